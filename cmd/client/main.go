@@ -2,17 +2,30 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"log"
+	"mime"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/dheeraj-sn/distributed-orchestrator/internal/config"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/models"
 	pb "github.com/dheeraj-sn/distributed-orchestrator/proto"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func main() {
@@ -23,15 +36,45 @@ func main() {
 	}
 
 	// CLI flags
-	mode := flag.String("mode", "submit", "Mode: submit or status")
+	mode := flag.String("mode", "submit", "Mode: submit, status, watch, history, cancel, list, logs, artifacts, upload, tasks, workers, cache or invalidate")
 	task := flag.String("task", "echo", "Task name")
 	args := flag.String("args", "hello", "Comma-separated arguments")
+	payload := flag.String("payload", "", "Structured job input as a JSON object")
+	priority := flag.Int("priority", 0, "Job priority (higher runs first)")
+	timeout := flag.Duration("timeout", 0, "How long the job may run (defaults to its task type's timeout)")
+	queue := flag.String("queue", "", "Only list jobs in this queue")
+	tenant := flag.String("tenant", "", "Tenant to submit the job for, or to list the jobs of")
+	cpu := flag.Float64("cpu", 0, "CPU cores the job needs, for bin-packing placement")
+	memory := flag.Int64("memory", 0, "Memory in MB the job needs, for bin-packing placement")
+	cacheable := flag.Bool("cacheable", false, "Reuse the result of an identical earlier job (defaults to the task type's setting)")
+	cacheKey := flag.String("key", "", "Cache key to list or invalidate")
+	concurrencyKey := flag.String("concurrency-key", "", "Keep jobs with the same key from running at the same time")
+	unique := flag.String("unique", "", "Submit only if no identical job is queued, active (queued or running), or was submitted within a duration such as 1h")
+	uniqueKey := flag.String("unique-key", "", "Key that identifies identical jobs with -unique (defaults to the task, args and payload)")
+	concurrencyLimit := flag.Int("concurrency-limit", 0, "How many jobs with -concurrency-key may run at once (default 1)")
+	all := flag.Bool("all", false, "Invalidate every cache entry, or list expired ones too")
 	jobID := flag.String("id", "", "Job ID to check status")
+	reason := flag.String("reason", "", "Cancellation reason")
+	statuses := flag.String("status", "", "Comma-separated statuses to list")
+	workerID := flag.String("worker", "", "Only list jobs assigned to this worker")
+	limit := flag.Int("limit", 20, "Jobs per page when listing (0 for all), or log lines to show")
+	offset := flag.Int("offset", 0, "Jobs to skip when listing, or log lines to skip")
+	follow := flag.Bool("follow", false, "Keep printing new log lines until the job finishes")
+	name := flag.String("name", "", "Artifact to download (lists the job's artifacts if empty), or the name to upload a file under")
+	out := flag.String("out", "", "File to save the artifact to (defaults to its name, - for stdout)")
+	file := flag.String("file", "", "File to upload as a job input")
+	inputs := flag.String("inputs", "", "Comma-separated input files: path=upload:<upload-id> or path=<job-id>/<artifact>")
+	output := flag.String("output", "text", "Output format for status, watch, history, list, tasks, workers and cache: text or json")
+	outputs := flag.String("outputs", "", "Comma-separated output files to keep as artifacts: path or path=<artifact>")
 
 	// Override scheduler address if passed via flag
 	addr := flag.String("addr", cfg.Client.SchedulerAddr, "Scheduler gRPC address")
 
 	flag.Parse()
+	if *output != "text" && *output != "json" {
+		log.Fatalf("Unknown -output format: %s", *output)
+	}
+	asJSON := *output == "json"
 
 	// Connect to the scheduler
 	conn, err := grpc.NewClient(*addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
@@ -48,13 +91,50 @@ func main() {
 	switch *mode {
 	case "submit":
 		req := &pb.JobRequest{
-			Task: *task,
-			Args: splitArgs(*args),
+			Task:     *task,
+			Args:     splitArgs(*args),
+			Priority: int32(*priority),
+			Tenant:   *tenant,
+			Outputs:  parseOutputs(*outputs),
+
+			ConcurrencyKey:   *concurrencyKey,
+			ConcurrencyLimit: int32(*concurrencyLimit),
+		}
+		if *timeout != 0 {
+			req.Timeout = durationpb.New(*timeout)
+		}
+		if *cpu != 0 || *memory != 0 {
+			req.Resources = &pb.Resources{Cpu: *cpu, MemoryMb: *memory}
+		}
+		if flagSet("cacheable") {
+			req.Cacheable = cacheable
+		}
+		if *unique != "" || *uniqueKey != "" {
+			if req.Unique, err = parseUnique(*unique, *uniqueKey); err != nil {
+				log.Fatalf("Invalid -unique: %v", err)
+			}
+		}
+		if req.Inputs, err = parseInputs(*inputs); err != nil {
+			log.Fatalf("Invalid -inputs: %v", err)
+		}
+		if *payload != "" {
+			req.Payload = &structpb.Struct{}
+			if err := protojson.Unmarshal([]byte(*payload), req.Payload); err != nil {
+				log.Fatalf("Invalid -payload: must be a JSON object: %v", err)
+			}
 		}
 		res, err := client.SubmitJob(ctx, req)
 		if err != nil {
 			log.Fatalf("Submit failed: %v", err)
 		}
+		if res.Duplicate {
+			fmt.Printf("🔁 Identical job already submitted. ID: %s\n", res.JobId)
+			break
+		}
+		if res.CacheHit {
+			fmt.Printf("♻️  Served from cache. ID: %s\n", res.JobId)
+			break
+		}
 		fmt.Printf("✅ Job submitted. ID: %s\n", res.JobId)
 
 	case "status":
@@ -65,16 +145,549 @@ func main() {
 		if err != nil {
 			log.Fatalf("Status check failed: %v", err)
 		}
+		if asJSON {
+			printJSON(res, true)
+			break
+		}
 		fmt.Printf("📦 Job Status: %s\n", res.Status)
 		if res.Result != "" {
 			fmt.Printf("📝 Result: %s\n", res.Result)
 		}
+		if res.Output != nil {
+			fmt.Printf("🧾 Output: %s\n", compactJSON(res.Output))
+		}
+		if d := res.Details; d != nil {
+			printDetails(d)
+		}
+
+	case "watch":
+		if *jobID == "" {
+			log.Fatal("Please provide a job ID using -id flag")
+		}
+		// Watching lasts until the job finishes, so don't use the request timeout
+		stream, err := client.WatchJob(context.Background(), &pb.JobStatusRequest{JobId: *jobID})
+		if err != nil {
+			log.Fatalf("Watch failed: %v", err)
+		}
+		for {
+			res, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				log.Fatalf("Watch failed: %v", err)
+			}
+			if asJSON {
+				// One object per line, so updates can be piped through jq
+				printJSON(res, false)
+				continue
+			}
+			fmt.Printf("%s  📦 %s\n", time.Now().Format(time.RFC3339), res.Status)
+			if res.Result != "" {
+				fmt.Printf("📝 Result: %s\n", res.Result)
+			}
+			if res.Output != nil {
+				fmt.Printf("🧾 Output: %s\n", compactJSON(res.Output))
+			}
+		}
+
+	case "history":
+		if *jobID == "" {
+			log.Fatal("Please provide a job ID using -id flag")
+		}
+		res, err := client.GetJobHistory(ctx, &pb.JobHistoryRequest{JobId: *jobID})
+		if err != nil {
+			log.Fatalf("History lookup failed: %v", err)
+		}
+		if asJSON {
+			printJSON(res, true)
+			break
+		}
+		for _, t := range res.Transitions {
+			from := t.From
+			if from == "" {
+				from = "-"
+			}
+			fmt.Printf("%s  %-13s → %-13s  %-20s %s\n",
+				t.Timestamp.AsTime().Local().Format(time.RFC3339), from, t.To, t.Actor, t.Reason)
+		}
+
+	case "cancel":
+		if *jobID == "" {
+			log.Fatal("Please provide a job ID using -id flag")
+		}
+		if _, err := client.CancelJob(ctx, &pb.CancelJobRequest{JobId: *jobID, Reason: *reason}); err != nil {
+			log.Fatalf("Cancel failed: %v", err)
+		}
+		fmt.Printf("🛑 Job cancelled: %s\n", *jobID)
+
+	case "list":
+		req := &pb.ListJobsRequest{
+			Statuses: splitArgs(*statuses),
+			WorkerId: *workerID,
+			Queue:    *queue,
+			Tenant:   *tenant,
+			Limit:    int32(*limit),
+			Offset:   int32(*offset),
+		}
+		// -task defaults to "echo" for submit; only filter when it was given
+		if flagSet("task") {
+			req.Task = *task
+		}
+		res, err := client.ListJobs(ctx, req)
+		if err != nil {
+			log.Fatalf("List failed: %v", err)
+		}
+		if asJSON {
+			printJSON(res, true)
+			break
+		}
+		for _, j := range res.Jobs {
+			d := j.Details
+			fmt.Printf("%s  %-13s %-10s %s\n", j.JobId, j.Status, d.GetWorkerId(), strings.Join(append([]string{d.GetTask()}, d.GetArgs()...), " "))
+		}
+		if res.NextOffset > 0 {
+			fmt.Printf("… more jobs: -offset %d\n", res.NextOffset)
+		}
+
+	case "logs":
+		if *jobID == "" {
+			log.Fatal("Please provide a job ID using -id flag")
+		}
+		if *follow {
+			// Following lasts until the job finishes, so don't use the request timeout
+			stream, err := client.TailJobLogs(context.Background(), &pb.JobLogsRequest{JobId: *jobID, Offset: int64(*offset)})
+			if err != nil {
+				log.Fatalf("Tail failed: %v", err)
+			}
+			for {
+				line, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					log.Fatalf("Tail failed: %v", err)
+				}
+				printLogLine(line)
+			}
+			break
+		}
+		// -limit defaults to a page of jobs; without it, print the whole log
+		want := 0
+		if flagSet("limit") {
+			want = *limit
+		}
+		req := &pb.JobLogsRequest{JobId: *jobID, Offset: int64(*offset)}
+		for printed := 0; want == 0 || printed < want; {
+			if want > 0 {
+				req.Limit = int32(want - printed)
+			}
+			res, err := client.GetJobLogs(ctx, req)
+			if err != nil {
+				log.Fatalf("Logs lookup failed: %v", err)
+			}
+			if len(res.Lines) == 0 {
+				break
+			}
+			for _, line := range res.Lines {
+				printLogLine(line)
+			}
+			printed += len(res.Lines)
+			req.Offset = res.NextOffset
+		}
+
+	case "artifacts":
+		if *jobID == "" {
+			log.Fatal("Please provide a job ID using -id flag")
+		}
+		if *name == "" {
+			res, err := client.ListArtifacts(ctx, &pb.ListArtifactsRequest{JobId: *jobID})
+			if err != nil {
+				log.Fatalf("Artifact listing failed: %v", err)
+			}
+			for _, a := range res.Artifacts {
+				fmt.Printf("%-30s %12d  %-24s %s\n", a.Name, a.Size, a.ContentType, a.CreatedAt.AsTime().Local().Format(time.RFC3339))
+			}
+			break
+		}
+		path := *out
+		if path == "" {
+			path = *name
+		}
+		// Downloads may be large, so don't use the request timeout
+		if err := downloadArtifact(context.Background(), client, *jobID, *name, path); err != nil {
+			log.Fatalf("Download failed: %v", err)
+		}
+
+	case "upload":
+		if *file == "" {
+			log.Fatal("Please provide a file to upload using -file flag")
+		}
+		// Uploads may be large, so don't use the request timeout
+		res, err := uploadInput(context.Background(), client, *file, *name)
+		if err != nil {
+			log.Fatalf("Upload failed: %v", err)
+		}
+		fmt.Printf("📤 Uploaded %s (%d bytes). Upload ID: %s\n", res.Artifact.Name, res.Artifact.Size, res.UploadId)
+
+	case "tasks":
+		res, err := client.ListTaskTypes(ctx, &pb.ListTaskTypesRequest{})
+		if err != nil {
+			log.Fatalf("Task type listing failed: %v", err)
+		}
+		if asJSON {
+			printJSON(res, true)
+			break
+		}
+		for _, t := range res.TaskTypes {
+			timeout := "none"
+			if t.Timeout != nil {
+				timeout = t.Timeout.AsDuration().String()
+			}
+			fmt.Printf("%-20s queue=%-12s timeout=%-8s attempts=%-3d %s\n", t.Name, t.Queue, timeout, t.MaxAttempts, t.Description)
+		}
+		if res.Strict {
+			fmt.Println("Other task types are rejected.")
+		}
+
+	case "workers":
+		res, err := client.ListWorkers(ctx, &pb.ListWorkersRequest{})
+		if err != nil {
+			log.Fatalf("Worker listing failed: %v", err)
+		}
+		if asJSON {
+			printJSON(res, true)
+			break
+		}
+		for _, w := range res.Workers {
+			state := "gone"
+			if w.Connected {
+				state = "connected"
+			}
+			var tasks []string
+			for _, t := range w.Tasks {
+				if t.Version != "" {
+					tasks = append(tasks, t.Task+"@"+t.Version)
+				} else {
+					tasks = append(tasks, t.Task)
+				}
+			}
+			if w.AnyTask {
+				tasks = append(tasks, "*")
+			}
+			var capacity string
+			if w.Capacity != nil {
+				capacity = "  capacity: " + formatResources(w.Capacity)
+			}
+			fmt.Printf("%-24s %-10s %-22s last seen %s  tasks: %s%s\n",
+				w.WorkerId, state, w.Host, w.LastSeen.AsTime().Local().Format(time.RFC3339), strings.Join(tasks, ", "), capacity)
+		}
+
+	case "cache":
+		req := &pb.ListCacheEntriesRequest{Key: *cacheKey, IncludeExpired: *all}
+		if flagSet("task") {
+			req.Task = *task
+		}
+		res, err := client.ListCacheEntries(ctx, req)
+		if err != nil {
+			log.Fatalf("Cache listing failed: %v", err)
+		}
+		if asJSON {
+			printJSON(res, true)
+			break
+		}
+		for _, e := range res.Entries {
+			expires := "never"
+			if e.ExpiresAt != nil {
+				expires = e.ExpiresAt.AsTime().Local().Format(time.RFC3339)
+			}
+			if e.Expired {
+				expires += " (expired)"
+			}
+			fmt.Printf("%s  %-16s job %s  hits %-4d expires %s\n", e.Key, e.Task, e.JobId, e.Hits, expires)
+		}
+
+	case "invalidate":
+		req := &pb.InvalidateCacheRequest{Key: *cacheKey, All: *all}
+		if flagSet("task") {
+			req.Task = *task
+		}
+		res, err := client.InvalidateCache(ctx, req)
+		if err != nil {
+			log.Fatalf("Invalidation failed: %v", err)
+		}
+		fmt.Printf("🗑️  Invalidated %d cache entries\n", res.Invalidated)
 
 	default:
 		log.Fatalf("Unknown mode: %s", *mode)
 	}
 }
 
+// flagSet reports whether the named flag was given on the command line.
+func flagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// downloadArtifact saves an artifact to path, or stdout for "-", and
+// checks it against the checksum the scheduler recorded.
+func downloadArtifact(ctx context.Context, client pb.OrchestratorClient, jobID, name, path string) error {
+	stream, err := client.DownloadArtifact(ctx, &pb.DownloadArtifactRequest{JobId: jobID, Name: name})
+	if err != nil {
+		return err
+	}
+	var (
+		info *pb.ArtifactInfo
+		w    io.Writer
+		f    *os.File
+		hash = sha256.New()
+	)
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if f != nil {
+				f.Close()
+				os.Remove(path)
+			}
+			return err
+		}
+		if info == nil {
+			info = msg.Info
+			if path == "-" {
+				w = os.Stdout
+			} else {
+				if f, err = os.Create(path); err != nil {
+					return err
+				}
+				w = f
+			}
+			w = io.MultiWriter(w, hash)
+		}
+		if _, err := w.Write(msg.Data); err != nil {
+			return err
+		}
+	}
+	if f != nil {
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+	if info == nil {
+		return fmt.Errorf("empty response")
+	}
+	if sum := hex.EncodeToString(hash.Sum(nil)); sum != info.Sha256 {
+		return fmt.Errorf("checksum mismatch: got %s, want %s", sum, info.Sha256)
+	}
+	if path != "-" {
+		fmt.Printf("📥 Saved %s (%d bytes) to %s\n", info.Name, info.Size, path)
+	}
+	return nil
+}
+
+// uploadInput streams a file to the scheduler for jobs to take as an
+// input, under name or else the file's base name.
+func uploadInput(ctx context.Context, client pb.OrchestratorClient, path, name string) (*pb.UploadInputResponse, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if name == "" {
+		name = filepath.Base(path)
+	}
+
+	stream, err := client.UploadInput(ctx)
+	if err != nil {
+		return nil, err
+	}
+	msg := &pb.ArtifactChunk{Header: &pb.ArtifactHeader{
+		Name:        name,
+		ContentType: mime.TypeByExtension(filepath.Ext(path)),
+	}}
+	buf := make([]byte, 256<<10)
+	for {
+		n, err := io.ReadFull(f, buf)
+		if n > 0 || msg.Header != nil {
+			msg.Data = buf[:n]
+			if err := stream.Send(msg); err != nil {
+				break // the reason comes with CloseAndRecv
+			}
+			msg = &pb.ArtifactChunk{}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return stream.CloseAndRecv()
+}
+
+// parseInputs parses -inputs entries of the form path=upload:<upload-id>
+// or path=<job-id>/<artifact>.
+func parseInputs(raw string) ([]*pb.JobInput, error) {
+	var inputs []*pb.JobInput
+	for _, entry := range splitAndTrim(raw, ",") {
+		path, source, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("%q: want path=source", entry)
+		}
+		in := &pb.JobInput{Path: path}
+		if id, ok := strings.CutPrefix(source, "upload:"); ok {
+			in.Source = &pb.JobInput_UploadId{UploadId: id}
+		} else if job, art, ok := strings.Cut(source, "/"); ok {
+			in.Source = &pb.JobInput_Artifact{Artifact: &pb.ArtifactRef{JobId: job, Name: art}}
+		} else {
+			return nil, fmt.Errorf("%q: source must be upload:<upload-id> or <job-id>/<artifact>", entry)
+		}
+		inputs = append(inputs, in)
+	}
+	return inputs, nil
+}
+
+// parseOutputs parses -outputs entries of the form path or path=<artifact>.
+func parseOutputs(raw string) []*pb.JobOutput {
+	var outputs []*pb.JobOutput
+	for _, entry := range splitAndTrim(raw, ",") {
+		path, artifact, _ := strings.Cut(entry, "=")
+		outputs = append(outputs, &pb.JobOutput{Path: path, Artifact: artifact})
+	}
+	return outputs
+}
+
+// parseUnique parses -unique: queued (the default), active, or the
+// duration of a window.
+func parseUnique(scope, key string) (*pb.UniquePolicy, error) {
+	policy := &pb.UniquePolicy{Key: key}
+	switch scope {
+	case "", "queued":
+		policy.Scope = pb.UniqueScope_UNIQUE_SCOPE_QUEUED
+	case "active":
+		policy.Scope = pb.UniqueScope_UNIQUE_SCOPE_ACTIVE
+	default:
+		window, err := time.ParseDuration(scope)
+		if err != nil {
+			return nil, fmt.Errorf("%q: want queued, active or a duration", scope)
+		}
+		policy.Scope = pb.UniqueScope_UNIQUE_SCOPE_WINDOW
+		policy.Window = durationpb.New(window)
+	}
+	return policy, nil
+}
+
+// formatResources prints the set fields of r, e.g. "2 CPU, 512 MB".
+func formatResources(r *pb.Resources) string {
+	return models.Resources{CPU: r.Cpu, MemoryMB: r.MemoryMb}.String()
+}
+
+func printDetails(d *pb.JobDetails) {
+	fmt.Printf("   Task:      %s %s\n", d.Task, strings.Join(d.Args, " "))
+	if d.Payload != nil {
+		fmt.Printf("   Payload:   %s\n", compactJSON(d.Payload))
+	}
+	fmt.Printf("   Priority:  %d\n", d.Priority)
+	if d.Queue != "" {
+		fmt.Printf("   Queue:     %s\n", d.Queue)
+	}
+	if d.Tenant != "" {
+		fmt.Printf("   Tenant:    %s\n", d.Tenant)
+	}
+	if d.Timeout != nil {
+		fmt.Printf("   Timeout:   %s\n", d.Timeout.AsDuration())
+	}
+	fmt.Printf("   Attempt:   %d\n", d.Attempt)
+	if d.WorkerId != "" {
+		fmt.Printf("   Worker:    %s\n", d.WorkerId)
+	}
+	printTime("Created:", d.CreatedAt)
+	printTime("Queued:", d.QueuedAt)
+	printTime("Started:", d.StartedAt)
+	printTime("Finished:", d.FinishedAt)
+	if d.CacheHit {
+		fmt.Printf("♻️  Cached:    result of job %s\n", d.CachedFrom)
+	} else if d.CacheKey != "" {
+		fmt.Printf("   Cache key: %s\n", d.CacheKey)
+	}
+	if d.UniqueKey != "" {
+		fmt.Printf("   Unique:    %s\n", d.UniqueKey)
+	}
+	if d.ConcurrencyKey != "" {
+		fmt.Printf("   Exclusive: %s (%d at a time)\n", d.ConcurrencyKey, max(d.ConcurrencyLimit, 1))
+	}
+	if d.Resources != nil {
+		fmt.Printf("   Resources: %s\n", formatResources(d.Resources))
+	}
+	if d.WaitingReason != "" {
+		fmt.Printf("⏳ Waiting:   %s\n", d.WaitingReason)
+	}
+	if d.QueueDuration.AsDuration() > 0 {
+		fmt.Printf("   Waited:    %s\n", d.QueueDuration.AsDuration())
+	}
+	if d.RunDuration.AsDuration() > 0 {
+		fmt.Printf("   Ran:       %s\n", d.RunDuration.AsDuration())
+	}
+	for _, in := range d.Inputs {
+		source := "upload:" + in.GetUploadId()
+		if ref := in.GetArtifact(); ref != nil {
+			source = ref.JobId + "/" + ref.Name
+		}
+		fmt.Printf("   Input:     %s ← %s\n", in.Path, source)
+	}
+	for _, o := range d.Outputs {
+		name := o.Artifact
+		if name == "" {
+			name = filepath.Base(o.Path)
+		}
+		fmt.Printf("   Output:    %s → %s\n", o.Path, name)
+	}
+	if d.Error != "" {
+		fmt.Printf("❌ Error:     %s (exit code %d)\n", d.Error, d.ExitCode)
+	}
+}
+
+// printJSON prints a response in protobuf's JSON mapping, indented or on a
+// single line.
+func printJSON(m proto.Message, indent bool) {
+	var opts protojson.MarshalOptions
+	if indent {
+		opts.Multiline, opts.Indent = true, "  "
+	}
+	b, err := opts.Marshal(m)
+	if err != nil {
+		log.Fatalf("Encode JSON: %v", err)
+	}
+	fmt.Println(string(b))
+}
+
+// compactJSON formats a structured output on one line.
+func compactJSON(s *structpb.Struct) string {
+	b, err := json.Marshal(s.AsMap())
+	if err != nil {
+		return s.String()
+	}
+	return string(b)
+}
+
+func printLogLine(l *pb.LogLine) {
+	fmt.Printf("%s  %s\n", l.Timestamp.AsTime().Local().Format(time.RFC3339), l.Message)
+}
+
+func printTime(label string, ts *timestamppb.Timestamp) {
+	if ts == nil {
+		return
+	}
+	fmt.Printf("   %-10s %s\n", label, ts.AsTime().Local().Format(time.RFC3339))
+}
+
 func splitArgs(raw string) []string {
 	if raw == "" {
 		return []string{}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/storage"
+	pb "github.com/dheeraj-sn/distributed-orchestrator/proto"
+)
+
+const healthCheckInterval = 10 * time.Second
+
+// monitorStore pings the job store every healthCheckInterval and reports
+// the result through the gRPC health service until ctx is done.
+func monitorStore(ctx context.Context, store storage.HealthChecker, srv *health.Server, logger *zap.Logger) {
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+	healthy := true
+	for {
+		pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		err := store.Ping(pingCtx)
+		cancel()
+
+		status := healthpb.HealthCheckResponse_SERVING
+		if err != nil {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+		srv.SetServingStatus("", status)
+		srv.SetServingStatus(pb.Orchestrator_ServiceDesc.ServiceName, status)
+		if err != nil && healthy {
+			logger.Error("Job store unhealthy", zap.Error(err))
+		} else if err == nil && !healthy {
+			logger.Info("Job store healthy again")
+		}
+		healthy = err == nil
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
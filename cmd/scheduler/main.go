@@ -1,16 +1,24 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net"
+	"os"
 
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/blobstore"
 	"github.com/dheeraj-sn/distributed-orchestrator/internal/config"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/logsink"
 	"github.com/dheeraj-sn/distributed-orchestrator/internal/scheduler"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/storage"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/worker"
 	pb "github.com/dheeraj-sn/distributed-orchestrator/proto"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func main() {
@@ -39,6 +47,14 @@ func main() {
 	}
 	defer logger.Sync()
 
+	// `scheduler migrate ...` manages the schema and exits
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(cfg, logger, os.Args[2:]); err != nil {
+			logger.Fatal("Migration failed", zap.Error(err))
+		}
+		return
+	}
+
 	// Start TCP listener
 	lis, err := net.Listen("tcp", cfg.Scheduler.Host)
 	if err != nil {
@@ -47,13 +63,81 @@ func main() {
 
 	// gRPC server setup
 	grpcServer := grpc.NewServer()
+	healthSrv := health.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthSrv)
+
+	// Open the job store selected by storage.backend
+	store, err := storage.Open(cfg.Storage, logger)
+	if err != nil {
+		logger.Fatal("Failed to open job store", zap.String("backend", cfg.Storage.Backend), zap.Error(err))
+	}
+	logger.Info("Job store opened", zap.String("backend", cfg.Storage.Backend))
+
+	// Make the memory backend recoverable with a write-ahead log
+	if cfg.Storage.WAL.Enabled {
+		mem, ok := store.(*storage.MemoryStore)
+		if !ok {
+			logger.Fatal("storage.wal requires the memory backend", zap.String("backend", cfg.Storage.Backend))
+		}
+		state, err := scheduler.OpenState(mem, cfg.Storage.WAL, logger)
+		if err != nil {
+			logger.Fatal("Failed to recover scheduler state", zap.Error(err))
+		}
+		go state.Run(context.Background())
+		store = state
+	}
+	defer store.Close()
+
+	// Send job logs to the sinks selected by job_logs.sinks
+	logSinks, err := logsink.Open(cfg.JobLogs, store, logger)
+	if err != nil {
+		logger.Fatal("Failed to open job log sinks", zap.Error(err))
+	}
+	defer logSinks.Close()
+	go logSinks.RunRetention(context.Background(), cfg.JobLogs.PruneInterval)
+
+	// Keep job artifacts in the blob store selected by artifacts.backend
+	blobs, err := blobstore.Open(cfg.Artifacts)
+	if err != nil {
+		logger.Fatal("Failed to open artifact store", zap.String("backend", cfg.Artifacts.Backend), zap.Error(err))
+	}
 
 	// Initialize scheduler logic and register gRPC service
-	srv := scheduler.NewSchedulerServer(logger)
+	srv, err := scheduler.NewSchedulerServer(cfg, store, logSinks, blobs, logger)
+	if err != nil {
+		logger.Fatal("Failed to initialize scheduler", zap.Error(err))
+	}
 	pb.RegisterOrchestratorServer(grpcServer, srv)
 
-	// Start job dispatcher loop in background
-	srv.Dispatcher.Run()
+	// Requeue jobs that were waiting before a restart
+	if err := srv.Dispatcher.Recover(); err != nil {
+		logger.Fatal("Failed to recover jobs", zap.Error(err))
+	}
+
+	// Report database reachability through the gRPC health service
+	if hc, ok := store.(storage.HealthChecker); ok {
+		go monitorStore(context.Background(), hc, healthSrv, logger)
+	}
+
+	// Wake waiting PullJob calls and watchers on changes made by other replicas
+	if src, ok := store.(storage.EventSource); ok {
+		go src.ListenEvents(context.Background(), srv.Events.Publish)
+	}
+
+	// Expose limit usage for Prometheus; metrics_port 0 turns it off
+	if cfg.Scheduler.MetricsPort != 0 {
+		go serveMetrics(cfg.Scheduler.MetricsPort, srv.Limits, logger)
+	}
+
+	// Reclaim jobs from workers that stopped heartbeating
+	go srv.Dispatcher.ReapLeases(context.Background(), cfg.Scheduler.LeaseCheckInterval)
+
+	// Optionally run jobs in-process instead of (or alongside) remote workers
+	if lc := cfg.Scheduler.LocalExecutor; lc.Enabled {
+		local := scheduler.NewLocalExecutor(srv.Dispatcher, srv.Artifacts, worker.NewDefaultRegistry(), lc.Concurrency, lc.WorkDir, logger)
+		go local.Run(context.Background())
+		logger.Info("Local executor enabled", zap.Int("concurrency", lc.Concurrency))
+	}
 
 	logger.Info("Scheduler listening", zap.String("addr", cfg.Scheduler.Host))
 
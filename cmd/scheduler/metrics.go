@@ -0,0 +1,27 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/scheduler"
+)
+
+// serveMetrics exposes the usage of the configured limits at /metrics in
+// the Prometheus text format.
+func serveMetrics(port int, limits *scheduler.Limiter, logger *zap.Logger) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := limits.WriteMetrics(w); err != nil {
+			logger.Warn("Failed to write metrics", zap.Error(err))
+		}
+	})
+	addr := ":" + strconv.Itoa(port)
+	logger.Info("Metrics listening", zap.String("addr", addr))
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		logger.Error("Metrics server stopped", zap.Error(err))
+	}
+}
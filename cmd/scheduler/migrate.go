@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/config"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/storage"
+)
+
+// runMigrate implements `scheduler migrate up|down [n]|status` against the
+// configured Postgres database.
+func runMigrate(cfg *config.Config, logger *zap.Logger, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: scheduler migrate up|down [n]|status")
+	}
+	migrator, err := storage.OpenMigrator(cfg.Storage.Postgres, logger)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Applied %d migration(s); schema is at version %d\n", applied, migrator.Latest())
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("invalid step count %q", args[1])
+			}
+		}
+		return migrator.Down(ctx, steps)
+
+	case "status":
+		current, statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Schema version %d (latest %d)\n", current, migrator.Latest())
+		for _, s := range statuses {
+			mark := " "
+			if s.Applied {
+				mark = "x"
+			}
+			fmt.Printf("  [%s] %04d %s\n", mark, s.Version, s.Name)
+		}
+
+	default:
+		return fmt.Errorf("unknown migrate command %q", args[0])
+	}
+	return nil
+}
@@ -5,19 +5,22 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	pb "github.com/dheeraj-sn/distributed-orchestrator/proto"
 )
 
 type job struct {
-	ID     string
-	Status string
-	Result string
+	ID      string
+	Status  string
+	Result  string
+	Details *pb.JobDetails
 }
 
 type model struct {
@@ -25,6 +28,7 @@ type model struct {
 	cursor int
 	err    error
 	filter string
+	detail bool
 }
 
 func initialModel() model {
@@ -54,9 +58,10 @@ func fetchJobs() ([]job, error) {
 	var jobs []job
 	for _, j := range res.Jobs {
 		jobs = append(jobs, job{
-			ID:     j.JobId,
-			Status: j.Status,
-			Result: j.Result,
+			ID:      j.JobId,
+			Status:  j.Status,
+			Result:  j.Result,
+			Details: j.Details,
 		})
 	}
 	return jobs, nil
@@ -87,6 +92,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.cursor = 0
 		case "/":
 			m.filter = "queued" // hardcoded demo: filter queued jobs
+		case "enter":
+			m.detail = !m.detail
+		case "esc":
+			m.detail = false
 		}
 	}
 	return m, nil
@@ -109,6 +118,9 @@ func (m model) View() string {
 		}
 		filteredJobs = temp
 	}
+	if m.detail && m.cursor < len(filteredJobs) {
+		return output + detailView(filteredJobs[m.cursor]) + "\n[enter/esc] Back  [q] Quit"
+	}
 	for i, j := range filteredJobs {
 		cursor := " "
 		if i == m.cursor {
@@ -116,10 +128,44 @@ func (m model) View() string {
 		}
 		output += fmt.Sprintf("%s %s | %s | %s\n", cursor, j.ID, j.Status, j.Result)
 	}
-	output += "\n[↑↓] Navigate  [r] Refresh  [/] Filter queued  [q] Quit"
+	output += "\n[↑↓] Navigate  [enter] Details  [r] Refresh  [/] Filter queued  [q] Quit"
 	return output
 }
 
+func detailView(j job) string {
+	label := lipgloss.NewStyle().Bold(true).Width(12)
+	row := func(name, value string) string {
+		if value == "" {
+			value = "-"
+		}
+		return label.Render(name) + value + "\n"
+	}
+	ts := func(t *timestamppb.Timestamp) string {
+		if t == nil {
+			return ""
+		}
+		return t.AsTime().Local().Format(time.RFC3339)
+	}
+
+	out := row("Job", j.ID) + row("Status", j.Status) + row("Result", j.Result)
+	d := j.Details
+	if d == nil {
+		return out
+	}
+	out += row("Task", strings.TrimSpace(d.Task+" "+strings.Join(d.Args, " ")))
+	out += row("Attempt", fmt.Sprint(d.Attempt))
+	out += row("Worker", d.WorkerId)
+	out += row("Created", ts(d.CreatedAt))
+	out += row("Queued", ts(d.QueuedAt))
+	out += row("Started", ts(d.StartedAt))
+	out += row("Finished", ts(d.FinishedAt))
+	out += row("Waited", d.QueueDuration.AsDuration().String())
+	out += row("Ran", d.RunDuration.AsDuration().String())
+	out += row("Exit code", fmt.Sprint(d.ExitCode))
+	out += row("Error", d.Error)
+	return out
+}
+
 func main() {
 	if err := tea.NewProgram(initialModel()).Start(); err != nil {
 		log.Fatal(err)
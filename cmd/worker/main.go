@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/dheeraj-sn/distributed-orchestrator/internal/config"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/models"
 	"github.com/dheeraj-sn/distributed-orchestrator/internal/worker"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -57,22 +58,29 @@ func main() {
 	defer conn.Close()
 
 	// Create and start worker
-	w := worker.NewWorker(
+	w, err := worker.NewWorker(
 		workerID,
 		cfg.Worker.Host,
 		conn,
 		logger,
 		cfg.Worker.Concurrency,
+		cfg.Worker.WorkDir,
+		cfg.Worker.LogShipping,
 	)
+	if err != nil {
+		logger.Fatal("Failed to create worker", zap.Error(err))
+	}
 
+	w.Capacity = models.Resources{CPU: cfg.Worker.Capacity.CPU, MemoryMB: cfg.Worker.Capacity.MemoryMB}
 	if err := w.Register(); err != nil {
 		logger.Fatal("Worker registration failed", zap.Error(err))
 	}
 
-	w.StartHeartbeat(10)
-	w.StartExecutorLoop(3)
+	w.Logs.Start()
+	w.StartHeartbeat(10 * time.Second)
+	w.StartExecutorLoop(3 * time.Second)
 
-	select {}
+	worker.WaitForShutdown(w)
 }
 
 func generateWorkerID() string {
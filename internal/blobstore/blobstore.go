@@ -0,0 +1,39 @@
+// Package blobstore keeps opaque blobs, such as job artifacts, outside the
+// job store.
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/config"
+)
+
+// ErrNotFound is returned when a key has no blob.
+var ErrNotFound = errors.New("blob not found")
+
+// Store is a flat namespace of blobs addressed by slash-separated keys.
+// Put replaces any blob at the key; readers never see a partial blob.
+type Store interface {
+	// Put stores size bytes read from r at key.
+	Put(ctx context.Context, key string, r io.Reader, size int64) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// List returns the keys starting with prefix, in lexical order.
+	List(ctx context.Context, prefix string) ([]string, error)
+	// Delete removes a blob; deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// Open returns the store selected by cfg.Backend.
+func Open(cfg config.ArtifactsConfig) (Store, error) {
+	switch cfg.Backend {
+	case "", "filesystem":
+		return NewFSStore(cfg.Filesystem.Dir)
+	case "s3":
+		return NewS3Store(cfg.S3)
+	default:
+		return nil, fmt.Errorf("unknown artifact backend %q", cfg.Backend)
+	}
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -14,12 +15,29 @@ func Load() (*Config, error) {
 	// Defaults for scheduler
 	v.SetDefault("scheduler.host", "0.0.0.0:50051")
 	v.SetDefault("scheduler.metrics_port", 9090)
+	v.SetDefault("scheduler.lease_duration", "1m")
+	v.SetDefault("scheduler.lease_check_interval", "10s")
+	v.SetDefault("scheduler.max_pull_wait", "30s")
+	v.SetDefault("scheduler.event_poll_interval", "2s")
+	v.SetDefault("scheduler.policy", "priority")
+	v.SetDefault("scheduler.local_executor.enabled", false)
+	v.SetDefault("scheduler.local_executor.concurrency", 2)
+	v.SetDefault("scheduler.local_executor.work_dir", "work")
 
 	// Defaults for worker
 	v.SetDefault("worker.host", "0.0.0.0:50052")
 	v.SetDefault("worker.metrics_port", 9091)
 	v.SetDefault("worker.concurrency", 4)
 	v.SetDefault("worker.worker_id", "worker-default")
+	v.SetDefault("worker.work_dir", "work")
+	v.SetDefault("worker.capacity.cpu", 0)
+	v.SetDefault("worker.capacity.memory_mb", 0)
+	v.SetDefault("worker.log_shipping.batch_size", 500)
+	v.SetDefault("worker.log_shipping.flush_interval", "200ms")
+	v.SetDefault("worker.log_shipping.max_line_bytes", 64<<10)
+	v.SetDefault("worker.log_shipping.max_buffer_bytes", 4<<20)
+	v.SetDefault("worker.log_shipping.spool_dir", "log-spool")
+	v.SetDefault("worker.log_shipping.max_spool_bytes", 256<<20)
 
 	// Defaults for client
 	v.SetDefault("client.scheduler_addr", "localhost:50051")
@@ -35,10 +53,81 @@ func Load() (*Config, error) {
 
 	// Storage defaults
 	v.SetDefault("storage.backend", "memory")
+	v.SetDefault("storage.postgres.host", "localhost")
+	v.SetDefault("storage.postgres.port", 5432)
+	v.SetDefault("storage.postgres.database", "orchestrator")
+	v.SetDefault("storage.postgres.user", "postgres")
+	v.SetDefault("storage.postgres.password", "")
+	v.SetDefault("storage.postgres.sslmode", "disable")
+	v.SetDefault("storage.postgres.sslrootcert", "")
+	v.SetDefault("storage.postgres.sslcert", "")
+	v.SetDefault("storage.postgres.sslkey", "")
+	v.SetDefault("storage.postgres.max_open_conns", 10)
+	v.SetDefault("storage.postgres.max_idle_conns", 5)
+	v.SetDefault("storage.postgres.conn_max_lifetime", "30m")
+	v.SetDefault("storage.postgres.conn_max_idle_time", "5m")
+	v.SetDefault("storage.postgres.startup_timeout", "1m")
+	v.SetDefault("storage.postgres.auto_migrate", true)
+	v.SetDefault("storage.bolt.path", "orchestrator.db")
+	v.SetDefault("storage.wal.enabled", false)
+	v.SetDefault("storage.wal.dir", "data")
+	v.SetDefault("storage.wal.fsync", "interval")
+	v.SetDefault("storage.wal.fsync_interval", "1s")
+	v.SetDefault("storage.wal.snapshot_interval", "5m")
+
+	// Job log sink defaults
+	v.SetDefault("job_logs.sinks", []string{"database"})
+	v.SetDefault("job_logs.database.max_lines_per_job", 0)
+	v.SetDefault("job_logs.database.max_age", "0s")
+	v.SetDefault("job_logs.file.dir", "logs/jobs")
+	v.SetDefault("job_logs.file.max_file_bytes", 10<<20)
+	v.SetDefault("job_logs.file.max_files_per_job", 5)
+	v.SetDefault("job_logs.file.max_age", "168h")
+	v.SetDefault("job_logs.prune_interval", "10m")
+
+	// Artifact storage defaults
+	v.SetDefault("artifacts.backend", "filesystem")
+	v.SetDefault("artifacts.max_bytes", 1<<30)
+	v.SetDefault("artifacts.filesystem.dir", "artifacts")
+	v.SetDefault("artifacts.s3.endpoint", "")
+	v.SetDefault("artifacts.s3.region", "us-east-1")
+	v.SetDefault("artifacts.s3.bucket", "")
+	v.SetDefault("artifacts.s3.prefix", "")
+	v.SetDefault("artifacts.s3.access_key_id", "")
+	v.SetDefault("artifacts.s3.secret_access_key", "")
+	v.SetDefault("artifacts.s3.session_token", "")
+	v.SetDefault("artifacts.s3.path_style", false)
+
+	// Task catalog defaults
+	v.SetDefault("task_types.strict", false)
+
+	// Result cache defaults
+	v.SetDefault("cache.ttl", "24h")
+
+	// Fair-share defaults
+	v.SetDefault("fair_share.default_weight", 1)
 
 	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	v.AutomaticEnv() // enable env var overrides, e.g. WORKER_CONCURRENCY
 
+	// Also accept the variables the postgres image, libpq and the AWS
+	// tools use
+	for key, env := range map[string]string{
+		"storage.postgres.host":     "POSTGRES_HOST",
+		"storage.postgres.port":     "POSTGRES_PORT",
+		"storage.postgres.database": "POSTGRES_DB",
+		"storage.postgres.user":     "POSTGRES_USER",
+		"storage.postgres.password": "POSTGRES_PASSWORD",
+		"storage.postgres.sslmode":  "PGSSLMODE",
+
+		"artifacts.s3.region":            "AWS_REGION",
+		"artifacts.s3.access_key_id":     "AWS_ACCESS_KEY_ID",
+		"artifacts.s3.secret_access_key": "AWS_SECRET_ACCESS_KEY",
+		"artifacts.s3.session_token":     "AWS_SESSION_TOKEN",
+	} {
+		v.BindEnv(key, strings.ToUpper(strings.ReplaceAll(key, ".", "_")), env)
+	}
+
 	// Read config file if set
 	configPath := v.GetString("CONFIG_PATH")
 	if configPath != "" {
@@ -63,6 +152,77 @@ func Load() (*Config, error) {
 	if err != nil {
 		return nil, fmt.Errorf("invalid retry.max_backoff: %w", err)
 	}
+	cfg.Scheduler.LeaseDuration, err = time.ParseDuration(v.GetString("scheduler.lease_duration"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid scheduler.lease_duration: %w", err)
+	}
+	cfg.Scheduler.LeaseCheckInterval, err = time.ParseDuration(v.GetString("scheduler.lease_check_interval"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid scheduler.lease_check_interval: %w", err)
+	}
+	if cfg.Scheduler.LeaseDuration <= 0 || cfg.Scheduler.LeaseCheckInterval <= 0 {
+		return nil, errors.New("scheduler.lease_duration and scheduler.lease_check_interval must be positive")
+	}
+	cfg.Scheduler.MaxPullWait, err = time.ParseDuration(v.GetString("scheduler.max_pull_wait"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid scheduler.max_pull_wait: %w", err)
+	}
+	cfg.Scheduler.EventPollInterval, err = time.ParseDuration(v.GetString("scheduler.event_poll_interval"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid scheduler.event_poll_interval: %w", err)
+	}
+	if cfg.Scheduler.MaxPullWait <= 0 || cfg.Scheduler.EventPollInterval <= 0 {
+		return nil, errors.New("scheduler.max_pull_wait and scheduler.event_poll_interval must be positive")
+	}
+	cfg.Worker.LogShipping.FlushInterval, err = time.ParseDuration(v.GetString("worker.log_shipping.flush_interval"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid worker.log_shipping.flush_interval: %w", err)
+	}
+	if cfg.Worker.LogShipping.FlushInterval <= 0 {
+		return nil, errors.New("worker.log_shipping.flush_interval must be positive")
+	}
+	cfg.Storage.Postgres.ConnMaxLifetime, err = time.ParseDuration(v.GetString("storage.postgres.conn_max_lifetime"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid storage.postgres.conn_max_lifetime: %w", err)
+	}
+	cfg.Storage.Postgres.ConnMaxIdleTime, err = time.ParseDuration(v.GetString("storage.postgres.conn_max_idle_time"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid storage.postgres.conn_max_idle_time: %w", err)
+	}
+	cfg.Storage.Postgres.StartupTimeout, err = time.ParseDuration(v.GetString("storage.postgres.startup_timeout"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid storage.postgres.startup_timeout: %w", err)
+	}
+	cfg.JobLogs.Database.MaxAge, err = time.ParseDuration(v.GetString("job_logs.database.max_age"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid job_logs.database.max_age: %w", err)
+	}
+	cfg.JobLogs.File.MaxAge, err = time.ParseDuration(v.GetString("job_logs.file.max_age"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid job_logs.file.max_age: %w", err)
+	}
+	cfg.JobLogs.PruneInterval, err = time.ParseDuration(v.GetString("job_logs.prune_interval"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid job_logs.prune_interval: %w", err)
+	}
+	if cfg.JobLogs.PruneInterval <= 0 {
+		return nil, errors.New("job_logs.prune_interval must be positive")
+	}
+	cfg.Storage.WAL.FsyncInterval, err = time.ParseDuration(v.GetString("storage.wal.fsync_interval"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid storage.wal.fsync_interval: %w", err)
+	}
+	cfg.Storage.WAL.SnapshotInterval, err = time.ParseDuration(v.GetString("storage.wal.snapshot_interval"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid storage.wal.snapshot_interval: %w", err)
+	}
+	if cfg.Storage.WAL.FsyncInterval <= 0 || cfg.Storage.WAL.SnapshotInterval <= 0 {
+		return nil, errors.New("storage.wal.fsync_interval and storage.wal.snapshot_interval must be positive")
+	}
+	cfg.Cache.TTL, err = time.ParseDuration(v.GetString("cache.ttl"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid cache.ttl: %w", err)
+	}
 
 	return &cfg, nil
 }
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadDefaults(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Scheduler.MaxPullWait <= 0 || cfg.Scheduler.EventPollInterval <= 0 {
+		t.Fatalf("max_pull_wait = %v, event_poll_interval = %v", cfg.Scheduler.MaxPullWait, cfg.Scheduler.EventPollInterval)
+	}
+}
+
+func TestLoadRejectsNonPositiveIntervals(t *testing.T) {
+	cases := []struct {
+		env, value, want string
+	}{
+		{"SCHEDULER_MAX_PULL_WAIT", "0s", "scheduler.max_pull_wait"},
+		{"SCHEDULER_MAX_PULL_WAIT", "-1s", "scheduler.max_pull_wait"},
+		{"SCHEDULER_EVENT_POLL_INTERVAL", "0s", "scheduler.event_poll_interval"},
+		{"SCHEDULER_EVENT_POLL_INTERVAL", "-1s", "scheduler.event_poll_interval"},
+	}
+	for _, c := range cases {
+		t.Run(c.env+"="+c.value, func(t *testing.T) {
+			t.Setenv(c.env, c.value)
+			_, err := Load()
+			if err == nil || !strings.Contains(err.Error(), c.want) {
+				t.Fatalf("err = %v, want one naming %s", err, c.want)
+			}
+		})
+	}
+}
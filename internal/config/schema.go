@@ -5,8 +5,33 @@ import (
 )
 
 type SchedulerConfig struct {
-	Host        string `mapstructure:"host"`
-	MetricsPort int    `mapstructure:"metrics_port"`
+	Host          string              `mapstructure:"host"`
+	MetricsPort   int                 `mapstructure:"metrics_port"`
+	LocalExecutor LocalExecutorConfig `mapstructure:"local_executor"`
+
+	// LeaseDuration is how long a worker may hold a job without a
+	// heartbeat before the job is reclaimed.
+	LeaseDuration      time.Duration `mapstructure:"lease_duration"`
+	LeaseCheckInterval time.Duration `mapstructure:"lease_check_interval"`
+
+	// Policy picks which ready job a worker gets: fifo, priority,
+	// fair_share or bin_packing.
+	Policy string `mapstructure:"policy"`
+
+	// MaxPullWait caps how long a PullJob call may block waiting for work.
+	MaxPullWait time.Duration `mapstructure:"max_pull_wait"`
+	// EventPollInterval is how often waiters re-check for work without a
+	// job event, covering events lost while the listener reconnects.
+	EventPollInterval time.Duration `mapstructure:"event_poll_interval"`
+}
+
+// LocalExecutorConfig controls the embedded executor that runs jobs inside
+// the scheduler process for single-binary development.
+type LocalExecutorConfig struct {
+	Enabled     bool `mapstructure:"enabled"`
+	Concurrency int  `mapstructure:"concurrency"`
+	// WorkDir holds the per-job working directories.
+	WorkDir string `mapstructure:"work_dir"`
 }
 
 type WorkerConfig struct {
@@ -14,6 +39,38 @@ type WorkerConfig struct {
 	MetricsPort int    `mapstructure:"metrics_port"`
 	Concurrency int    `mapstructure:"concurrency"`
 	WorkerID    string `mapstructure:"worker_id"`
+	// WorkDir holds the per-job working directories that input files are
+	// staged into and outputs collected from.
+	WorkDir string `mapstructure:"work_dir"`
+	// Capacity is what the worker advertises to the bin_packing scheduling
+	// policy. It is only checked per scheduler replica, so it holds while
+	// each worker pulls from one replica at a time.
+	Capacity ResourcesConfig `mapstructure:"capacity"`
+
+	LogShipping LogShippingConfig `mapstructure:"log_shipping"`
+}
+
+// ResourcesConfig is an amount of compute. Zero fields are unlimited.
+type ResourcesConfig struct {
+	CPU      float64 `mapstructure:"cpu"` // cores
+	MemoryMB int64   `mapstructure:"memory_mb"`
+}
+
+// LogShippingConfig controls how a worker sends job output to the
+// scheduler. Lines are buffered in memory up to MaxBufferBytes and spooled
+// to SpoolDir when the buffer is full or the scheduler is unreachable.
+type LogShippingConfig struct {
+	// BatchSize is the most lines sent in one batch; a partial batch is
+	// sent after FlushInterval.
+	BatchSize     int           `mapstructure:"batch_size"`
+	FlushInterval time.Duration `mapstructure:"flush_interval"`
+	// MaxLineBytes truncates longer lines.
+	MaxLineBytes   int    `mapstructure:"max_line_bytes"`
+	MaxBufferBytes int64  `mapstructure:"max_buffer_bytes"`
+	SpoolDir       string `mapstructure:"spool_dir"`
+	// MaxSpoolBytes bounds the spool; lines logged while it is full are
+	// dropped.
+	MaxSpoolBytes int64 `mapstructure:"max_spool_bytes"`
 }
 
 type ClientConfig struct {
@@ -34,6 +91,8 @@ type LoggingConfig struct {
 type StorageConfig struct {
 	Backend  string         `mapstructure:"backend"`
 	Postgres PostgresConfig `mapstructure:"postgres"`
+	Bolt     BoltConfig     `mapstructure:"bolt"`
+	WAL      WALConfig      `mapstructure:"wal"`
 }
 
 type Config struct {
@@ -43,6 +102,80 @@ type Config struct {
 	Retry     RetryConfig     `mapstructure:"retry"`
 	Logging   LoggingConfig   `mapstructure:"logging"`
 	Storage   StorageConfig   `mapstructure:"storage"`
+	JobLogs   JobLogsConfig   `mapstructure:"job_logs"`
+	Artifacts ArtifactsConfig `mapstructure:"artifacts"`
+	TaskTypes TaskTypesConfig `mapstructure:"task_types"`
+	Cache     CacheConfig     `mapstructure:"cache"`
+	Limits    []LimitConfig   `mapstructure:"limits"`
+	FairShare FairShareConfig `mapstructure:"fair_share"`
+}
+
+// TaskTypesConfig declares the catalog of task types the scheduler knows.
+type TaskTypesConfig struct {
+	// Strict rejects submissions of undeclared task types. Otherwise they
+	// are accepted with the default queue and retry policy.
+	Strict bool             `mapstructure:"strict"`
+	Types  []TaskTypeConfig `mapstructure:"types"`
+}
+
+// TaskTypeConfig declares one task type. Unset retry fields fall back to
+// the retry section, and a zero timeout means jobs may run indefinitely.
+type TaskTypeConfig struct {
+	Name        string        `mapstructure:"name"`
+	Description string        `mapstructure:"description"`
+	Queue       string        `mapstructure:"queue"`
+	Timeout     time.Duration `mapstructure:"timeout"`
+	Retry       RetryConfig   `mapstructure:"retry"`
+	// ArgsSchema and PayloadSchema are JSON Schema documents, written as
+	// JSON, that a job's args (an array of strings) and payload must
+	// satisfy.
+	ArgsSchema    string `mapstructure:"args_schema"`
+	PayloadSchema string `mapstructure:"payload_schema"`
+	// Version identifies the current behaviour of the task's executor; it
+	// is part of the cache key, so bumping it stops reuse of older results.
+	Version string `mapstructure:"version"`
+	// Cacheable makes the type's jobs cacheable unless a submission opts
+	// out. CacheTTL overrides cache.ttl for them.
+	Cacheable bool          `mapstructure:"cacheable"`
+	CacheTTL  time.Duration `mapstructure:"cache_ttl"`
+}
+
+// CacheConfig controls the reuse of results of cacheable jobs.
+type CacheConfig struct {
+	// TTL is how long a result is reused; 0 keeps it until invalidated.
+	TTL time.Duration `mapstructure:"ttl"`
+}
+
+// LimitConfig throttles the jobs of one task type, or of every type in one
+// queue, across all workers. Exactly one of Task and Queue is set, and zero
+// MaxRunning or Rate leaves that kind of limit off.
+type LimitConfig struct {
+	Task  string `mapstructure:"task"`
+	Queue string `mapstructure:"queue"`
+	// MaxRunning caps the jobs leased or running at once.
+	MaxRunning int `mapstructure:"max_running"`
+	// Rate is how many jobs may start per second on average; Burst is how
+	// many may start back to back after a quiet period, at least 1.
+	Rate  float64 `mapstructure:"rate"`
+	Burst int     `mapstructure:"burst"`
+}
+
+// FairShareConfig sets the shares of tenants under the fair_share
+// scheduling policy. Tenants that are not listed get DefaultWeight and no
+// minimum share.
+type FairShareConfig struct {
+	DefaultWeight float64        `mapstructure:"default_weight"`
+	Tenants       []TenantConfig `mapstructure:"tenants"`
+}
+
+// TenantConfig sets the share of one tenant; an unset Weight is
+// DefaultWeight. MinShare, between 0 and 1, is the fraction of running jobs
+// the tenant is guaranteed while it has jobs queued, whatever the weights
+// of the others.
+type TenantConfig struct {
+	Name     string  `mapstructure:"name"`
+	Weight   float64 `mapstructure:"weight"`
+	MinShare float64 `mapstructure:"min_share"`
 }
 
 type PostgresConfig struct {
@@ -51,4 +184,105 @@ type PostgresConfig struct {
 	Database string `mapstructure:"database"`
 	User     string `mapstructure:"user"`
 	Password string `mapstructure:"password"`
+
+	// SSLMode is passed to the driver as sslmode: disable, require,
+	// verify-ca or verify-full. SSLRootCert is the CA bundle used to verify
+	// the server; SSLCert and SSLKey are an optional client certificate.
+	SSLMode     string `mapstructure:"sslmode"`
+	SSLRootCert string `mapstructure:"sslrootcert"`
+	SSLCert     string `mapstructure:"sslcert"`
+	SSLKey      string `mapstructure:"sslkey"`
+
+	// Connection pool limits; see database/sql.DB.
+	MaxOpenConns    int           `mapstructure:"max_open_conns"`
+	MaxIdleConns    int           `mapstructure:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `mapstructure:"conn_max_idle_time"`
+	// StartupTimeout is how long to keep retrying the first connection,
+	// e.g. while the database container is still starting.
+	StartupTimeout time.Duration `mapstructure:"startup_timeout"`
+
+	// AutoMigrate applies pending schema migrations at startup. When off,
+	// the scheduler refuses to start unless the schema is up to date.
+	AutoMigrate bool `mapstructure:"auto_migrate"`
+}
+
+// BoltConfig configures the embedded single-file backend.
+type BoltConfig struct {
+	Path string `mapstructure:"path"`
+}
+
+// WALConfig controls crash recovery for the memory backend: every change
+// is appended to a write-ahead log in Dir and compacted into a snapshot
+// every SnapshotInterval.
+type WALConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Dir     string `mapstructure:"dir"`
+	// Fsync is "always" (sync every record), "interval" (sync every
+	// FsyncInterval) or "never" (leave it to the OS).
+	Fsync            string        `mapstructure:"fsync"`
+	FsyncInterval    time.Duration `mapstructure:"fsync_interval"`
+	SnapshotInterval time.Duration `mapstructure:"snapshot_interval"`
+}
+
+// JobLogsConfig selects where job log lines received by the scheduler go.
+type JobLogsConfig struct {
+	// Sinks lists "database", "file" and/or "stdout". GetJobLogs and
+	// TailJobLogs read from the database sink.
+	Sinks    []string              `mapstructure:"sinks"`
+	Database DatabaseLogSinkConfig `mapstructure:"database"`
+	File     FileLogSinkConfig     `mapstructure:"file"`
+	// PruneInterval is how often age-based retention runs.
+	PruneInterval time.Duration `mapstructure:"prune_interval"`
+}
+
+// DatabaseLogSinkConfig limits the logs kept in the job store. Zero values
+// keep everything.
+type DatabaseLogSinkConfig struct {
+	// MaxLinesPerJob keeps only the newest lines of each job.
+	MaxLinesPerJob int `mapstructure:"max_lines_per_job"`
+	// MaxAge drops the logs of jobs that finished longer ago than this.
+	MaxAge time.Duration `mapstructure:"max_age"`
+}
+
+// FileLogSinkConfig configures per-job log files.
+type FileLogSinkConfig struct {
+	Dir string `mapstructure:"dir"`
+	// MaxFileBytes rotates a job's file when it would grow past this size,
+	// keeping at most MaxFilesPerJob files per job.
+	MaxFileBytes   int64 `mapstructure:"max_file_bytes"`
+	MaxFilesPerJob int   `mapstructure:"max_files_per_job"`
+	// MaxAge removes files not written to for this long.
+	MaxAge time.Duration `mapstructure:"max_age"`
+}
+
+// ArtifactsConfig selects the blob store that holds job artifacts.
+type ArtifactsConfig struct {
+	// Backend is "filesystem" or "s3".
+	Backend string `mapstructure:"backend"`
+	// MaxBytes caps the size of one artifact.
+	MaxBytes   int64                `mapstructure:"max_bytes"`
+	Filesystem FilesystemBlobConfig `mapstructure:"filesystem"`
+	S3         S3BlobConfig         `mapstructure:"s3"`
+}
+
+// FilesystemBlobConfig keeps blobs as files under Dir.
+type FilesystemBlobConfig struct {
+	Dir string `mapstructure:"dir"`
+}
+
+// S3BlobConfig configures an S3-compatible bucket, e.g. AWS S3 or MinIO.
+type S3BlobConfig struct {
+	// Endpoint is the service URL; empty means AWS S3 in Region.
+	Endpoint string `mapstructure:"endpoint"`
+	Region   string `mapstructure:"region"`
+	Bucket   string `mapstructure:"bucket"`
+	// Prefix is prepended to every object key.
+	Prefix          string `mapstructure:"prefix"`
+	AccessKeyID     string `mapstructure:"access_key_id"`
+	SecretAccessKey string `mapstructure:"secret_access_key"`
+	SessionToken    string `mapstructure:"session_token"`
+	// PathStyle addresses the bucket in the URL path instead of the host
+	// name, as MinIO and most other S3-compatible servers expect.
+	PathStyle bool `mapstructure:"path_style"`
 }
@@ -0,0 +1,79 @@
+package logsink
+
+import (
+	"math"
+	"time"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/config"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/models"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/storage"
+)
+
+// finished lists the states after which a job writes no more logs.
+var finished = []models.TaskStatus{
+	models.TaskStatusSucceeded,
+	models.TaskStatusCancelled,
+	models.TaskStatusDeadLettered,
+}
+
+// DBSink stores lines in the job store, which numbers them, and serves
+// GetJobLogs and TailJobLogs.
+type DBSink struct {
+	store storage.JobStore
+	cfg   config.DatabaseLogSinkConfig
+}
+
+func NewDBSink(store storage.JobStore, cfg config.DatabaseLogSinkConfig) *DBSink {
+	return &DBSink{store: store, cfg: cfg}
+}
+
+// Write appends lines; see Append.
+func (s *DBSink) Write(jobID string, lines []models.LogLine) error {
+	_, err := s.Append(jobID, lines)
+	return err
+}
+
+// Append appends lines, leaving out those of a resent batch, then drops
+// the job's oldest lines beyond MaxLinesPerJob. It returns the lines it
+// appended, numbered.
+func (s *DBSink) Append(jobID string, lines []models.LogLine) ([]models.LogLine, error) {
+	added, err := s.store.AppendLogs(jobID, lines)
+	if err != nil {
+		return nil, err
+	}
+	if s.cfg.MaxLinesPerJob > 0 && len(added) > 0 {
+		if through := added[len(added)-1].Seq - int64(s.cfg.MaxLinesPerJob); through > 0 {
+			return added, s.store.DeleteLogs(jobID, through)
+		}
+	}
+	return added, nil
+}
+
+func (s *DBSink) Read(jobID string, after int64, limit int) ([]models.LogLine, error) {
+	return s.store.JobLogs(jobID, after, limit)
+}
+
+// Prune deletes the logs of jobs that finished more than MaxAge ago.
+func (s *DBSink) Prune(now time.Time) error {
+	if s.cfg.MaxAge <= 0 {
+		return nil
+	}
+	jobs, err := s.store.ListTasks(storage.TaskFilter{
+		Statuses:       finished,
+		FinishedBefore: now.Add(-s.cfg.MaxAge),
+	})
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		if err := s.store.DeleteLogs(job.ID, math.MaxInt64); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close is a no-op; the store is closed by its owner.
+func (s *DBSink) Close() error {
+	return nil
+}
@@ -0,0 +1,133 @@
+package logsink
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/config"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/models"
+)
+
+// FileSink writes each job's log to <dir>/<job id>.log, one
+// "<timestamp> <seq> <message>" line per entry. A file that would grow past
+// MaxFileBytes is rotated to .1, .2, ...; at most MaxFilesPerJob files are
+// kept per job, and files untouched for MaxAge are removed by Prune.
+type FileSink struct {
+	mu  sync.Mutex
+	cfg config.FileLogSinkConfig
+}
+
+func NewFileSink(cfg config.FileLogSinkConfig) (*FileSink, error) {
+	if err := os.MkdirAll(cfg.Dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileSink{cfg: cfg}, nil
+}
+
+func (s *FileSink) Write(jobID string, lines []models.LogLine) error {
+	if jobID == "" || filepath.Base(jobID) != jobID {
+		return fmt.Errorf("invalid job ID %q", jobID)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := filepath.Join(s.cfg.Dir, jobID+".log")
+	f, size, err := openAppend(path)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	for _, l := range lines {
+		entry := fmt.Sprintf("%s %d %s\n", l.At.UTC().Format(time.RFC3339Nano), l.Seq, l.Message)
+		if s.cfg.MaxFileBytes > 0 && size > 0 && size+int64(len(entry)) > s.cfg.MaxFileBytes {
+			if err := w.Flush(); err != nil {
+				f.Close()
+				return err
+			}
+			f.Close()
+			if err := s.rotate(path); err != nil {
+				return err
+			}
+			if f, size, err = openAppend(path); err != nil {
+				return err
+			}
+			w.Reset(f)
+		}
+		n, err := w.WriteString(entry)
+		size += int64(n)
+		if err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func openAppend(path string) (*os.File, int64, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, 0, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, 0, err
+	}
+	return f, info.Size(), nil
+}
+
+// rotate shifts path to path.1, path.1 to path.2 and so on, dropping the
+// oldest file so that at most MaxFilesPerJob remain with the new one.
+func (s *FileSink) rotate(path string) error {
+	keep := max(s.cfg.MaxFilesPerJob, 1) - 1 // rotated files besides the current one
+	if keep == 0 {
+		return os.Remove(path)
+	}
+	if err := os.Remove(fmt.Sprintf("%s.%d", path, keep)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for i := keep - 1; i >= 1; i-- {
+		err := os.Rename(fmt.Sprintf("%s.%d", path, i), fmt.Sprintf("%s.%d", path, i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return os.Rename(path, path+".1")
+}
+
+// Prune removes log files not written to for MaxAge.
+func (s *FileSink) Prune(now time.Time) error {
+	if s.cfg.MaxAge <= 0 {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, err := os.ReadDir(s.cfg.Dir)
+	if err != nil {
+		return err
+	}
+	cutoff := now.Add(-s.cfg.MaxAge)
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil || info.IsDir() || !info.ModTime().Before(cutoff) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.cfg.Dir, e.Name())); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close is a no-op; files are only held open while writing.
+func (s *FileSink) Close() error {
+	return nil
+}
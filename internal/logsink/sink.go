@@ -0,0 +1,151 @@
+// Package logsink delivers job log lines received by the scheduler to one
+// or more configured destinations.
+package logsink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/config"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/models"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/storage"
+)
+
+// ErrNotReadable is returned by Sinks.Read when no configured sink can
+// serve logs back.
+var ErrNotReadable = errors.New("job logs are not readable: enable the database log sink")
+
+// LogSink receives job log lines. Implementations apply their own
+// per-job retention as lines are written.
+type LogSink interface {
+	Write(jobID string, lines []models.LogLine) error
+	Close() error
+}
+
+// Reader is implemented by sinks that can serve logs back to clients.
+type Reader interface {
+	Read(jobID string, after int64, limit int) ([]models.LogLine, error)
+}
+
+// Pruner is implemented by sinks with age-based retention.
+type Pruner interface {
+	Prune(now time.Time) error
+}
+
+// Sinks fans lines out to every configured sink. The database sink, when
+// enabled, always comes first so that lines carry their stored Seq by the
+// time the other sinks see them.
+type Sinks struct {
+	sinks  []LogSink
+	db     *DBSink // also sinks[0], if enabled
+	logger *zap.Logger
+}
+
+// Open builds the sinks listed in cfg.Sinks.
+func Open(cfg config.JobLogsConfig, store storage.JobStore, logger *zap.Logger) (*Sinks, error) {
+	s := &Sinks{logger: logger}
+	seen := make(map[string]bool)
+	for _, name := range cfg.Sinks {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		switch name {
+		case "database":
+			s.db = NewDBSink(store, cfg.Database)
+			s.sinks = append([]LogSink{s.db}, s.sinks...)
+		case "file":
+			sink, err := NewFileSink(cfg.File)
+			if err != nil {
+				s.Close()
+				return nil, fmt.Errorf("file log sink: %w", err)
+			}
+			s.sinks = append(s.sinks, sink)
+		case "stdout":
+			sink, err := NewStdoutSink()
+			if err != nil {
+				s.Close()
+				return nil, fmt.Errorf("stdout log sink: %w", err)
+			}
+			s.sinks = append(s.sinks, sink)
+		default:
+			s.Close()
+			return nil, fmt.Errorf("unknown log sink %q", name)
+		}
+	}
+	return s, nil
+}
+
+// Write delivers lines to every sink. The database sink drops the lines
+// of a resent batch, which the other sinks then do not get either. Only a
+// failure of the database sink is returned, since the caller resends the
+// lines and the sinks that took them would otherwise get them twice;
+// without it, Write fails only if every sink did. Other failures are
+// logged.
+func (s *Sinks) Write(jobID string, lines []models.LogLine) error {
+	others := s.sinks
+	if s.db != nil {
+		added, err := s.db.Append(jobID, lines)
+		if err != nil {
+			return err
+		}
+		lines, others = added, s.sinks[1:]
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	var errs []error
+	for _, sink := range others {
+		if err := sink.Write(jobID, lines); err != nil {
+			s.logger.Warn("Failed to write job logs", zap.String("job_id", jobID), zap.Error(err))
+			errs = append(errs, err)
+		}
+	}
+	if s.db == nil && len(errs) == len(others) {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// Read serves lines from the first sink that implements Reader.
+func (s *Sinks) Read(jobID string, after int64, limit int) ([]models.LogLine, error) {
+	for _, sink := range s.sinks {
+		if r, ok := sink.(Reader); ok {
+			return r.Read(jobID, after, limit)
+		}
+	}
+	return nil, ErrNotReadable
+}
+
+// RunRetention prunes every Pruner sink each interval until ctx is done.
+func (s *Sinks) RunRetention(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			for _, sink := range s.sinks {
+				if p, ok := sink.(Pruner); ok {
+					if err := p.Prune(now); err != nil {
+						s.logger.Warn("Failed to prune job logs", zap.Error(err))
+					}
+				}
+			}
+		}
+	}
+}
+
+// Close closes every sink.
+func (s *Sinks) Close() error {
+	var errs []error
+	for _, sink := range s.sinks {
+		errs = append(errs, sink.Close())
+	}
+	return errors.Join(errs...)
+}
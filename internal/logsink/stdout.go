@@ -0,0 +1,45 @@
+package logsink
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/models"
+)
+
+// StdoutSink writes every line to stdout as a structured JSON record, for
+// collection by the container runtime's log pipeline. It keeps nothing, so
+// it has no retention.
+type StdoutSink struct {
+	logger *zap.Logger
+}
+
+func NewStdoutSink() (*StdoutSink, error) {
+	cfg := zap.NewProductionConfig()
+	cfg.OutputPaths = []string{"stdout"}
+	cfg.Sampling = nil // never drop job output
+	cfg.DisableCaller = true
+	cfg.DisableStacktrace = true
+	cfg.EncoderConfig.TimeKey = "timestamp"
+	cfg.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	logger, err := cfg.Build()
+	if err != nil {
+		return nil, err
+	}
+	return &StdoutSink{logger: logger}, nil
+}
+
+func (s *StdoutSink) Write(jobID string, lines []models.LogLine) error {
+	for _, l := range lines {
+		s.logger.Info(l.Message,
+			zap.String("job_id", jobID),
+			zap.Int64("seq", l.Seq),
+			zap.Time("at", l.At))
+	}
+	return nil
+}
+
+func (s *StdoutSink) Close() error {
+	s.logger.Sync()
+	return nil
+}
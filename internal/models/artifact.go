@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Artifact describes a named file produced by a job, or uploaded as a job
+// input. Its content is kept in the blob store rather than with the job.
+type Artifact struct {
+	JobID       string    `json:"job_id,omitempty"`
+	UploadID    string    `json:"upload_id,omitempty"`
+	Name        string    `json:"name"`
+	Size        int64     `json:"size"`
+	SHA256      string    `json:"sha256"`
+	ContentType string    `json:"content_type"`
+	CreatedAt   time.Time `json:"created_at"`
+}
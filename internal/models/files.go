@@ -0,0 +1,98 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"unicode"
+)
+
+// MaxArtifactName is the longest artifact name, in bytes.
+const MaxArtifactName = 255
+
+// ErrInvalidFiles is wrapped by the errors of ValidateFiles.
+var ErrInvalidFiles = errors.New("invalid job files")
+
+// JobInput is a file staged into a job's working directory before it
+// runs: either a file uploaded for the job or an artifact of an earlier job.
+type JobInput struct {
+	// Path is where the file is placed, relative to the working directory.
+	Path     string `json:"path"`
+	UploadID string `json:"upload_id,omitempty"`
+	JobID    string `json:"job_id,omitempty"`
+	Artifact string `json:"artifact,omitempty"`
+}
+
+// JobOutput is a file collected from a job's working directory as an
+// artifact after the job succeeds.
+type JobOutput struct {
+	Path string `json:"path"`
+	// Artifact names the artifact; it defaults to the path's base name.
+	Artifact string `json:"artifact,omitempty"`
+}
+
+// ArtifactName returns the name the output is stored under.
+func (o JobOutput) ArtifactName() string {
+	if o.Artifact != "" {
+		return o.Artifact
+	}
+	return path.Base(o.Path)
+}
+
+// ValidArtifactName reports whether name is a single path segment of
+// printable characters.
+func ValidArtifactName(name string) bool {
+	if name == "" || len(name) > MaxArtifactName || name == "." || name == ".." {
+		return false
+	}
+	for _, r := range name {
+		if r == '/' || r == '\\' || !unicode.IsPrint(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// validFilePath reports whether p is a slash-separated path that stays
+// inside the working directory.
+func validFilePath(p string) bool {
+	return fs.ValidPath(p) && p != "."
+}
+
+// ValidateFiles checks that a job's inputs and outputs are well formed:
+// relative paths inside the working directory, one source per input, and
+// no path or artifact name used twice.
+func ValidateFiles(inputs []JobInput, outputs []JobOutput) error {
+	paths := make(map[string]bool)
+	for _, in := range inputs {
+		if !validFilePath(in.Path) {
+			return fmt.Errorf("%w: input path %q must be relative and inside the working directory", ErrInvalidFiles, in.Path)
+		}
+		if paths[in.Path] {
+			return fmt.Errorf("%w: input path %q is used twice", ErrInvalidFiles, in.Path)
+		}
+		paths[in.Path] = true
+		switch {
+		case in.UploadID != "" && in.JobID == "" && in.Artifact == "":
+		case in.UploadID == "" && in.JobID != "" && ValidArtifactName(in.Artifact):
+		default:
+			return fmt.Errorf("%w: input %q needs either an upload ID or a job ID and artifact name", ErrInvalidFiles, in.Path)
+		}
+	}
+	names := make(map[string]bool)
+	for _, out := range outputs {
+		if !validFilePath(out.Path) {
+			return fmt.Errorf("%w: output path %q must be relative and inside the working directory", ErrInvalidFiles, out.Path)
+		}
+		name := out.ArtifactName()
+		if !ValidArtifactName(name) {
+			return fmt.Errorf("%w: output %q: invalid artifact name %q", ErrInvalidFiles, out.Path, name)
+		}
+		if names[name] {
+			return fmt.Errorf("%w: output artifact %q is declared twice", ErrInvalidFiles, name)
+		}
+		names[name] = true
+	}
+	return nil
+}
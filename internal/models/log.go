@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// LogLine is one line of a job's output. Seq numbers are assigned by the
+// store, start at 1 and are contiguous per job.
+type LogLine struct {
+	Seq     int64     `json:"seq"`
+	At      time.Time `json:"at"`
+	Message string    `json:"message"`
+
+	// Attempt and ShipSeq are set on lines shipped by a worker: ShipSeq
+	// numbers the lines of one job attempt from 1. Stores use them to drop
+	// the lines of a resent batch; they are not kept with the line.
+	Attempt int   `json:"attempt,omitempty"`
+	ShipSeq int64 `json:"ship_seq,omitempty"`
+}
+
+// Stored returns the line as stores keep it.
+func (l LogLine) Stored() LogLine {
+	return LogLine{Seq: l.Seq, At: l.At, Message: l.Message}
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Resources is an amount of compute: what a job requests, or what a worker
+// offers. Zero fields are unset; a job requests none of that resource, and
+// a worker does not limit it.
+type Resources struct {
+	CPU      float64 `json:"cpu,omitempty"` // cores
+	MemoryMB int64   `json:"memory_mb,omitempty"`
+}
+
+// IsZero reports whether no field is set.
+func (r Resources) IsZero() bool {
+	return r == Resources{}
+}
+
+// String lists the set fields, e.g. "2 CPU, 512 MB".
+func (r Resources) String() string {
+	var parts []string
+	if r.CPU != 0 {
+		parts = append(parts, strconv.FormatFloat(r.CPU, 'g', -1, 64)+" CPU")
+	}
+	if r.MemoryMB != 0 {
+		parts = append(parts, fmt.Sprintf("%d MB", r.MemoryMB))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Add returns the sum of r and o.
+func (r Resources) Add(o Resources) Resources {
+	return Resources{CPU: r.CPU + o.CPU, MemoryMB: r.MemoryMB + o.MemoryMB}
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Actors recorded on state transitions. Workers are recorded as
+// WorkerActor(id).
+const (
+	ActorClient    = "client"
+	ActorScheduler = "scheduler"
+)
+
+// WorkerActor returns the actor name recorded for a worker.
+func WorkerActor(workerID string) string {
+	return "worker:" + workerID
+}
+
+// transitions lists the legal next states for every state. Pending jobs
+// only go straight to succeeded when they reuse a cached result.
+var transitions = map[TaskStatus][]TaskStatus{
+	TaskStatusPending:   {TaskStatusScheduled, TaskStatusQueued, TaskStatusSucceeded, TaskStatusCancelled},
+	TaskStatusScheduled: {TaskStatusQueued, TaskStatusCancelled},
+	TaskStatusQueued:    {TaskStatusLeased, TaskStatusCancelled},
+	TaskStatusLeased:    {TaskStatusRunning, TaskStatusQueued, TaskStatusFailed, TaskStatusCancelled, TaskStatusTimedOut},
+	TaskStatusRunning:   {TaskStatusSucceeded, TaskStatusFailed, TaskStatusCancelled, TaskStatusTimedOut},
+	TaskStatusFailed:    {TaskStatusScheduled, TaskStatusQueued, TaskStatusDeadLettered},
+	TaskStatusTimedOut:  {TaskStatusScheduled, TaskStatusQueued, TaskStatusDeadLettered},
+}
+
+// CanTransition reports whether a task may move from s to next.
+func (s TaskStatus) CanTransition(next TaskStatus) bool {
+	for _, t := range transitions[s] {
+		if t == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTerminal reports whether no further transitions are possible.
+func (s TaskStatus) IsTerminal() bool {
+	return len(transitions[s]) == 0
+}
+
+// Valid reports whether s is a known state.
+func (s TaskStatus) Valid() bool {
+	switch s {
+	case TaskStatusSucceeded, TaskStatusCancelled, TaskStatusDeadLettered:
+		return true
+	}
+	_, ok := transitions[s]
+	return ok
+}
+
+// Transition records a single state change.
+type Transition struct {
+	From   TaskStatus `json:"from"`
+	To     TaskStatus `json:"to"`
+	At     time.Time  `json:"at"`
+	Actor  string     `json:"actor"`
+	Reason string     `json:"reason,omitempty"`
+}
+
+// InvalidTransitionError is returned when a state change is not allowed.
+type InvalidTransitionError struct {
+	From TaskStatus
+	To   TaskStatus
+}
+
+func (e *InvalidTransitionError) Error() string {
+	return fmt.Sprintf("invalid transition from %q to %q", e.From, e.To)
+}
@@ -1,25 +1,147 @@
 package models
 
-import "time"
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
 
 // TaskStatus represents the current state of a task
 type TaskStatus string
 
 const (
-	TaskStatusPending   TaskStatus = "pending"
-	TaskStatusRunning   TaskStatus = "running"
-	TaskStatusComplete  TaskStatus = "complete"
-	TaskStatusFailed    TaskStatus = "failed"
+	TaskStatusPending      TaskStatus = "pending"
+	TaskStatusScheduled    TaskStatus = "scheduled"
+	TaskStatusQueued       TaskStatus = "queued"
+	TaskStatusLeased       TaskStatus = "leased"
+	TaskStatusRunning      TaskStatus = "running"
+	TaskStatusSucceeded    TaskStatus = "succeeded"
+	TaskStatusFailed       TaskStatus = "failed"
+	TaskStatusCancelled    TaskStatus = "cancelled"
+	TaskStatusTimedOut     TaskStatus = "timed_out"
+	TaskStatusDeadLettered TaskStatus = "dead_lettered"
 )
 
 // Task represents a unit of work to be executed
 type Task struct {
-	ID          string     `json:"id"`
-	Type        string     `json:"type"`
-	Args        []string   `json:"args"`
-	Status      TaskStatus `json:"status"`
-	CreatedAt   time.Time  `json:"created_at"`
-	StartedAt   *time.Time `json:"started_at,omitempty"`
-	CompletedAt *time.Time `json:"completed_at,omitempty"`
-	Error       string     `json:"error,omitempty"`
-} 
\ No newline at end of file
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	Args        []string        `json:"args"`
+	Payload     json.RawMessage `json:"payload,omitempty"`
+	Inputs      []JobInput      `json:"inputs,omitempty"`
+	Outputs     []JobOutput     `json:"outputs,omitempty"`
+	Status      TaskStatus      `json:"status"`
+	Priority    int             `json:"priority"`
+	Queue       string          `json:"queue,omitempty"`
+	Tenant      string          `json:"tenant,omitempty"`
+	Timeout     time.Duration   `json:"timeout,omitempty"`
+	Result      string          `json:"result,omitempty"`
+	Output      json.RawMessage `json:"output,omitempty"`
+	Error       string          `json:"error,omitempty"`
+	ExitCode    int             `json:"exit_code"`
+	Attempts    int             `json:"attempts"`
+	WorkerID    string          `json:"worker_id,omitempty"`
+	History     []Transition    `json:"history,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	QueuedAt    *time.Time      `json:"queued_at,omitempty"`
+	StartedAt   *time.Time      `json:"started_at,omitempty"`
+	CompletedAt *time.Time      `json:"completed_at,omitempty"`
+
+	// LeaseExpiresAt is set while a worker holds the task. An expired lease
+	// means the worker is presumed lost and the task is reclaimed.
+	LeaseExpiresAt *time.Time `json:"lease_expires_at,omitempty"`
+
+	// RetryAt is set while the task waits out its retry backoff, to when
+	// it is due to be queued again.
+	RetryAt *time.Time `json:"retry_at,omitempty"`
+
+	// CacheKey identifies the result of a cacheable job. CachedFrom is set
+	// on jobs that reused the result of an earlier job with the same key
+	// instead of running.
+	CacheKey   string `json:"cache_key,omitempty"`
+	CachedFrom string `json:"cached_from,omitempty"`
+
+	// ConcurrencyKey, if set, names something the job must not share, e.g.
+	// a customer: at most ConcurrencyLimit jobs with the key, 1 if unset,
+	// are leased or running at once.
+	ConcurrencyKey   string `json:"concurrency_key,omitempty"`
+	ConcurrencyLimit int    `json:"concurrency_limit,omitempty"`
+
+	// UniqueKey is set on jobs submitted as unique: a later submission of
+	// the same task type with the same key may be answered with this job.
+	UniqueKey string `json:"unique_key,omitempty"`
+
+	// Resources is what the job needs of a worker, for policies that
+	// place jobs by size.
+	Resources Resources `json:"resources,omitzero"`
+
+	// Version is incremented by the store on every update and is used to
+	// detect concurrent modifications.
+	Version int `json:"version"`
+}
+
+// Clone returns a deep copy of the task.
+func (t *Task) Clone() *Task {
+	cp := *t
+	cp.Args = append([]string(nil), t.Args...)
+	cp.Payload = bytes.Clone(t.Payload)
+	cp.Output = bytes.Clone(t.Output)
+	cp.Inputs = append([]JobInput(nil), t.Inputs...)
+	cp.Outputs = append([]JobOutput(nil), t.Outputs...)
+	cp.History = append([]Transition(nil), t.History...)
+	cp.QueuedAt = cloneTime(t.QueuedAt)
+	cp.StartedAt = cloneTime(t.StartedAt)
+	cp.CompletedAt = cloneTime(t.CompletedAt)
+	cp.LeaseExpiresAt = cloneTime(t.LeaseExpiresAt)
+	cp.RetryAt = cloneTime(t.RetryAt)
+	return &cp
+}
+
+// LeaseExpired reports whether the task is held by a worker whose lease ran
+// out before now.
+func (t *Task) LeaseExpired(now time.Time) bool {
+	if t.Status != TaskStatusLeased && t.Status != TaskStatusRunning {
+		return false
+	}
+	return t.LeaseExpiresAt != nil && !now.Before(*t.LeaseExpiresAt)
+}
+
+// KeyLimit returns how many tasks with the task's concurrency key may be
+// leased or running at once.
+func (t *Task) KeyLimit() int {
+	return max(t.ConcurrencyLimit, 1)
+}
+
+// QueueDuration is how long the current attempt waited before starting.
+func (t *Task) QueueDuration() time.Duration {
+	switch {
+	case t.QueuedAt == nil:
+		return 0
+	case t.StartedAt != nil:
+		return t.StartedAt.Sub(*t.QueuedAt)
+	case t.Status.IsTerminal():
+		return 0
+	default:
+		return time.Since(*t.QueuedAt)
+	}
+}
+
+// RunDuration is how long the current attempt has been running, or ran for.
+func (t *Task) RunDuration() time.Duration {
+	switch {
+	case t.StartedAt == nil:
+		return 0
+	case t.CompletedAt != nil:
+		return t.CompletedAt.Sub(*t.StartedAt)
+	default:
+		return time.Since(*t.StartedAt)
+	}
+}
+
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	v := *t
+	return &v
+}
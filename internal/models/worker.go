@@ -0,0 +1,46 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// Worker is a registered worker node
+type Worker struct {
+	ID       string    `json:"id"`
+	Host     string    `json:"host"`
+	LastSeen time.Time `json:"last_seen"`
+	// Tasks lists the task types the worker has executors for. AnyTask is
+	// set for workers that also run tasks they do not list.
+	Tasks   []TaskCapability `json:"tasks,omitempty"`
+	AnyTask bool             `json:"any_task,omitempty"`
+	// Capacity is what the worker offers to the jobs it holds at once.
+	Capacity Resources `json:"capacity,omitzero"`
+}
+
+// TaskCapability is a task type a worker runs, with the version of its
+// executor if it reports one.
+type TaskCapability struct {
+	Task    string `json:"task"`
+	Version string `json:"version,omitempty"`
+}
+
+// Clone returns a deep copy of the worker.
+func (w *Worker) Clone() *Worker {
+	cp := *w
+	cp.Tasks = slices.Clone(w.Tasks)
+	return &cp
+}
+
+// Runs reports whether the worker can run jobs of the given task type and
+// version. An empty version, or a capability that reports none, matches
+// any version. A task the worker lists at another version is not run, even
+// by a worker that runs any task.
+func (w *Worker) Runs(task, version string) bool {
+	i := slices.IndexFunc(w.Tasks, func(c TaskCapability) bool { return c.Task == task })
+	if i < 0 {
+		return w.AnyTask
+	}
+	c := w.Tasks[i]
+	return version == "" || c.Version == "" || c.Version == version
+}
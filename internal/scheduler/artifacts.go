@@ -0,0 +1,267 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/blobstore"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/models"
+)
+
+var (
+	// ErrArtifactNotFound is returned for unknown artifact names.
+	ErrArtifactNotFound = errors.New("artifact not found")
+	// ErrInvalidArtifactName is returned for names that are empty, too
+	// long, or not a single printable path segment.
+	ErrInvalidArtifactName = errors.New("invalid artifact name")
+	// ErrArtifactTooLarge is returned when an upload exceeds the limit.
+	ErrArtifactTooLarge = errors.New("artifact exceeds the size limit")
+	// ErrJobNotRunning is returned for uploads to a job that is not
+	// running.
+	ErrJobNotRunning = errors.New("job is not running")
+)
+
+const defaultContentType = "application/octet-stream"
+
+// ArtifactStore keeps job artifacts, and files uploaded as job inputs, in a
+// blob store. Each is two blobs under its owner's prefix: the content, and
+// a small JSON record of its models.Artifact that is written last, so an
+// artifact is only listed once it is complete.
+type ArtifactStore struct {
+	blobs    blobstore.Store
+	jobs     *JobManager
+	maxBytes int64
+}
+
+func NewArtifactStore(blobs blobstore.Store, jobs *JobManager, maxBytes int64) *ArtifactStore {
+	return &ArtifactStore{blobs: blobs, jobs: jobs, maxBytes: maxBytes}
+}
+
+// Blob key prefixes of a job's artifacts and of an uploaded input.
+func jobOwner(jobID string) string       { return "jobs/" + jobID }
+func uploadOwner(uploadID string) string { return "uploads/" + uploadID }
+
+func artifactDataKey(owner, name string) string {
+	return owner + "/data/" + name
+}
+
+func artifactMetaPrefix(owner string) string {
+	return owner + "/meta/"
+}
+
+func artifactMetaKey(owner, name string) string {
+	return artifactMetaPrefix(owner) + name + ".json"
+}
+
+// Upload stores an artifact of a running job on behalf of the worker that
+// holds it, replacing any artifact of the same name. The content is staged
+// in a temporary file to enforce the size limit and checksum it before it
+// reaches the blob store.
+func (a *ArtifactStore) Upload(ctx context.Context, jobID, workerID, name, contentType string, r io.Reader) (*models.Artifact, error) {
+	if !models.ValidArtifactName(name) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidArtifactName, name)
+	}
+	job, err := a.jobs.Get(jobID)
+	if err != nil {
+		return nil, err
+	}
+	if err := held(job, workerID); err != nil {
+		return nil, err
+	}
+	if job.Status != models.TaskStatusRunning {
+		return nil, fmt.Errorf("%w: job is %s", ErrJobNotRunning, job.Status)
+	}
+	art := &models.Artifact{JobID: jobID, Name: name, ContentType: contentType}
+	if err := a.put(ctx, jobOwner(jobID), art, r); err != nil {
+		return nil, err
+	}
+	return art, nil
+}
+
+// UploadInput stores a file for jobs to take as an input, under a new
+// upload ID.
+func (a *ArtifactStore) UploadInput(ctx context.Context, name, contentType string, r io.Reader) (*models.Artifact, error) {
+	if !models.ValidArtifactName(name) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidArtifactName, name)
+	}
+	art := &models.Artifact{UploadID: uuid.New().String(), Name: name, ContentType: contentType}
+	if err := a.put(ctx, uploadOwner(art.UploadID), art, r); err != nil {
+		return nil, err
+	}
+	return art, nil
+}
+
+// put stores the content read from r and then art's record, filling in
+// the size, checksum and creation time.
+func (a *ArtifactStore) put(ctx context.Context, owner string, art *models.Artifact, r io.Reader) error {
+	tmp, err := os.CreateTemp("", "artifact-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), io.LimitReader(r, a.maxBytes+1))
+	if err != nil {
+		return err
+	}
+	if size > a.maxBytes {
+		return fmt.Errorf("%w of %d bytes", ErrArtifactTooLarge, a.maxBytes)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if err := a.blobs.Put(ctx, artifactDataKey(owner, art.Name), tmp, size); err != nil {
+		return fmt.Errorf("store artifact: %w", err)
+	}
+
+	if art.ContentType == "" {
+		art.ContentType = defaultContentType
+	}
+	art.Size = size
+	art.SHA256 = hex.EncodeToString(hash.Sum(nil))
+	art.CreatedAt = time.Now()
+	meta, err := json.Marshal(art)
+	if err != nil {
+		return err
+	}
+	if err := a.blobs.Put(ctx, artifactMetaKey(owner, art.Name), bytes.NewReader(meta), int64(len(meta))); err != nil {
+		return fmt.Errorf("store artifact: %w", err)
+	}
+	return nil
+}
+
+// List returns a job's artifacts by name.
+func (a *ArtifactStore) List(ctx context.Context, jobID string) ([]*models.Artifact, error) {
+	if _, err := a.jobs.Get(jobID); err != nil {
+		return nil, err
+	}
+	return a.list(ctx, jobOwner(jobID))
+}
+
+func (a *ArtifactStore) list(ctx context.Context, owner string) ([]*models.Artifact, error) {
+	keys, err := a.blobs.List(ctx, artifactMetaPrefix(owner))
+	if err != nil {
+		return nil, err
+	}
+	arts := make([]*models.Artifact, 0, len(keys))
+	for _, key := range keys {
+		name := strings.TrimSuffix(strings.TrimPrefix(key, artifactMetaPrefix(owner)), ".json")
+		art, err := a.stat(ctx, owner, name)
+		if errors.Is(err, ErrArtifactNotFound) {
+			continue // deleted since the listing
+		}
+		if err != nil {
+			return nil, err
+		}
+		arts = append(arts, art)
+	}
+	sort.Slice(arts, func(i, j int) bool { return arts[i].Name < arts[j].Name })
+	return arts, nil
+}
+
+// Open returns an artifact's record and content.
+func (a *ArtifactStore) Open(ctx context.Context, jobID, name string) (*models.Artifact, io.ReadCloser, error) {
+	if _, err := a.jobs.Get(jobID); err != nil {
+		return nil, nil, err
+	}
+	if !models.ValidArtifactName(name) {
+		return nil, nil, fmt.Errorf("%w: %q", ErrInvalidArtifactName, name)
+	}
+	return a.open(ctx, jobOwner(jobID), name)
+}
+
+// OpenUpload returns an uploaded input's record and content.
+func (a *ArtifactStore) OpenUpload(ctx context.Context, uploadID string) (*models.Artifact, io.ReadCloser, error) {
+	art, err := a.statUpload(ctx, uploadID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return a.open(ctx, uploadOwner(uploadID), art.Name)
+}
+
+// OpenInput returns the file a job input refers to.
+func (a *ArtifactStore) OpenInput(ctx context.Context, in models.JobInput) (*models.Artifact, io.ReadCloser, error) {
+	if in.UploadID != "" {
+		return a.OpenUpload(ctx, in.UploadID)
+	}
+	return a.Open(ctx, in.JobID, in.Artifact)
+}
+
+// CheckInputs verifies that every input refers to an existing file.
+func (a *ArtifactStore) CheckInputs(ctx context.Context, inputs []models.JobInput) error {
+	for _, in := range inputs {
+		var err error
+		if in.UploadID != "" {
+			_, err = a.statUpload(ctx, in.UploadID)
+		} else if _, err = a.jobs.Get(in.JobID); err == nil {
+			_, err = a.stat(ctx, jobOwner(in.JobID), in.Artifact)
+		}
+		if errors.Is(err, ErrArtifactNotFound) || errors.Is(err, ErrJobNotFound) {
+			return fmt.Errorf("%w: input %q: %v", models.ErrInvalidFiles, in.Path, err)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *ArtifactStore) open(ctx context.Context, owner, name string) (*models.Artifact, io.ReadCloser, error) {
+	art, err := a.stat(ctx, owner, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	body, err := a.blobs.Get(ctx, artifactDataKey(owner, name))
+	if errors.Is(err, blobstore.ErrNotFound) {
+		return nil, nil, ErrArtifactNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return art, body, nil
+}
+
+// statUpload returns the record of the one file under an upload ID.
+func (a *ArtifactStore) statUpload(ctx context.Context, uploadID string) (*models.Artifact, error) {
+	if _, err := uuid.Parse(uploadID); err != nil {
+		return nil, ErrArtifactNotFound
+	}
+	arts, err := a.list(ctx, uploadOwner(uploadID))
+	if err != nil {
+		return nil, err
+	}
+	if len(arts) == 0 {
+		return nil, ErrArtifactNotFound
+	}
+	return arts[0], nil
+}
+
+func (a *ArtifactStore) stat(ctx context.Context, owner, name string) (*models.Artifact, error) {
+	body, err := a.blobs.Get(ctx, artifactMetaKey(owner, name))
+	if errors.Is(err, blobstore.ErrNotFound) {
+		return nil, ErrArtifactNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	var art models.Artifact
+	if err := json.NewDecoder(body).Decode(&art); err != nil {
+		return nil, fmt.Errorf("artifact %s/%s: %w", owner, name, err)
+	}
+	return &art, nil
+}
@@ -0,0 +1,112 @@
+package scheduler
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/models"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/storage"
+)
+
+// binPackingWindow is how many ready jobs, in priority order, BinPacking
+// considers for each placement.
+const binPackingWindow = 100
+
+// BinPacking places jobs by the resources they request: a worker is handed
+// the ready job that leaves the least of its capacity free, and never one
+// that does not fit beside the jobs it already holds. Packing workers
+// tightly keeps room elsewhere for large jobs, at the expense of priority,
+// which only breaks ties. Workers that advertise no capacity are handed
+// jobs as under Priority.
+//
+// Capacity is checked by each scheduler replica, not in the job store: a
+// worker whose pulls reach two replicas at once can be handed more than
+// it offers.
+type BinPacking struct {
+	// mu serializes placements, so that two pulls by the same worker do not
+	// both count on the same free capacity. It only covers this replica.
+	mu    sync.Mutex
+	store storage.JobStore
+}
+
+func NewBinPacking(store storage.JobStore) *BinPacking {
+	return &BinPacking{store: store}
+}
+
+func (p *BinPacking) Name() string { return PolicyBinPacking }
+
+func (p *BinPacking) Next(w *WorkerInfo, ready *ReadySet) (*Job, error) {
+	if w.Capacity.IsZero() {
+		return ready.Claim(ready.Filter)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	held, err := p.store.ListTasks(storage.TaskFilter{
+		Statuses: []models.TaskStatus{models.TaskStatusLeased, models.TaskStatusRunning},
+		WorkerID: w.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var used models.Resources
+	for _, job := range held {
+		used = used.Add(job.Resources)
+	}
+	candidates, err := ready.Peek(ready.Filter, binPackingWindow)
+	if err != nil {
+		return nil, err
+	}
+
+	type fit struct {
+		id   string
+		fill float64
+	}
+	var fits []fit
+	for _, job := range candidates {
+		if fill, ok := packedFill(w.Capacity, used.Add(job.Resources)); ok {
+			fits = append(fits, fit{job.ID, fill})
+		}
+	}
+	sort.SliceStable(fits, func(i, j int) bool { return fits[i].fill > fits[j].fill })
+	for _, f := range fits {
+		filter := ready.Filter
+		filter.IDs = []string{f.id}
+		job, err := ready.Claim(filter)
+		if err != nil || job != nil {
+			return job, err
+		}
+		// Claimed by another worker since it was peeked.
+	}
+	return nil, nil
+}
+
+// fits reports whether a job requesting req fits on an idle worker with
+// the given capacity.
+func fits(capacity, req models.Resources) bool {
+	_, ok := packedFill(capacity, req)
+	return ok
+}
+
+// packedFill reports whether used fits in capacity and, if so, how full it
+// makes the worker: the mean fraction in use of the resources it limits.
+func packedFill(capacity, used models.Resources) (float64, bool) {
+	var fill float64
+	var limited int
+	for _, r := range []struct{ capacity, used float64 }{
+		{capacity.CPU, used.CPU},
+		{float64(capacity.MemoryMB), float64(used.MemoryMB)},
+	} {
+		if r.capacity == 0 {
+			continue
+		}
+		if r.used > r.capacity {
+			return 0, false
+		}
+		fill += r.used / r.capacity
+		limited++
+	}
+	if limited == 0 {
+		return 0, true
+	}
+	return fill / float64(limited), true
+}
@@ -0,0 +1,189 @@
+package scheduler
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/models"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/storage"
+)
+
+func TestFits(t *testing.T) {
+	cases := []struct {
+		name          string
+		capacity, req models.Resources
+		want          bool
+	}{
+		{"no capacity", models.Resources{}, models.Resources{CPU: 64, MemoryMB: 1 << 20}, true},
+		{"no request", models.Resources{CPU: 2, MemoryMB: 1024}, models.Resources{}, true},
+		{"exact", models.Resources{CPU: 2, MemoryMB: 1024}, models.Resources{CPU: 2, MemoryMB: 1024}, true},
+		{"too many cores", models.Resources{CPU: 2, MemoryMB: 1024}, models.Resources{CPU: 2.5}, false},
+		{"too much memory", models.Resources{CPU: 2, MemoryMB: 1024}, models.Resources{CPU: 1, MemoryMB: 2048}, false},
+		{"unlimited memory", models.Resources{CPU: 2}, models.Resources{CPU: 1, MemoryMB: 1 << 20}, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := fits(c.capacity, c.req); got != c.want {
+				t.Fatalf("fits(%+v, %+v) = %v, want %v", c.capacity, c.req, got, c.want)
+			}
+		})
+	}
+}
+
+// TestBinPacking checks which ready job BinPacking hands a worker that
+// already holds some jobs.
+func TestBinPacking(t *testing.T) {
+	type job struct {
+		name      string
+		priority  int
+		resources models.Resources
+	}
+	cases := []struct {
+		name     string
+		capacity models.Resources
+		held     []models.Resources
+		ready    []job
+		want     string // name of the job handed out, "" for none
+	}{
+		{
+			name:     "best fit",
+			capacity: models.Resources{CPU: 4, MemoryMB: 4096},
+			ready: []job{
+				{"small", 5, models.Resources{CPU: 1, MemoryMB: 512}},
+				{"large", 1, models.Resources{CPU: 3, MemoryMB: 3072}},
+				{"too large", 9, models.Resources{CPU: 8}},
+			},
+			want: "large",
+		},
+		{
+			name:     "beside held jobs",
+			capacity: models.Resources{CPU: 4, MemoryMB: 4096},
+			held:     []models.Resources{{CPU: 2, MemoryMB: 1024}},
+			ready: []job{
+				{"large", 5, models.Resources{CPU: 3}},
+				{"medium", 1, models.Resources{CPU: 2, MemoryMB: 1024}},
+				{"small", 1, models.Resources{CPU: 1}},
+			},
+			want: "medium",
+		},
+		{
+			name:     "nothing fits",
+			capacity: models.Resources{CPU: 4},
+			held:     []models.Resources{{CPU: 3}},
+			ready:    []job{{"medium", 1, models.Resources{CPU: 2}}},
+			want:     "",
+		},
+		{
+			name:     "tie goes to priority",
+			capacity: models.Resources{CPU: 4},
+			ready: []job{
+				{"low", 1, models.Resources{CPU: 2}},
+				{"high", 5, models.Resources{CPU: 2}},
+				{"middle", 3, models.Resources{CPU: 2}},
+			},
+			want: "high",
+		},
+		{
+			name: "no capacity",
+			ready: []job{
+				{"large", 1, models.Resources{CPU: 64}},
+				{"small", 5, models.Resources{CPU: 1}},
+			},
+			want: "small",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			jm := newTestJobs(t)
+			for _, r := range c.held {
+				job := submitQueued(t, jm, JobSpec{Task: "echo", Resources: r})
+				if _, err := jm.Claim("w1", storage.ClaimFilter{IDs: []string{job.ID}}, time.Minute); err != nil {
+					t.Fatal(err)
+				}
+			}
+			names := make(map[string]string)
+			for _, j := range c.ready {
+				job := submitQueued(t, jm, JobSpec{Task: "echo", Priority: j.priority, Resources: j.resources})
+				names[job.ID] = j.name
+			}
+
+			w := &WorkerInfo{ID: "w1", AnyTask: true, Capacity: c.capacity}
+			job, err := NewBinPacking(jm.store).Next(w, &ReadySet{workerID: w.ID, jobs: jm, lease: time.Minute})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got string
+			if job != nil {
+				got = names[job.ID]
+			}
+			if got != c.want {
+				t.Fatalf("handed out %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+// TestWaitingTooLarge checks that a job no connected worker has the
+// capacity for waits, and says why.
+func TestWaitingTooLarge(t *testing.T) {
+	jm := newTestJobs(t)
+	workers := NewWorkerManager(jm.store, time.Minute, zap.NewNop())
+	caps := []models.TaskCapability{{Task: "echo"}}
+	if err := workers.Register("small", "host", caps, false, models.Resources{CPU: 2, MemoryMB: 2048}); err != nil {
+		t.Fatal(err)
+	}
+	if err := workers.Register("large", "host", caps, false, models.Resources{CPU: 4, MemoryMB: 1024}); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name string
+		req  models.Resources
+		want bool
+	}{
+		{"fits small", models.Resources{CPU: 2, MemoryMB: 2048}, true},
+		{"fits large", models.Resources{CPU: 4}, true},
+		{"fits neither", models.Resources{CPU: 4, MemoryMB: 2048}, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := workers.Fits("echo", "", c.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != c.want {
+				t.Fatalf("Fits(%s) = %v, want %v", c.req, got, c.want)
+			}
+		})
+	}
+
+	job := submitQueued(t, jm, JobSpec{Task: "echo", Resources: models.Resources{CPU: 4, MemoryMB: 2048}})
+	policy := NewBinPacking(jm.store)
+	for _, id := range []string{"small", "large"} {
+		w, _ := workers.Get(id)
+		got, err := policy.Next(w, &ReadySet{Filter: claimFilter(w, jm.tasks), workerID: w.ID, jobs: jm, lease: time.Minute})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != nil {
+			t.Fatalf("%s was handed %s, which does not fit", id, got.ID)
+		}
+	}
+
+	limits, err := NewLimiter(nil, jm.store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &SchedulerServer{Jobs: jm, Workers: workers, Tasks: jm.tasks, Limits: limits, Policy: policy, Logger: zap.NewNop()}
+	job, err = jm.Get(job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reason := s.jobDetails(job).WaitingReason
+	if !strings.HasPrefix(reason, "no worker large enough: ") || !strings.Contains(reason, "4 CPU, 2048 MB") {
+		t.Fatalf("waiting reason %q, want no worker large enough for 4 CPU, 2048 MB", reason)
+	}
+}
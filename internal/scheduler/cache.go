@@ -0,0 +1,156 @@
+package scheduler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/models"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/storage"
+)
+
+// CacheKey hashes what determines the result of a deterministic job: its
+// task, the task type's version, its args and its payload. The payload is
+// decoded and re-encoded so that key order and spacing do not matter.
+func CacheKey(task, version string, args []string, payload json.RawMessage) string {
+	if args == nil {
+		args = []string{}
+	}
+	var p any
+	if len(payload) > 0 {
+		if err := json.Unmarshal(payload, &p); err != nil {
+			p = string(payload)
+		}
+	}
+	// Encoding plain strings, slices and decoded JSON cannot fail.
+	b, _ := json.Marshal(struct {
+		Task    string   `json:"task"`
+		Version string   `json:"version"`
+		Args    []string `json:"args"`
+		Payload any      `json:"payload"`
+	}{task, version, args, p})
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// CacheEntry is a reusable result: that of the last job with its key that
+// ran and succeeded. Jobs that reused a result are not entries themselves,
+// so reuse never extends an entry's life.
+type CacheEntry struct {
+	Key       string
+	Task      string
+	JobID     string
+	CreatedAt time.Time
+	ExpiresAt *time.Time // nil if the entry is kept until invalidated
+	Hits      int
+}
+
+// Expired reports whether the entry may no longer be reused.
+func (e *CacheEntry) Expired(now time.Time) bool {
+	return e.ExpiresAt != nil && !now.Before(*e.ExpiresAt)
+}
+
+// CacheFilter selects cache entries. Empty fields match every entry.
+type CacheFilter struct {
+	Task           string
+	Key            string
+	IncludeExpired bool
+}
+
+// CacheEntries returns the matching entries, newest first.
+func (jm *JobManager) CacheEntries(filter CacheFilter) ([]*CacheEntry, error) {
+	jobs, err := jm.cachedJobs(filter.Task, filter.Key)
+	if err != nil {
+		return nil, err
+	}
+	latest := make(map[string]*CacheEntry)
+	for _, job := range jobs {
+		if job.CachedFrom != "" {
+			continue
+		}
+		if e, ok := latest[job.CacheKey]; !ok || job.CompletedAt.After(e.CreatedAt) {
+			latest[job.CacheKey] = jm.cacheEntry(job)
+		}
+	}
+	for _, job := range jobs {
+		if e, ok := latest[job.CacheKey]; ok && job.CachedFrom == e.JobID {
+			e.Hits++
+		}
+	}
+
+	now := time.Now()
+	entries := make([]*CacheEntry, 0, len(latest))
+	for _, e := range latest {
+		if filter.IncludeExpired || !e.Expired(now) {
+			entries = append(entries, e)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].CreatedAt.After(entries[j].CreatedAt) })
+	return entries, nil
+}
+
+// InvalidateCache drops the entries for a task type and key, either of
+// which may be empty to match any, and returns how many it dropped. The
+// jobs behind them keep their results but lose their cache key.
+func (jm *JobManager) InvalidateCache(task, key string) (int, error) {
+	jobs, err := jm.cachedJobs(task, key)
+	if err != nil {
+		return 0, err
+	}
+	dropped := make(map[string]bool)
+	for _, job := range jobs {
+		if job.CachedFrom != "" {
+			continue
+		}
+		_, err := jm.update(job.ID, func(j *Job) error {
+			j.CacheKey = ""
+			return nil
+		})
+		if err != nil {
+			return len(dropped), err
+		}
+		dropped[job.CacheKey] = true
+	}
+	return len(dropped), nil
+}
+
+// cachedResult returns the job whose result a job with the given key can
+// reuse, or nil if there is no unexpired entry. The key covers the task
+// type, so it is looked up on its own.
+func (jm *JobManager) cachedResult(key string) (*Job, error) {
+	job, err := jm.store.CachedTask(key)
+	if err != nil || job == nil {
+		return nil, err
+	}
+	if jm.cacheEntry(job).Expired(time.Now()) {
+		return nil, nil
+	}
+	return job, nil
+}
+
+// cachedJobs returns the succeeded jobs with a cache key matching task and
+// key.
+func (jm *JobManager) cachedJobs(task, key string) ([]*Job, error) {
+	return jm.store.ListTasks(storage.TaskFilter{
+		Statuses: []models.TaskStatus{models.TaskStatusSucceeded},
+		Type:     task,
+		CacheKey: key,
+		Cached:   true,
+	})
+}
+
+func (jm *JobManager) cacheEntry(job *Job) *CacheEntry {
+	e := &CacheEntry{
+		Key:       job.CacheKey,
+		Task:      job.Type,
+		JobID:     job.ID,
+		CreatedAt: *job.CompletedAt,
+	}
+	if ttl := jm.tasks.CacheTTL(job.Type); ttl > 0 {
+		expires := e.CreatedAt.Add(ttl)
+		e.ExpiresAt = &expires
+	}
+	return e
+}
@@ -0,0 +1,131 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/config"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/models"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/storage"
+)
+
+func TestCacheKey(t *testing.T) {
+	base := CacheKey("resize", "1", []string{"a.png"}, json.RawMessage(`{"width": 10, "opts": {"fit": "crop", "q": 80}}`))
+	cases := []struct {
+		name    string
+		task    string
+		version string
+		args    []string
+		payload string
+		same    bool
+	}{
+		{"key order and spacing", "resize", "1", []string{"a.png"}, `{"opts":{"q":80,"fit":"crop"},"width":10}`, true},
+		{"other task", "crop", "1", []string{"a.png"}, `{"width": 10, "opts": {"fit": "crop", "q": 80}}`, false},
+		{"other version", "resize", "2", []string{"a.png"}, `{"width": 10, "opts": {"fit": "crop", "q": 80}}`, false},
+		{"no version", "resize", "", []string{"a.png"}, `{"width": 10, "opts": {"fit": "crop", "q": 80}}`, false},
+		{"other args", "resize", "1", []string{"b.png"}, `{"width": 10, "opts": {"fit": "crop", "q": 80}}`, false},
+		{"reordered args", "resize", "1", []string{"a.png", "b.png"}, `{"width": 10, "opts": {"fit": "crop", "q": 80}}`, false},
+		{"other payload", "resize", "1", []string{"a.png"}, `{"width": 20, "opts": {"fit": "crop", "q": 80}}`, false},
+		{"no payload", "resize", "1", []string{"a.png"}, ``, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := CacheKey(c.task, c.version, c.args, json.RawMessage(c.payload))
+			if (got == base) != c.same {
+				t.Fatalf("same key as the base job: %v, want %v", got == base, c.same)
+			}
+		})
+	}
+	if CacheKey("resize", "1", nil, nil) != CacheKey("resize", "1", []string{}, nil) {
+		t.Fatal("nil and empty args have different keys")
+	}
+}
+
+// submitCacheable resolves and submits a job as the server does.
+func submitCacheable(t *testing.T, jm *JobManager, spec JobSpec) *Job {
+	t.Helper()
+	if err := jm.tasks.Resolve(&spec); err != nil {
+		t.Fatal(err)
+	}
+	job, _, err := jm.Submit(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return job
+}
+
+// succeed runs a submitted job to success on worker w1.
+func succeed(t *testing.T, jm *JobManager, id, result string) {
+	t.Helper()
+	if err := jm.Transition(id, models.TaskStatusQueued, models.ActorScheduler, "queued"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := jm.Claim("w1", storage.ClaimFilter{IDs: []string{id}}, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if err := jm.Start(id, "w1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := jm.Complete(id, "w1", result, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestCacheReuse checks that a cacheable job reuses the result of the last
+// job with its key that ran, until the entry expires or is invalidated.
+func TestCacheReuse(t *testing.T) {
+	const ttl = time.Hour
+	jm := newTestJobs(t, config.TaskTypeConfig{Name: "resize", Version: "1", Cacheable: true, CacheTTL: ttl})
+	spec := JobSpec{Task: "resize", Args: []string{"a.png"}}
+
+	first := submitCacheable(t, jm, spec)
+	if first.CachedFrom != "" {
+		t.Fatalf("first job reused %s with nothing cached", first.CachedFrom)
+	}
+	succeed(t, jm, first.ID, "done")
+
+	hit := submitCacheable(t, jm, spec)
+	if hit.CachedFrom != first.ID || hit.Result != "done" {
+		t.Fatalf("second job reused %q with result %q, want %s's result", hit.CachedFrom, hit.Result, first.ID)
+	}
+	// A reused result is not an entry of its own.
+	if again := submitCacheable(t, jm, spec); again.CachedFrom != first.ID {
+		t.Fatalf("third job reused %q, want %s", again.CachedFrom, first.ID)
+	}
+	entries, err := jm.CacheEntries(CacheFilter{Key: first.CacheKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].JobID != first.ID || entries[0].Hits != 2 {
+		t.Fatalf("entries %+v, want one for %s with 2 hits", entries, first.ID)
+	}
+
+	if miss := submitCacheable(t, jm, JobSpec{Task: "resize", Args: []string{"b.png"}}); miss.CachedFrom != "" {
+		t.Fatalf("job with other args reused %s", miss.CachedFrom)
+	}
+
+	// Age the entry past its TTL.
+	if _, err := jm.update(first.ID, func(j *Job) error {
+		expired := time.Now().Add(-ttl)
+		j.CompletedAt = &expired
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	expired := submitCacheable(t, jm, spec)
+	if expired.CachedFrom != "" {
+		t.Fatalf("job reused the expired result of %s", expired.CachedFrom)
+	}
+	succeed(t, jm, expired.ID, "done again")
+	if hit := submitCacheable(t, jm, spec); hit.CachedFrom != expired.ID {
+		t.Fatalf("job reused %q, want the fresh result of %s", hit.CachedFrom, expired.ID)
+	}
+
+	if n, err := jm.InvalidateCache("resize", first.CacheKey); err != nil || n != 1 {
+		t.Fatalf("InvalidateCache = %d, %v, want 1 entry", n, err)
+	}
+	if miss := submitCacheable(t, jm, spec); miss.CachedFrom != "" {
+		t.Fatalf("job reused the invalidated result of %s", miss.CachedFrom)
+	}
+}
@@ -1,50 +1,215 @@
 package scheduler
 
 import (
+	"context"
+	"time"
+
 	"go.uber.org/zap"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/models"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/storage"
 )
 
+// Dispatcher hands queued jobs to workers. The ready queue is the set of
+// queued jobs in the JobStore, and the scheduling policy picks which of
+// them a worker gets; the store guarantees each job goes to exactly one
+// consumer, either a remote worker via PullJob or the embedded local
+// executor, even across scheduler replicas. A worker is only handed jobs of
+// the task types it registered for, and jobs held back by a limit are
+// skipped.
 type Dispatcher struct {
-	JobQueue   chan string
-	JobManager *JobManager
-	Logger     *zap.Logger
+	JobManager    *JobManager
+	Workers       *WorkerManager
+	Limits        *Limiter
+	Policy        SchedulingPolicy
+	Logger        *zap.Logger
+	LeaseDuration time.Duration
 }
 
-func NewDispatcher(jm *JobManager, logger *zap.Logger) *Dispatcher {
+func NewDispatcher(jm *JobManager, workers *WorkerManager, limits *Limiter, policy SchedulingPolicy, leaseDuration time.Duration, logger *zap.Logger) *Dispatcher {
 	return &Dispatcher{
-		JobQueue:   make(chan string, 100),
-		JobManager: jm,
-		Logger:     logger,
+		JobManager:    jm,
+		Workers:       workers,
+		Limits:        limits,
+		Policy:        policy,
+		Logger:        logger,
+		LeaseDuration: leaseDuration,
 	}
 }
 
-func (d *Dispatcher) Run() {
-	go func() {
-		for jobID := range d.JobQueue {
-			job, ok := d.JobManager.Get(jobID)
-			if !ok {
-				continue
-			}
+// Enqueue moves a job to queued, making it available to workers.
+func (d *Dispatcher) Enqueue(jobID, reason string) error {
+	return d.JobManager.Transition(jobID, models.TaskStatusQueued, models.ActorScheduler, reason)
+}
+
+// NextJob leases the next ready job workerID can run to it, or returns nil
+// when there is none or the worker is not registered.
+func (d *Dispatcher) NextJob(workerID string) *Job {
+	w, ok := d.Workers.Get(workerID)
+	if !ok {
+		return nil
+	}
+	job, err := d.Limits.Claim(claimFilter(w, d.JobManager.tasks), func(filter storage.ClaimFilter) (*Job, error) {
+		return d.Policy.Next(w, &ReadySet{
+			Filter:   filter,
+			workerID: workerID,
+			jobs:     d.JobManager,
+			lease:    d.LeaseDuration,
+		})
+	})
+	if err != nil {
+		d.Logger.Error("Failed to claim job", zap.String("worker", workerID), zap.Error(err))
+		return nil
+	}
+	if job == nil {
+		return nil
+	}
+	d.Logger.Info("Dispatching job",
+		zap.String("job_id", job.ID),
+		zap.String("task", job.Type),
+		zap.String("tenant", job.Tenant),
+		zap.Int("priority", job.Priority),
+		zap.String("worker", workerID))
+	return job
+}
 
-			d.Logger.Info("Dispatching job", zap.String("job_id", jobID), zap.String("task", job.Task))
+// WaitForJob is NextJob that waits, until ctx is done, for a job to be
+// queued when none is ready. With limits configured or jobs placed by bin
+// packing it also wakes when a job stops running, which may free a slot
+// under a limit or capacity on a worker, and when a start rate allows
+// another start.
+func (d *Dispatcher) WaitForJob(ctx context.Context, workerID string) *Job {
+	events := d.JobManager.events
+	filter := queuedEvents
+	if _, packing := d.Policy.(*BinPacking); packing || len(d.Limits.Limits()) > 0 {
+		filter = releaseEvents
+	}
+	wake, unsubscribe := events.Subscribe(filter)
+	defer unsubscribe()
+	for {
+		if job := d.NextJob(workerID); job != nil {
+			return job
+		}
+		wait := events.PollInterval
+		if next := d.Limits.NextStart(); next > 0 && next < wait {
+			wait = next
+		}
+		if !events.WaitFor(ctx, wake, wait) {
+			return nil
+		}
+	}
+}
 
-			// Simulate local execution (replace with remote worker call later)
-			d.JobManager.SetStatus(jobID, "completed")
+// Heartbeat renews the leases of all jobs held by a worker.
+func (d *Dispatcher) Heartbeat(workerID string) {
+	if err := d.JobManager.RenewLeases(workerID, d.LeaseDuration); err != nil {
+		d.Logger.Warn("Failed to renew leases", zap.String("worker", workerID), zap.Error(err))
+	}
+}
 
-			d.Logger.Info("Job completed", zap.String("job_id", jobID))
+// Fail records a failed attempt and schedules a retry with its task type's
+// backoff if the job has attempts left.
+func (d *Dispatcher) Fail(jobID, workerID, errMsg string, exitCode int) error {
+	job, retry, err := d.JobManager.Fail(jobID, workerID, errMsg, exitCode)
+	if err != nil || !retry {
+		return err
+	}
+	return d.retry(job)
+}
+
+func (d *Dispatcher) retry(job *Job) error {
+	delay := d.JobManager.tasks.Retry(job.Type).Backoff(job.Attempts)
+	_, err := d.JobManager.update(job.ID, func(j *Job) error {
+		if err := transition(j, models.TaskStatusScheduled, models.ActorScheduler, "retry in "+delay.String()); err != nil {
+			return err
 		}
-	}()
+		at := time.Now().Add(delay)
+		j.RetryAt = &at
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	d.enqueueAfter(job.ID, delay, "retry")
+	return nil
 }
 
-func (d *Dispatcher) NextJob() *Job {
-	d.JobManager.mu.Lock()
-	defer d.JobManager.mu.Unlock()
+// ReapLeases reclaims jobs whose worker stopped renewing its lease, every
+// interval until ctx is cancelled. Leased jobs that never started go back
+// to the queue; running jobs time out and are retried like failures.
+func (d *Dispatcher) ReapLeases(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.reapLeases()
+		}
+	}
+}
 
-	for _, job := range d.JobManager.jobs {
-		if job.Status == "queued" {
-			job.Status = "in_progress"
-			return job
+func (d *Dispatcher) reapLeases() {
+	expired, err := d.JobManager.ExpiredLeases()
+	if err != nil {
+		d.Logger.Error("Failed to list expired leases", zap.Error(err))
+		return
+	}
+	for _, job := range expired {
+		logger := d.Logger.With(zap.String("job_id", job.ID), zap.String("worker", job.WorkerID))
+		switch job.Status {
+		case models.TaskStatusLeased:
+			err = d.Enqueue(job.ID, "lease expired before start")
+		case models.TaskStatusRunning:
+			var retry bool
+			job, retry, err = d.JobManager.TimeOut(job.ID, "lease expired")
+			if err == nil && retry {
+				err = d.retry(job)
+			}
+		}
+		if err != nil {
+			// Another replica may have reclaimed it first.
+			logger.Debug("Lease reclaim skipped", zap.Error(err))
+			continue
 		}
+		logger.Warn("Reclaimed job with expired lease")
 	}
+}
+
+// Recover enqueues jobs that were pending or waiting for a retry when the
+// scheduler stopped; those waiting for a retry wait out the rest of their
+// backoff first. Queued jobs need no recovery, and leased and running jobs
+// stay with their workers until their lease expires.
+func (d *Dispatcher) Recover() error {
+	jobs, err := d.JobManager.List(storage.TaskFilter{
+		Statuses: []models.TaskStatus{models.TaskStatusPending, models.TaskStatusScheduled},
+	})
+	if err != nil {
+		return err
+	}
+	recovered := 0
+	now := time.Now()
+	for _, job := range jobs {
+		if job.RetryAt != nil && job.RetryAt.After(now) {
+			d.enqueueAfter(job.ID, job.RetryAt.Sub(now), "retry")
+			recovered++
+			continue
+		}
+		if err := d.Enqueue(job.ID, "recovered"); err != nil {
+			d.Logger.Warn("Failed to recover job", zap.String("job_id", job.ID), zap.Error(err))
+			continue
+		}
+		recovered++
+	}
+	d.Logger.Info("Recovered jobs", zap.Int("jobs", recovered))
 	return nil
 }
+
+func (d *Dispatcher) enqueueAfter(jobID string, delay time.Duration, reason string) {
+	time.AfterFunc(delay, func() {
+		if err := d.Enqueue(jobID, reason); err != nil {
+			d.Logger.Warn("Failed to requeue job", zap.String("job_id", jobID), zap.Error(err))
+		}
+	})
+}
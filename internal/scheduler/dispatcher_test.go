@@ -0,0 +1,119 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/config"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/models"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/storage"
+)
+
+// waitStatus polls until a job reaches the status or the deadline passes.
+func waitStatus(t *testing.T, jm *JobManager, id string, want models.TaskStatus, within time.Duration) *Job {
+	t.Helper()
+	deadline := time.Now().Add(within)
+	for {
+		job, err := jm.Get(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job.Status == want {
+			return job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job %s is %s after %s, want %s", id, job.Status, within, want)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// TestRetry checks that a failed job waits out its backoff as scheduled,
+// with retry_at set, and is queued again once it has passed.
+func TestRetry(t *testing.T) {
+	const backoff = 100 * time.Millisecond
+	jm := newTestJobs(t, config.TaskTypeConfig{
+		Name:  "flaky",
+		Retry: config.RetryConfig{MaxAttempts: 2, InitialBackoff: backoff, MaxBackoff: backoff},
+	})
+	d := NewDispatcher(jm, nil, nil, nil, time.Minute, zap.NewNop())
+
+	job := submitQueued(t, jm, JobSpec{Task: "flaky"})
+	attempt := func() {
+		t.Helper()
+		if _, err := jm.Claim("w1", storage.ClaimFilter{IDs: []string{job.ID}}, time.Minute); err != nil {
+			t.Fatal(err)
+		}
+		if err := jm.Start(job.ID, "w1"); err != nil {
+			t.Fatal(err)
+		}
+		if err := d.Fail(job.ID, "w1", "boom", 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	failed := time.Now()
+	attempt()
+	scheduled, err := jm.Get(job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scheduled.Status != models.TaskStatusScheduled || scheduled.RetryAt == nil {
+		t.Fatalf("after the first failure: %s with retry_at %v, want scheduled with retry_at set", scheduled.Status, scheduled.RetryAt)
+	}
+	if wait := scheduled.RetryAt.Sub(failed); wait < backoff || wait > backoff+time.Second {
+		t.Fatalf("retry_at %s after the failure, want %s", wait, backoff)
+	}
+
+	queued := waitStatus(t, jm, job.ID, models.TaskStatusQueued, 2*time.Second)
+	if time.Now().Before(*scheduled.RetryAt) {
+		t.Fatal("queued before retry_at")
+	}
+	if queued.RetryAt != nil {
+		t.Fatalf("queued job kept retry_at %v", queued.RetryAt)
+	}
+
+	// The last attempt is not retried.
+	attempt()
+	waitStatus(t, jm, job.ID, models.TaskStatusDeadLettered, 0)
+}
+
+// TestRecoverRetries checks that on restart a job waiting for a retry is
+// queued once its retry_at passes, and not before, while one whose
+// retry_at passed during the outage is queued at once.
+func TestRecoverRetries(t *testing.T) {
+	jm := newTestJobs(t)
+	d := NewDispatcher(jm, nil, nil, nil, time.Minute, zap.NewNop())
+
+	schedule := func(retryAt time.Time) *Job {
+		t.Helper()
+		job, _, err := jm.Submit(JobSpec{Task: "echo"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		job, err = jm.update(job.ID, func(j *Job) error {
+			if err := transition(j, models.TaskStatusScheduled, models.ActorScheduler, "retry"); err != nil {
+				return err
+			}
+			j.RetryAt = &retryAt
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return job
+	}
+	due := schedule(time.Now().Add(-time.Minute))
+	later := schedule(time.Now().Add(200 * time.Millisecond))
+
+	if err := d.Recover(); err != nil {
+		t.Fatal(err)
+	}
+	waitStatus(t, jm, due.ID, models.TaskStatusQueued, 0)
+	if job, _ := jm.Get(later.ID); job.Status != models.TaskStatusScheduled {
+		t.Fatalf("job retrying later is %s right after recovery, want scheduled", job.Status)
+	}
+	waitStatus(t, jm, later.ID, models.TaskStatusQueued, 2*time.Second)
+}
@@ -0,0 +1,111 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/models"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/storage"
+)
+
+// EventHub fans job events out to waiting PullJob calls, status watchers
+// and the local executor. Events are wakeups only: subscribers re-read
+// state from the JobManager after waking, so a slow subscriber may miss
+// intermediate events but never the latest state.
+type EventHub struct {
+	mu   sync.Mutex
+	next int
+	subs map[int]*subscription
+
+	// PollInterval bounds how long a waiter sleeps without an event, as a
+	// fallback for events lost while a replica's listener was disconnected.
+	PollInterval time.Duration
+}
+
+type subscription struct {
+	ch     chan storage.JobEvent
+	filter func(storage.JobEvent) bool
+}
+
+func NewEventHub(pollInterval time.Duration) *EventHub {
+	return &EventHub{
+		subs:         make(map[int]*subscription),
+		PollInterval: pollInterval,
+	}
+}
+
+// Publish wakes every subscriber whose filter accepts ev.
+func (h *EventHub) Publish(ev storage.JobEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, sub := range h.subs {
+		if sub.filter != nil && !sub.filter(ev) {
+			continue
+		}
+		select {
+		case sub.ch <- ev:
+		default: // already has a pending wakeup
+		}
+	}
+}
+
+// Subscribe registers for events accepted by filter (all events if nil).
+// The returned function must be called to unsubscribe.
+func (h *EventHub) Subscribe(filter func(storage.JobEvent) bool) (<-chan storage.JobEvent, func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	id := h.next
+	h.next++
+	sub := &subscription{ch: make(chan storage.JobEvent, 1), filter: filter}
+	h.subs[id] = sub
+	return sub.ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.subs, id)
+	}
+}
+
+// Wait blocks until an event arrives on ch, the poll interval elapses or
+// ctx is done. It reports false if ctx is done.
+func (h *EventHub) Wait(ctx context.Context, ch <-chan storage.JobEvent) bool {
+	return h.WaitFor(ctx, ch, h.PollInterval)
+}
+
+// WaitFor is Wait with a timeout other than the poll interval.
+func (h *EventHub) WaitFor(ctx context.Context, ch <-chan storage.JobEvent, timeout time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-ch:
+	case <-timer.C:
+	}
+	return true
+}
+
+// queuedEvents accepts events that may make a job available to workers: a
+// job was queued, or a job released the concurrency key it held.
+func queuedEvents(ev storage.JobEvent) bool {
+	return ev.Status == models.TaskStatusQueued || ev.ConcurrencyKey != "" && released(ev)
+}
+
+// releaseEvents is queuedEvents that also accepts any job leaving its
+// worker, which may free a slot under a limit.
+func releaseEvents(ev storage.JobEvent) bool {
+	return released(ev)
+}
+
+// released reports whether the event's job is not held by a worker, which
+// includes jobs that were just queued.
+func released(ev storage.JobEvent) bool {
+	return ev.Status != "" && ev.Status != models.TaskStatusLeased && ev.Status != models.TaskStatusRunning
+}
+
+// jobEvents accepts events for a single job.
+func jobEvents(id string) func(storage.JobEvent) bool {
+	return func(ev storage.JobEvent) bool {
+		return ev.TaskID == id
+	}
+}
@@ -0,0 +1,199 @@
+package scheduler
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/config"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/storage"
+)
+
+// DefaultTenant is the tenant of jobs submitted without one.
+const DefaultTenant = "default"
+
+// TenantShare is the configured share of a tenant.
+type TenantShare struct {
+	Name     string
+	Weight   float64
+	MinShare float64
+}
+
+// FairQueue is the fair_share scheduling policy. It orders dispatch between
+// tenants by weighted fair queuing, so that a tenant with a large backlog
+// cannot starve the others: while several tenants have jobs queued, each is
+// served in proportion to its weight. Tenants below their minimum share of
+// running jobs are served before everyone else. Within a tenant, jobs go as
+// under Priority.
+//
+// Every tenant has a virtual finish time that grows by 1/weight with each
+// job dispatched to it, including jobs dispatched for its minimum share.
+// The tenant with the earliest one goes next. The virtual clock is the
+// earliest start among tenants with jobs queued, and a tenant whose queue
+// ran dry starts again from it, so idle tenants build up no credit.
+// Virtual times are kept by each scheduler replica.
+type FairQueue struct {
+	defaultWeight float64
+	tenants       map[string]TenantShare
+	minShares     bool
+	store         storage.JobStore
+
+	// mu serializes claims, so that virtual times advance in the order
+	// jobs are dispatched.
+	mu     sync.Mutex
+	vtime  float64
+	finish map[string]float64
+}
+
+// NewFairQueue checks the fair-share configuration, failing on tenants
+// without a name or listed twice, on negative weights and on
+// minimum shares that are out of range or add up to more than 1.
+func NewFairQueue(cfg config.FairShareConfig, store storage.JobStore) (*FairQueue, error) {
+	q := &FairQueue{
+		defaultWeight: cfg.DefaultWeight,
+		tenants:       make(map[string]TenantShare),
+		store:         store,
+		finish:        make(map[string]float64),
+	}
+	if q.defaultWeight <= 0 {
+		return nil, errors.New("fair_share: default_weight must be positive")
+	}
+	var minShares float64
+	for _, tc := range cfg.Tenants {
+		switch {
+		case tc.Name == "":
+			return nil, errors.New("fair_share: every tenant needs a name")
+		case q.tenants[tc.Name].Name != "":
+			return nil, fmt.Errorf("fair_share: tenant %q is listed twice", tc.Name)
+		case tc.Weight < 0:
+			return nil, fmt.Errorf("fair_share: tenant %q: weight must not be negative", tc.Name)
+		case tc.MinShare < 0 || tc.MinShare > 1:
+			return nil, fmt.Errorf("fair_share: tenant %q: min_share must be between 0 and 1", tc.Name)
+		}
+		q.tenants[tc.Name] = TenantShare{
+			Name:     tc.Name,
+			Weight:   cmp.Or(tc.Weight, q.defaultWeight),
+			MinShare: tc.MinShare,
+		}
+		minShares += tc.MinShare
+	}
+	if minShares > 1 {
+		return nil, errors.New("fair_share: the min_shares of all tenants add up to more than 1")
+	}
+	q.minShares = minShares > 0
+	return q, nil
+}
+
+func (q *FairQueue) Name() string { return PolicyFairShare }
+
+// Share returns the share of a tenant, listed or not.
+func (q *FairQueue) Share(tenant string) TenantShare {
+	if s, ok := q.tenants[tenant]; ok {
+		return s
+	}
+	return TenantShare{Name: tenant, Weight: q.defaultWeight}
+}
+
+// Next claims from one tenant at a time, in fair-share order, until a
+// tenant has a job the worker may run, and charges that job to its tenant.
+func (q *FairQueue) Next(w *WorkerInfo, ready *ReadySet) (*Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	order, err := q.order()
+	if err != nil {
+		return nil, err
+	}
+	for _, tenant := range order {
+		filter := ready.Filter
+		filter.Tenants = []string{tenant}
+		job, err := ready.Claim(filter)
+		if err != nil {
+			return nil, err
+		}
+		if job != nil {
+			q.charge(tenant)
+			return job, nil
+		}
+	}
+	return nil, nil
+}
+
+// order returns the tenants with queued jobs in the order they should be
+// served: those below their minimum share, furthest below first, then the
+// rest by virtual start time.
+func (q *FairQueue) order() ([]string, error) {
+	queued, err := q.store.QueuedTenants()
+	if err != nil {
+		return nil, err
+	}
+	if len(queued) == 0 {
+		return nil, nil
+	}
+	vtime := math.Inf(1)
+	for tenant := range queued {
+		vtime = min(vtime, q.start(tenant))
+	}
+	q.vtime = vtime
+	for tenant, finish := range q.finish {
+		if queued[tenant] == 0 && finish <= q.vtime {
+			delete(q.finish, tenant)
+		}
+	}
+	deficit, err := q.deficits(queued)
+	if err != nil {
+		return nil, err
+	}
+
+	order := make([]string, 0, len(queued))
+	for tenant := range queued {
+		order = append(order, tenant)
+	}
+	slices.SortFunc(order, func(a, b string) int {
+		da, db := deficit[a], deficit[b]
+		if da > 0 || db > 0 {
+			if c := cmp.Compare(db, da); c != 0 {
+				return c
+			}
+		}
+		return cmp.Or(cmp.Compare(q.start(a), q.start(b)), cmp.Compare(a, b))
+	})
+	return order, nil
+}
+
+// deficits returns, for the queued tenants below their minimum share, the
+// fraction of running jobs they are short of it.
+func (q *FairQueue) deficits(queued map[string]int) (map[string]float64, error) {
+	if !q.minShares {
+		return nil, nil
+	}
+	running, err := q.store.HeldTenants()
+	if err != nil {
+		return nil, err
+	}
+	// Counting the job about to start means a tenant's first job always
+	// counts as below a positive minimum.
+	total := 1.0
+	for _, n := range running {
+		total += float64(n)
+	}
+	deficit := make(map[string]float64)
+	for tenant := range queued {
+		share := q.Share(tenant).MinShare
+		if d := share - float64(running[tenant])/total; share > 0 && d > 0 {
+			deficit[tenant] = d
+		}
+	}
+	return deficit, nil
+}
+
+// start is the virtual time at which the tenant's next job starts.
+func (q *FairQueue) start(tenant string) float64 {
+	return max(q.finish[tenant], q.vtime)
+}
+
+func (q *FairQueue) charge(tenant string) {
+	q.finish[tenant] = q.start(tenant) + 1/q.Share(tenant).Weight
+}
@@ -0,0 +1,126 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/config"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/storage"
+)
+
+// pull has policy hand jobs to w1 until n are dispatched or none is ready,
+// and returns the tenants they were dispatched for in order.
+func pull(t *testing.T, jm *JobManager, policy SchedulingPolicy, n int) []string {
+	t.Helper()
+	w := &WorkerInfo{ID: "w1", AnyTask: true}
+	var tenants []string
+	for range n {
+		job, err := policy.Next(w, &ReadySet{Filter: storage.ClaimFilter{}, workerID: w.ID, jobs: jm, lease: time.Minute})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job == nil {
+			break
+		}
+		tenants = append(tenants, job.Tenant)
+	}
+	return tenants
+}
+
+func count(tenants []string, tenant string) int {
+	n := 0
+	for _, t := range tenants {
+		if t == tenant {
+			n++
+		}
+	}
+	return n
+}
+
+func submitTenant(t *testing.T, jm *JobManager, tenant string, n int) {
+	t.Helper()
+	for range n {
+		submitQueued(t, jm, JobSpec{Task: "echo", Queue: DefaultQueue, Tenant: tenant})
+	}
+}
+
+func newTestFairQueue(t *testing.T, jm *JobManager, tenants ...config.TenantConfig) *FairQueue {
+	t.Helper()
+	q, err := NewFairQueue(config.FairShareConfig{DefaultWeight: 1, Tenants: tenants}, jm.store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return q
+}
+
+func TestFairQueueWeights(t *testing.T) {
+	cases := []struct {
+		name             string
+		weightA, weightB float64
+		wantA            int // of 40 dispatches
+	}{
+		{"equal", 1, 1, 20},
+		{"three to one", 3, 1, 30},
+		{"one to four", 1, 4, 8},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			jm := newTestJobs(t)
+			submitTenant(t, jm, "a", 50)
+			submitTenant(t, jm, "b", 50)
+			q := newTestFairQueue(t, jm,
+				config.TenantConfig{Name: "a", Weight: c.weightA},
+				config.TenantConfig{Name: "b", Weight: c.weightB},
+			)
+			got := count(pull(t, jm, q, 40), "a")
+			if got < c.wantA-1 || got > c.wantA+1 {
+				t.Fatalf("a got %d of 40 dispatches, want %d", got, c.wantA)
+			}
+		})
+	}
+}
+
+func TestFairQueueMinShare(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		minShare float64
+		wantMin  int // of 10 dispatches
+	}{
+		{"without a minimum", 0, 1},
+		{"with half guaranteed", 0.5, 5},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			jm := newTestJobs(t)
+			submitTenant(t, jm, "batch", 20)
+			submitTenant(t, jm, "urgent", 20)
+			// By weight alone, urgent gets one job in eleven.
+			q := newTestFairQueue(t, jm, config.TenantConfig{Name: "urgent", Weight: 0.1, MinShare: c.minShare})
+			got := count(pull(t, jm, q, 10), "urgent")
+			if got < c.wantMin || got > c.wantMin+1 {
+				t.Fatalf("urgent got %d of 10 dispatches, want %d", got, c.wantMin)
+			}
+		})
+	}
+}
+
+func TestFairQueueIdleTenant(t *testing.T) {
+	jm := newTestJobs(t)
+	q := newTestFairQueue(t, jm)
+	submitTenant(t, jm, "a", 40)
+	if got := pull(t, jm, q, 10); count(got, "a") != 10 {
+		t.Fatalf("dispatched %v while only a had jobs", got)
+	}
+
+	// b was idle while a ran ten jobs, and gets no credit for it: from
+	// now on the two take turns.
+	submitTenant(t, jm, "b", 20)
+	got := pull(t, jm, q, 10)
+	if n := count(got, "b"); n < 4 || n > 6 {
+		t.Fatalf("b got %d of 10 dispatches after coming back: %v", n, got)
+	}
+	for i := 2; i < len(got); i++ {
+		if got[i] == got[i-1] && got[i] == got[i-2] {
+			t.Fatalf("%s was dispatched three times in a row: %v", got[i], got)
+		}
+	}
+}
@@ -1,58 +1,367 @@
 package scheduler
 
 import (
+	"cmp"
+	"encoding/json"
+	"errors"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/logsink"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/models"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/storage"
+)
+
+var (
+	// ErrJobNotFound is returned for operations on unknown job IDs.
+	ErrJobNotFound = errors.New("job not found")
+	// ErrLeaseLost is returned when a worker reports on a job it no longer
+	// holds, e.g. after its lease expired and the job was handed to another
+	// worker.
+	ErrLeaseLost = errors.New("job is not held by this worker")
 )
 
+// maxUpdateRetries bounds how often an update is re-applied after losing a
+// race with another writer to the same job.
+const maxUpdateRetries = 3
+
+// JobManager owns job state. Every status change goes through transition,
+// which enforces the models.TaskStatus state machine and records history,
+// and is persisted through the JobStore.
 type JobManager struct {
-	mu   sync.RWMutex
-	jobs map[string]*Job
+	mu     sync.Mutex
+	store  storage.JobStore
+	events *EventHub
+	logs   *logsink.Sinks
+	tasks  *TaskRegistry
 }
 
-func NewJobManager() *JobManager {
+func NewJobManager(store storage.JobStore, events *EventHub, logs *logsink.Sinks, tasks *TaskRegistry) *JobManager {
 	return &JobManager{
-		jobs: make(map[string]*Job),
+		store:  store,
+		events: events,
+		logs:   logs,
+		tasks:  tasks,
 	}
 }
 
-func (jm *JobManager) Submit(task string, args []string) string {
-	jm.mu.Lock()
-	defer jm.mu.Unlock()
+// publish announces a persisted change to local subscribers. Other
+// replicas learn about it through the store's EventSource, if any.
+func (jm *JobManager) publish(job *Job) {
+	jm.events.Publish(storage.JobEvent{TaskID: job.ID, Status: job.Status, ConcurrencyKey: job.ConcurrencyKey})
+}
 
-	id := uuid.New().String()
-	jm.jobs[id] = &Job{
-		ID:     id,
-		Task:   task,
-		Args:   args,
-		Status: "queued",
+// JobSpec describes a job to submit.
+type JobSpec struct {
+	Task     string
+	Args     []string
+	Payload  json.RawMessage
+	Priority int
+	// Tenant is who the job is run for; jobs without one belong to
+	// DefaultTenant.
+	Tenant string
+	// Resources is what the job needs of a worker.
+	Resources models.Resources
+	Inputs    []models.JobInput
+	Outputs   []models.JobOutput
+	// Cacheable, if set, overrides the task type's setting.
+	Cacheable *bool
+	// ConcurrencyKey, if set, keeps more than ConcurrencyLimit jobs with
+	// the key, 1 if unset, from running at once.
+	ConcurrencyKey   string
+	ConcurrencyLimit int
+	// Unique, if set, makes the submission return an existing job instead
+	// of a duplicate.
+	Unique *UniquePolicy
+	// Queue, Timeout and CacheKey are filled in from the task type by
+	// TaskRegistry.Resolve; a zero Timeout means no limit, and an empty
+	// CacheKey that the job is not cacheable.
+	Queue    string
+	Timeout  time.Duration
+	CacheKey string
+}
+
+// Submit creates a pending job. A cacheable job with a reusable result in
+// the cache is instead created succeeded, with that result. A unique job
+// with a duplicate creates nothing: the duplicate is returned, with
+// existing set.
+func (jm *JobManager) Submit(spec JobSpec) (job *Job, existing bool, err error) {
+	now := time.Now()
+	job = &Job{
+		ID:               uuid.New().String(),
+		Type:             spec.Task,
+		Args:             spec.Args,
+		Payload:          spec.Payload,
+		Priority:         spec.Priority,
+		Tenant:           cmp.Or(spec.Tenant, DefaultTenant),
+		Resources:        spec.Resources,
+		Inputs:           spec.Inputs,
+		Outputs:          spec.Outputs,
+		Queue:            spec.Queue,
+		Timeout:          spec.Timeout,
+		CacheKey:         spec.CacheKey,
+		ConcurrencyKey:   spec.ConcurrencyKey,
+		ConcurrencyLimit: spec.ConcurrencyLimit,
+		Status:           models.TaskStatusPending,
+		CreatedAt:        now,
+		History: []models.Transition{{
+			To:     models.TaskStatusPending,
+			At:     now,
+			Actor:  models.ActorClient,
+			Reason: "submitted",
+		}},
+	}
+	if spec.CacheKey != "" {
+		cached, err := jm.cachedResult(spec.CacheKey)
+		if err != nil {
+			return nil, false, err
+		}
+		if cached != nil {
+			if err := transition(job, models.TaskStatusSucceeded, models.ActorScheduler, "cache hit: reused the result of job "+cached.ID); err != nil {
+				return nil, false, err
+			}
+			job.Result = cached.Result
+			job.Output = cached.Output
+			job.CachedFrom = cached.ID
+		}
+	}
+	if spec.Unique != nil {
+		job.UniqueKey = cmp.Or(spec.Unique.Key, UniqueKey(spec.Task, spec.Args, spec.Payload))
+		dup, err := jm.store.SaveUniqueTask(job, spec.Unique.duplicates(job))
+		if err != nil {
+			return nil, false, err
+		}
+		if dup != nil {
+			return dup, true, nil
+		}
+	} else if err := jm.store.SaveTask(job); err != nil {
+		return nil, false, err
 	}
-	return id
+	jm.publish(job)
+	return job, false, nil
 }
 
-func (jm *JobManager) Get(id string) (*Job, bool) {
-	jm.mu.RLock()
-	defer jm.mu.RUnlock()
-	job, ok := jm.jobs[id]
-	return job, ok
+// Get returns a snapshot of the job.
+func (jm *JobManager) Get(id string) (*Job, error) {
+	job, err := jm.store.GetTask(id)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, ErrJobNotFound
+	}
+	return job, err
 }
 
-func (jm *JobManager) SetStatus(id, status string) {
-	jm.mu.Lock()
-	defer jm.mu.Unlock()
-	if job, ok := jm.jobs[id]; ok {
-		job.Status = status
+// List returns snapshots of the jobs matching filter, oldest first.
+func (jm *JobManager) List(filter storage.TaskFilter) ([]*Job, error) {
+	return jm.store.ListTasks(filter)
+}
+
+// History returns the job's recorded transitions.
+func (jm *JobManager) History(id string) ([]models.Transition, error) {
+	job, err := jm.Get(id)
+	if err != nil {
+		return nil, err
 	}
+	return job.History, nil
+}
+
+// Transition moves a job to the given state on behalf of actor.
+func (jm *JobManager) Transition(id string, to models.TaskStatus, actor, reason string) error {
+	_, err := jm.update(id, func(job *Job) error {
+		return transition(job, to, actor, reason)
+	})
+	return err
 }
 
-func (jm *JobManager) Complete(id string, result string) bool {
+// update applies fn to the latest version of a job and persists the result,
+// re-reading and re-applying fn if another writer got there first.
+func (jm *JobManager) update(id string, fn func(job *Job) error) (*Job, error) {
 	jm.mu.Lock()
 	defer jm.mu.Unlock()
-	if job, ok := jm.jobs[id]; ok {
-		job.Status = "completed"
+
+	for attempt := 0; ; attempt++ {
+		job, err := jm.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		if err := fn(job); err != nil {
+			return nil, err
+		}
+		err = jm.store.UpdateTask(job)
+		if errors.Is(err, storage.ErrConflict) && attempt < maxUpdateRetries {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		jm.publish(job)
+		return job, nil
+	}
+}
+
+func transition(job *Job, to models.TaskStatus, actor, reason string) error {
+	if !job.Status.CanTransition(to) {
+		return &models.InvalidTransitionError{From: job.Status, To: to}
+	}
+	now := time.Now()
+	job.History = append(job.History, models.Transition{
+		From:   job.Status,
+		To:     to,
+		At:     now,
+		Actor:  actor,
+		Reason: reason,
+	})
+	job.Status = to
+
+	switch to {
+	case models.TaskStatusQueued:
+		job.QueuedAt = &now
+	case models.TaskStatusLeased:
+		job.StartedAt = nil
+		job.CompletedAt = nil
+	case models.TaskStatusRunning:
+		job.StartedAt = &now
+	case models.TaskStatusSucceeded, models.TaskStatusFailed, models.TaskStatusCancelled, models.TaskStatusTimedOut:
+		job.CompletedAt = &now
+	}
+	if to != models.TaskStatusLeased && to != models.TaskStatusRunning {
+		job.LeaseExpiresAt = nil
+	}
+	if to != models.TaskStatusScheduled {
+		job.RetryAt = nil
+	}
+	return nil
+}
+
+// Claim leases the next queued job the filter allows to a worker for the
+// given duration. It returns nil if no such job is queued.
+func (jm *JobManager) Claim(workerID string, filter storage.ClaimFilter, lease time.Duration) (*Job, error) {
+	job, err := jm.store.ClaimTask(filter, func(job *Job) error {
+		if err := transition(job, models.TaskStatusLeased, models.WorkerActor(workerID), "pulled"); err != nil {
+			return err
+		}
+		expires := time.Now().Add(lease)
+		job.Attempts++
+		job.WorkerID = workerID
+		job.LeaseExpiresAt = &expires
+		return nil
+	})
+	if err != nil || job == nil {
+		return nil, err
+	}
+	jm.publish(job)
+	return job, nil
+}
+
+// KeyHolders returns the IDs of the leased and running jobs holding a
+// concurrency key.
+func (jm *JobManager) KeyHolders(key string) ([]string, error) {
+	jobs, err := jm.store.ListTasks(storage.TaskFilter{
+		Statuses:       []models.TaskStatus{models.TaskStatusLeased, models.TaskStatusRunning},
+		ConcurrencyKey: key,
+	})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(jobs))
+	for i, job := range jobs {
+		ids[i] = job.ID
+	}
+	return ids, nil
+}
+
+// RenewLeases extends the leases of all jobs held by a worker.
+func (jm *JobManager) RenewLeases(workerID string, lease time.Duration) error {
+	return jm.store.RenewLeases(workerID, time.Now().Add(lease))
+}
+
+// ExpiredLeases returns jobs whose worker stopped renewing its lease.
+func (jm *JobManager) ExpiredLeases() ([]*Job, error) {
+	return jm.store.ExpiredLeases(time.Now())
+}
+
+// held rejects reports from a worker that does not hold the job.
+func held(job *Job, workerID string) error {
+	if job.WorkerID != workerID {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// Start marks a leased job as running.
+func (jm *JobManager) Start(id, workerID string) error {
+	_, err := jm.update(id, func(job *Job) error {
+		if err := held(job, workerID); err != nil {
+			return err
+		}
+		return transition(job, models.TaskStatusRunning, models.WorkerActor(workerID), "started")
+	})
+	return err
+}
+
+// Complete marks a running job as succeeded, recording its result and its
+// structured output, if any.
+func (jm *JobManager) Complete(id, workerID, result string, output json.RawMessage) error {
+	_, err := jm.update(id, func(job *Job) error {
+		if err := held(job, workerID); err != nil {
+			return err
+		}
+		if err := transition(job, models.TaskStatusSucceeded, models.WorkerActor(workerID), "completed"); err != nil {
+			return err
+		}
 		job.Result = result
-		return true
+		job.Output = output
+		job.ExitCode = 0
+		return nil
+	})
+	return err
+}
+
+// Fail marks a job as failed and dead-letters it once it has used all of
+// its attempts. It returns the updated job and whether it should be retried.
+func (jm *JobManager) Fail(id, workerID, errMsg string, exitCode int) (*Job, bool, error) {
+	return jm.endAttempt(id, models.TaskStatusFailed, models.WorkerActor(workerID), errMsg, func(job *Job) error {
+		if err := held(job, workerID); err != nil {
+			return err
+		}
+		job.Error = errMsg
+		job.ExitCode = exitCode
+		return nil
+	})
+}
+
+// TimeOut marks a running job whose lease expired as timed out, with the
+// same retry accounting as Fail.
+func (jm *JobManager) TimeOut(id, reason string) (*Job, bool, error) {
+	return jm.endAttempt(id, models.TaskStatusTimedOut, models.ActorScheduler, reason, func(job *Job) error {
+		job.Error = reason
+		return nil
+	})
+}
+
+// endAttempt moves a job to failed or timed_out, then to dead_lettered if
+// no attempts are left.
+func (jm *JobManager) endAttempt(id string, to models.TaskStatus, actor, reason string, fn func(job *Job) error) (*Job, bool, error) {
+	job, err := jm.update(id, func(job *Job) error {
+		if err := fn(job); err != nil {
+			return err
+		}
+		if err := transition(job, to, actor, reason); err != nil {
+			return err
+		}
+		if job.Attempts < jm.tasks.Retry(job.Type).MaxAttempts {
+			return nil
+		}
+		return transition(job, models.TaskStatusDeadLettered, models.ActorScheduler, "max attempts exhausted")
+	})
+	if err != nil {
+		return nil, false, err
 	}
-	return false
+	return job, job.Status == to, nil
+}
+
+// Cancel moves a job that has not finished to cancelled.
+func (jm *JobManager) Cancel(id, actor, reason string) error {
+	return jm.Transition(id, models.TaskStatusCancelled, actor, reason)
 }
@@ -0,0 +1,105 @@
+package scheduler
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/config"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/models"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/storage"
+)
+
+func newTestRegistry(t *testing.T, types ...config.TaskTypeConfig) *TaskRegistry {
+	t.Helper()
+	r, err := NewTaskRegistry(config.TaskTypesConfig{Types: types}, config.RetryConfig{MaxAttempts: 3}, config.CacheConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+// newTestJobs returns a JobManager over a fresh memory store, with the
+// given task types declared.
+func newTestJobs(t *testing.T, types ...config.TaskTypeConfig) *JobManager {
+	t.Helper()
+	return NewJobManager(storage.NewMemoryStore(), NewEventHub(time.Second), nil, newTestRegistry(t, types...))
+}
+
+// submitQueued submits a job and queues it.
+func submitQueued(t *testing.T, jm *JobManager, spec JobSpec) *Job {
+	t.Helper()
+	job, _, err := jm.Submit(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := jm.Transition(job.ID, models.TaskStatusQueued, models.ActorScheduler, "queued"); err != nil {
+		t.Fatal(err)
+	}
+	return job
+}
+
+func TestTransition(t *testing.T) {
+	cases := []struct {
+		from, to models.TaskStatus
+		allowed  bool
+	}{
+		{models.TaskStatusPending, models.TaskStatusQueued, true},
+		{models.TaskStatusPending, models.TaskStatusScheduled, true},
+		{models.TaskStatusPending, models.TaskStatusSucceeded, true}, // cache hit
+		{models.TaskStatusPending, models.TaskStatusLeased, false},
+		{models.TaskStatusPending, models.TaskStatusRunning, false},
+		{models.TaskStatusScheduled, models.TaskStatusQueued, true},
+		{models.TaskStatusScheduled, models.TaskStatusCancelled, true},
+		{models.TaskStatusScheduled, models.TaskStatusLeased, false},
+		{models.TaskStatusQueued, models.TaskStatusLeased, true},
+		{models.TaskStatusQueued, models.TaskStatusRunning, false},
+		{models.TaskStatusQueued, models.TaskStatusSucceeded, false},
+		{models.TaskStatusLeased, models.TaskStatusRunning, true},
+		{models.TaskStatusLeased, models.TaskStatusQueued, true}, // lease expired before start
+		{models.TaskStatusLeased, models.TaskStatusSucceeded, false},
+		{models.TaskStatusRunning, models.TaskStatusSucceeded, true},
+		{models.TaskStatusRunning, models.TaskStatusFailed, true},
+		{models.TaskStatusRunning, models.TaskStatusTimedOut, true},
+		{models.TaskStatusRunning, models.TaskStatusQueued, false},
+		{models.TaskStatusFailed, models.TaskStatusScheduled, true}, // retry after backoff
+		{models.TaskStatusFailed, models.TaskStatusQueued, true},    // retry at once
+		{models.TaskStatusFailed, models.TaskStatusDeadLettered, true},
+		{models.TaskStatusFailed, models.TaskStatusRunning, false},
+		{models.TaskStatusTimedOut, models.TaskStatusScheduled, true},
+		{models.TaskStatusTimedOut, models.TaskStatusDeadLettered, true},
+		{models.TaskStatusSucceeded, models.TaskStatusQueued, false},
+		{models.TaskStatusCancelled, models.TaskStatusQueued, false},
+		{models.TaskStatusDeadLettered, models.TaskStatusScheduled, false},
+	}
+	for _, c := range cases {
+		t.Run(string(c.from)+"->"+string(c.to), func(t *testing.T) {
+			retryAt := time.Now().Add(time.Minute)
+			job := &Job{ID: "j1", Status: c.from, RetryAt: &retryAt}
+			err := transition(job, c.to, models.ActorScheduler, "test")
+			if !c.allowed {
+				var invalid *models.InvalidTransitionError
+				if !errors.As(err, &invalid) || invalid.From != c.from || invalid.To != c.to {
+					t.Fatalf("transition = %v, want an InvalidTransitionError", err)
+				}
+				if job.Status != c.from || len(job.History) != 0 {
+					t.Fatalf("rejected transition changed the job to %s with history %v", job.Status, job.History)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("transition: %v", err)
+			}
+			if job.Status != c.to {
+				t.Fatalf("status %s, want %s", job.Status, c.to)
+			}
+			if n := len(job.History); n != 1 || job.History[0].From != c.from || job.History[0].To != c.to || job.History[0].Actor != models.ActorScheduler {
+				t.Fatalf("history %+v, want one %s -> %s entry by the scheduler", job.History, c.from, c.to)
+			}
+			// Only a job waiting for a retry keeps its retry time.
+			if (job.RetryAt != nil) != (c.to == models.TaskStatusScheduled) {
+				t.Fatalf("retry_at = %v after moving to %s", job.RetryAt, c.to)
+			}
+		})
+	}
+}
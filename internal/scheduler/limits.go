@@ -0,0 +1,275 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/config"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/storage"
+)
+
+// Limit throttles the jobs of a task type, or of every type in a queue:
+// at most MaxRunning of them may be leased or running at once, and they
+// start at most Rate times per second. Zero leaves either off.
+type Limit struct {
+	Task       string
+	Queue      string
+	MaxRunning int
+	Rate       float64
+	Burst      int
+}
+
+// Kind is "task" or "queue".
+func (l *Limit) Kind() string {
+	if l.Task != "" {
+		return "task"
+	}
+	return "queue"
+}
+
+// Name is the task type or queue the limit applies to.
+func (l *Limit) Name() string {
+	if l.Task != "" {
+		return l.Task
+	}
+	return l.Queue
+}
+
+func (l *Limit) String() string {
+	return fmt.Sprintf("%s %q", l.Kind(), l.Name())
+}
+
+// startRate is the limit's Rate and Burst as the store checks them.
+func (l *Limit) startRate() storage.StartRate {
+	return storage.StartRate{Rate: l.Rate, Burst: l.Burst}
+}
+
+func (l *Limit) applies(job *Job) bool {
+	if l.Task != "" {
+		return job.Type == l.Task
+	}
+	return job.Queue == l.Queue
+}
+
+// LimitUsage is the state of a limit at one point in time.
+type LimitUsage struct {
+	Limit   *Limit
+	Running int
+	// Tokens is how many jobs may start now under the rate; it is only
+	// meaningful for limits with one.
+	Tokens float64
+}
+
+// Throttled explains why no more jobs may start under the limit, or
+// returns "" if they may.
+func (u LimitUsage) Throttled() string {
+	l := u.Limit
+	switch {
+	case l.MaxRunning > 0 && u.Running >= l.MaxRunning:
+		return fmt.Sprintf("%s has %d of %d jobs running", l, u.Running, l.MaxRunning)
+	case l.Rate > 0 && u.Tokens < 1:
+		return fmt.Sprintf("%s is limited to %s starts per second", l, strconv.FormatFloat(l.Rate, 'g', -1, 64))
+	}
+	return ""
+}
+
+// Limiter enforces the configured limits in the dispatch path. They are
+// passed to the store, which checks them and counts starts in the claim
+// itself, so both concurrency limits and start rates hold across scheduler
+// replicas.
+type Limiter struct {
+	limits []*Limit
+	store  storage.JobStore
+}
+
+// NewLimiter checks the configured limits, failing on ones that name
+// neither or both of a task type and a queue, or that limit nothing.
+func NewLimiter(cfg []config.LimitConfig, store storage.JobStore) (*Limiter, error) {
+	l := &Limiter{store: store}
+	seen := make(map[string]bool)
+	for _, lc := range cfg {
+		limit := &Limit{
+			Task:       lc.Task,
+			Queue:      lc.Queue,
+			MaxRunning: lc.MaxRunning,
+			Rate:       lc.Rate,
+			Burst:      max(lc.Burst, 1),
+		}
+		switch {
+		case (lc.Task == "") == (lc.Queue == ""):
+			return nil, errors.New("limits: every limit needs either a task or a queue")
+		case seen[limit.String()]:
+			return nil, fmt.Errorf("limits: %s is limited twice", limit)
+		case lc.MaxRunning < 0 || lc.Rate < 0 || lc.Burst < 0:
+			return nil, fmt.Errorf("limits: %s: max_running, rate and burst must not be negative", limit)
+		case lc.MaxRunning == 0 && lc.Rate == 0:
+			return nil, fmt.Errorf("limits: %s needs a max_running or a rate", limit)
+		}
+		seen[limit.String()] = true
+		l.limits = append(l.limits, limit)
+	}
+	return l, nil
+}
+
+// Limits returns the configured limits.
+func (l *Limiter) Limits() []*Limit {
+	return l.limits
+}
+
+// Usage returns the current state of every limit.
+func (l *Limiter) Usage() ([]LimitUsage, error) {
+	if len(l.limits) == 0 {
+		return nil, nil
+	}
+	byType, byQueue, err := l.store.HeldCounts()
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := l.tokens(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	usage := make([]LimitUsage, len(l.limits))
+	for i, limit := range l.limits {
+		usage[i].Limit = limit
+		if limit.Task != "" {
+			usage[i].Running = byType[limit.Task]
+		} else {
+			usage[i].Running = byQueue[limit.Queue]
+		}
+		usage[i].Tokens = tokens[limit]
+	}
+	return usage, nil
+}
+
+// tokens returns how many starts each limit with a rate allows at now.
+func (l *Limiter) tokens(now time.Time) (map[*Limit]float64, error) {
+	tokens := make(map[*Limit]float64)
+	if !slices.ContainsFunc(l.limits, func(limit *Limit) bool { return limit.Rate > 0 }) {
+		return tokens, nil
+	}
+	byType, byQueue, err := l.store.LimitStarts()
+	if err != nil {
+		return nil, err
+	}
+	for _, limit := range l.limits {
+		switch {
+		case limit.Rate == 0:
+		case limit.Task != "":
+			tokens[limit] = limit.startRate().Tokens(byType[limit.Task], now)
+		default:
+			tokens[limit] = limit.startRate().Tokens(byQueue[limit.Queue], now)
+		}
+	}
+	return tokens, nil
+}
+
+// Throttled explains why a queued job is held back by a limit, or returns
+// "" if it is not.
+func (l *Limiter) Throttled(job *Job) (string, error) {
+	usage, err := l.Usage()
+	if err != nil {
+		return "", err
+	}
+	for _, u := range usage {
+		if reason := u.Throttled(); reason != "" && u.Limit.applies(job) {
+			return reason, nil
+		}
+	}
+	return "", nil
+}
+
+// Claim calls claim with the limits set as the filter's caps and rates.
+func (l *Limiter) Claim(filter storage.ClaimFilter, claim func(storage.ClaimFilter) (*Job, error)) (*Job, error) {
+	if len(l.limits) == 0 {
+		return claim(filter)
+	}
+	filter.TypeLimits = make(map[string]int)
+	filter.QueueLimits = make(map[string]int)
+	filter.TypeRates = make(map[string]storage.StartRate)
+	filter.QueueRates = make(map[string]storage.StartRate)
+	for _, limit := range l.limits {
+		caps, rates := filter.QueueLimits, filter.QueueRates
+		if limit.Task != "" {
+			caps, rates = filter.TypeLimits, filter.TypeRates
+		}
+		if limit.MaxRunning > 0 {
+			caps[limit.Name()] = limit.MaxRunning
+		}
+		if limit.Rate > 0 {
+			rates[limit.Name()] = limit.startRate()
+		}
+	}
+	return claim(filter)
+}
+
+// NextStart returns how long until a start rate that is exhausted allows
+// another start, or 0 if none is exhausted or the store cannot be read.
+func (l *Limiter) NextStart() time.Duration {
+	tokens, err := l.tokens(time.Now())
+	if err != nil {
+		return 0
+	}
+	var next time.Duration
+	for limit, t := range tokens {
+		if missing := 1 - t; missing > 0 {
+			wait := time.Duration(math.Ceil(missing / limit.Rate * float64(time.Second)))
+			if next == 0 || wait < next {
+				next = wait
+			}
+		}
+	}
+	return next
+}
+
+// WriteMetrics writes the usage of every limit in the Prometheus text
+// exposition format.
+func (l *Limiter) WriteMetrics(w io.Writer) error {
+	usage, err := l.Usage()
+	if err != nil {
+		return err
+	}
+	gauges := []struct {
+		name, help string
+		value      func(LimitUsage) (float64, bool)
+	}{
+		{"orchestrator_limit_running", "Jobs leased or running under a limit.", func(u LimitUsage) (float64, bool) {
+			return float64(u.Running), true
+		}},
+		{"orchestrator_limit_max_running", "Most jobs a limit lets run at once.", func(u LimitUsage) (float64, bool) {
+			return float64(u.Limit.MaxRunning), u.Limit.MaxRunning > 0
+		}},
+		{"orchestrator_limit_start_rate", "Job starts per second a limit allows.", func(u LimitUsage) (float64, bool) {
+			return u.Limit.Rate, u.Limit.Rate > 0
+		}},
+		{"orchestrator_limit_start_tokens", "Job starts a limit allows right now.", func(u LimitUsage) (float64, bool) {
+			return u.Tokens, u.Limit.Rate > 0
+		}},
+		{"orchestrator_limit_throttled", "Whether a limit is holding jobs back.", func(u LimitUsage) (float64, bool) {
+			if u.Throttled() != "" {
+				return 1, true
+			}
+			return 0, true
+		}},
+	}
+	for _, g := range gauges {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", g.name, g.help, g.name); err != nil {
+			return err
+		}
+		for _, u := range usage {
+			v, ok := g.value(u)
+			if !ok {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "%s{kind=%q,name=%q} %s\n", g.name, u.Limit.Kind(), u.Limit.Name(), strconv.FormatFloat(v, 'g', -1, 64)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package scheduler
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/config"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/storage"
+)
+
+// limitedClaimer claims jobs for w1 through a Limiter over jm's store, as
+// one scheduler replica would.
+func limitedClaimer(t *testing.T, jm *JobManager, limits ...config.LimitConfig) (*Limiter, func() *Job) {
+	t.Helper()
+	l, err := NewLimiter(limits, jm.store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l, func() *Job {
+		t.Helper()
+		job, err := l.Claim(storage.ClaimFilter{}, func(filter storage.ClaimFilter) (*Job, error) {
+			return jm.Claim("w1", filter, time.Minute)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return job
+	}
+}
+
+func claimedID(job *Job) string {
+	if job == nil {
+		return ""
+	}
+	return job.ID
+}
+
+func TestLimiterMaxRunning(t *testing.T) {
+	jm := newTestJobs(t)
+	first := submitQueued(t, jm, JobSpec{Task: "build", Queue: DefaultQueue, Priority: 3})
+	second := submitQueued(t, jm, JobSpec{Task: "build", Queue: DefaultQueue, Priority: 2})
+	other := submitQueued(t, jm, JobSpec{Task: "echo", Queue: DefaultQueue, Priority: 1})
+	l, claim := limitedClaimer(t, jm, config.LimitConfig{Task: "build", MaxRunning: 1})
+	_, replica := limitedClaimer(t, jm, config.LimitConfig{Task: "build", MaxRunning: 1})
+
+	if got := claimedID(claim()); got != first.ID {
+		t.Fatalf("claimed %q, want the first build", got)
+	}
+	// The running build holds the only slot, on every replica.
+	if got := claimedID(replica()); got != other.ID {
+		t.Fatalf("claimed %q on another replica, want the echo job", got)
+	}
+	if got := claimedID(claim()); got != "" {
+		t.Fatalf("claimed %q over the limit", got)
+	}
+	reason, err := l.Throttled(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(reason, "1 of 1 jobs running") {
+		t.Fatalf("throttled reason = %q", reason)
+	}
+
+	if err := jm.Start(first.ID, "w1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := jm.Complete(first.ID, "w1", "done", nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := claimedID(claim()); got != second.ID {
+		t.Fatalf("claimed %q after the first build finished, want the second", got)
+	}
+}
+
+func TestLimiterRate(t *testing.T) {
+	jm := newTestJobs(t)
+	first := submitQueued(t, jm, JobSpec{Task: "echo", Queue: "ci", Priority: 3})
+	second := submitQueued(t, jm, JobSpec{Task: "echo", Queue: "ci", Priority: 2})
+	other := submitQueued(t, jm, JobSpec{Task: "echo", Queue: DefaultQueue, Priority: 1})
+	// One start per thousand seconds in ci.
+	l, claim := limitedClaimer(t, jm, config.LimitConfig{Queue: "ci", Rate: 0.001})
+	_, replica := limitedClaimer(t, jm, config.LimitConfig{Queue: "ci", Rate: 0.001})
+
+	if next := l.NextStart(); next != 0 {
+		t.Fatalf("next start in %v before any start", next)
+	}
+	if got := claimedID(claim()); got != first.ID {
+		t.Fatalf("claimed %q, want the first ci job", got)
+	}
+	// The start is counted on every replica.
+	if got := claimedID(replica()); got != other.ID {
+		t.Fatalf("claimed %q on another replica, want the default queue job", got)
+	}
+	if got := claimedID(claim()); got != "" {
+		t.Fatalf("claimed %q over the rate", got)
+	}
+	reason, err := l.Throttled(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(reason, "starts per second") {
+		t.Fatalf("throttled reason = %q", reason)
+	}
+	if next := l.NextStart(); next < 990*time.Second || next > 1000*time.Second {
+		t.Fatalf("next start in %v, want about 1000s", next)
+	}
+}
@@ -0,0 +1,144 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/models"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/worker"
+)
+
+// LocalWorkerID identifies the embedded local executor in dispatch logs.
+const LocalWorkerID = "local"
+
+// LocalExecutor runs jobs inside the scheduler process for single-binary
+// development. It takes jobs from the same ready queue as remote workers and
+// runs them through the same worker.Executor interface.
+type LocalExecutor struct {
+	Dispatcher  *Dispatcher
+	Jobs        *JobManager
+	Artifacts   *ArtifactStore
+	Executors   *worker.Registry
+	Workspaces  *worker.Workspaces
+	Concurrency int
+	Logger      *zap.Logger
+}
+
+func NewLocalExecutor(d *Dispatcher, artifacts *ArtifactStore, executors *worker.Registry, concurrency int, workDir string, logger *zap.Logger) *LocalExecutor {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &LocalExecutor{
+		Dispatcher: d,
+		Jobs:       d.JobManager,
+		Artifacts:  artifacts,
+		Executors:  executors,
+		Workspaces: &worker.Workspaces{
+			Root: workDir,
+			Open: func(ctx context.Context, in models.JobInput) (io.ReadCloser, string, error) {
+				art, body, err := artifacts.OpenInput(ctx, in)
+				if err != nil {
+					return nil, "", err
+				}
+				return body, art.SHA256, nil
+			},
+		},
+		Concurrency: concurrency,
+		Logger:      logger,
+	}
+}
+
+// Run executes ready jobs until ctx is cancelled. Like a remote worker, the
+// executor registers the tasks it runs and is only handed jobs of those.
+func (l *LocalExecutor) Run(ctx context.Context) {
+	tasks, anyTask := l.Executors.Capabilities()
+	if err := l.Dispatcher.Workers.Register(LocalWorkerID, LocalWorkerID, tasks, anyTask, models.Resources{}); err != nil {
+		l.Logger.Error("Failed to register local executor", zap.Error(err))
+		return
+	}
+	go l.renewLeases(ctx)
+
+	var wg sync.WaitGroup
+	for i := 0; i < l.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l.loop(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+func (l *LocalExecutor) loop(ctx context.Context) {
+	for {
+		job := l.Dispatcher.WaitForJob(ctx, LocalWorkerID)
+		if job == nil {
+			return
+		}
+		l.execute(ctx, job)
+	}
+}
+
+// renewLeases stands in for the heartbeats a remote worker would send.
+func (l *LocalExecutor) renewLeases(ctx context.Context) {
+	ticker := time.NewTicker(l.Dispatcher.LeaseDuration / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			l.Dispatcher.Workers.Heartbeat(LocalWorkerID)
+			l.Dispatcher.Heartbeat(LocalWorkerID)
+		}
+	}
+}
+
+func (l *LocalExecutor) execute(ctx context.Context, job *Job) {
+	logger := l.Logger.With(zap.String("job_id", job.ID), zap.String("worker", LocalWorkerID))
+
+	if err := l.Jobs.Start(job.ID, LocalWorkerID); err != nil {
+		logger.Warn("Failed to start job", zap.Error(err))
+		return
+	}
+
+	res, err := l.Workspaces.Execute(ctx, l.Executors, &worker.Job{
+		ID:      job.ID,
+		Task:    job.Type,
+		Args:    job.Args,
+		Payload: job.Payload,
+		Timeout: job.Timeout,
+		Inputs:  job.Inputs,
+		Outputs: job.Outputs,
+		Log: func(line string) {
+			if err := l.Jobs.AppendLogs(job.ID, []models.LogLine{{At: time.Now(), Message: line}}); err != nil {
+				logger.Warn("Failed to store job log", zap.Error(err))
+			}
+		},
+		SaveArtifact: func(name, contentType string, r io.Reader) error {
+			_, err := l.Artifacts.Upload(ctx, job.ID, LocalWorkerID, name, contentType, r)
+			return err
+		},
+	}, job.Attempts)
+	var output json.RawMessage
+	if err == nil {
+		output, err = res.DataJSON()
+	}
+	if err != nil {
+		logger.Error("Job failed", zap.Error(err))
+		if err := l.Dispatcher.Fail(job.ID, LocalWorkerID, err.Error(), worker.ExitCode(err)); err != nil {
+			logger.Warn("Failed to record job failure", zap.Error(err))
+		}
+		return
+	}
+	if err := l.Jobs.Complete(job.ID, LocalWorkerID, res.Output, output); err != nil {
+		logger.Warn("Failed to complete job", zap.Error(err))
+		return
+	}
+	logger.Info("Job completed")
+}
@@ -0,0 +1,89 @@
+package scheduler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/models"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/storage"
+)
+
+// maxLogPage caps how many log lines one read returns.
+const maxLogPage = 1000
+
+// ShippedLine is a log line sent by a worker. Seq numbers the lines of one
+// job attempt from 1; a zero Seq is never treated as a duplicate.
+type ShippedLine struct {
+	JobID   string
+	Attempt int
+	Seq     int64
+	At      time.Time
+	Message string
+}
+
+// AppendLogs sends output lines for a job to the log sinks and wakes
+// anyone tailing its log. The database sink sets the lines' Seq.
+func (jm *JobManager) AppendLogs(id string, lines []models.LogLine) error {
+	if len(lines) == 0 {
+		return nil
+	}
+	if err := jm.logs.Write(id, lines); err != nil {
+		return err
+	}
+	jm.events.Publish(storage.JobEvent{TaskID: id, Logs: true})
+	return nil
+}
+
+// ShipLogs stores lines shipped by workers. Lines at or below the highest
+// Seq already stored for their job attempt are duplicates of a resent batch
+// and are dropped by the database sink, which keeps that Seq in the job
+// store, so a batch resent after a restart or to another replica is still
+// stored once. Lines of unknown jobs are dropped too: a spooled line can
+// outlive its job.
+func (jm *JobManager) ShipLogs(lines []ShippedLine) error {
+	var order []string
+	byJob := make(map[string][]models.LogLine)
+	known := make(map[string]bool)
+	for _, l := range lines {
+		ok, seen := known[l.JobID]
+		if !seen {
+			_, err := jm.Get(l.JobID)
+			if err != nil && !errors.Is(err, ErrJobNotFound) {
+				return err
+			}
+			ok = err == nil
+			known[l.JobID] = ok
+			if ok {
+				order = append(order, l.JobID)
+			}
+		}
+		if !ok {
+			continue
+		}
+		byJob[l.JobID] = append(byJob[l.JobID], models.LogLine{
+			At:      l.At,
+			Message: l.Message,
+			Attempt: l.Attempt,
+			ShipSeq: l.Seq,
+		})
+	}
+
+	for _, id := range order {
+		if err := jm.AppendLogs(id, byJob[id]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Logs returns up to limit lines of a job's log after sequence number
+// after. A limit of 0 or above maxLogPage reads maxLogPage lines.
+func (jm *JobManager) Logs(id string, after int64, limit int) ([]models.LogLine, error) {
+	if _, err := jm.Get(id); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > maxLogPage {
+		limit = maxLogPage
+	}
+	return jm.logs.Read(id, after, limit)
+}
@@ -0,0 +1,94 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/config"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/storage"
+)
+
+// The scheduling policies, by their names in scheduler.policy.
+const (
+	PolicyFIFO       = "fifo"
+	PolicyPriority   = "priority"
+	PolicyFairShare  = "fair_share"
+	PolicyBinPacking = "bin_packing"
+)
+
+// SchedulingPolicy decides which ready job a worker gets. The dispatcher
+// calls Next whenever a worker asks for work; the policy claims one of the
+// jobs in ready for it, or none to leave the worker idle until the next
+// job event.
+type SchedulingPolicy interface {
+	// Name is the policy's name in scheduler.policy.
+	Name() string
+	Next(w *WorkerInfo, ready *ReadySet) (*Job, error)
+}
+
+// NewPolicy returns the policy selected by scheduler.policy, priority if
+// it is unset.
+func NewPolicy(cfg *config.Config, store storage.JobStore) (SchedulingPolicy, error) {
+	switch cfg.Scheduler.Policy {
+	case PolicyFIFO:
+		return FIFO{}, nil
+	case PolicyPriority, "":
+		return Priority{}, nil
+	case PolicyFairShare:
+		fair, err := NewFairQueue(cfg.FairShare, store)
+		if err != nil {
+			return nil, err
+		}
+		return fair, nil
+	case PolicyBinPacking:
+		return NewBinPacking(store), nil
+	}
+	return nil, fmt.Errorf("scheduler.policy: unknown policy %q: want %s, %s, %s or %s",
+		cfg.Scheduler.Policy, PolicyFIFO, PolicyPriority, PolicyFairShare, PolicyBinPacking)
+}
+
+// ReadySet is the set of queued jobs a worker may be handed: those Filter
+// allows whose concurrency keys are free. Policies narrow Filter to choose
+// among them. Claims are atomic in the store, so a job returned by Peek may
+// be gone, to another worker, by the time it is claimed.
+type ReadySet struct {
+	// Filter allows the task types the worker runs, minus throttled ones.
+	Filter storage.ClaimFilter
+
+	workerID string
+	jobs     *JobManager
+	lease    time.Duration
+}
+
+// Claim leases the first job filter allows to the worker, or returns nil
+// if there is none.
+func (r *ReadySet) Claim(filter storage.ClaimFilter) (*Job, error) {
+	return r.jobs.Claim(r.workerID, filter, r.lease)
+}
+
+// Peek returns up to limit of the jobs filter allows, in claim order,
+// without claiming them.
+func (r *ReadySet) Peek(filter storage.ClaimFilter, limit int) ([]*Job, error) {
+	return r.jobs.store.PeekTasks(filter, limit)
+}
+
+// FIFO hands out the oldest ready job, whatever its priority.
+type FIFO struct{}
+
+func (FIFO) Name() string { return PolicyFIFO }
+
+func (FIFO) Next(w *WorkerInfo, ready *ReadySet) (*Job, error) {
+	filter := ready.Filter
+	filter.Order = storage.ByAge
+	return ready.Claim(filter)
+}
+
+// Priority hands out the ready job with the highest priority, the oldest
+// first among equals. It is the default policy.
+type Priority struct{}
+
+func (Priority) Name() string { return PolicyPriority }
+
+func (Priority) Next(w *WorkerInfo, ready *ReadySet) (*Job, error) {
+	return ready.Claim(ready.Filter)
+}
@@ -2,10 +2,27 @@ package scheduler
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"strings"
+	"time"
 
 	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/blobstore"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/config"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/logsink"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/models"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/storage"
 	pb "github.com/dheeraj-sn/distributed-orchestrator/proto"
 )
 
@@ -15,96 +32,906 @@ type SchedulerServer struct {
 	Jobs       *JobManager
 	Workers    *WorkerManager
 	Dispatcher *Dispatcher
+	Events     *EventHub
+	Artifacts  *ArtifactStore
+	Tasks      *TaskRegistry
+	Limits     *Limiter
+	Policy     SchedulingPolicy
 	Logger     *zap.Logger
+
+	maxPullWait time.Duration
 }
 
-func NewSchedulerServer(logger *zap.Logger) *SchedulerServer {
-	jobs := NewJobManager()
-	dispatcher := NewDispatcher(jobs, logger)
-	workers := NewWorkerManager()
+func NewSchedulerServer(cfg *config.Config, store storage.JobStore, logs *logsink.Sinks, blobs blobstore.Store, logger *zap.Logger) (*SchedulerServer, error) {
+	tasks, err := NewTaskRegistry(cfg.TaskTypes, cfg.Retry, cfg.Cache)
+	if err != nil {
+		return nil, err
+	}
+	events := NewEventHub(cfg.Scheduler.EventPollInterval)
+	jobs := NewJobManager(store, events, logs, tasks)
+	workers := NewWorkerManager(store, cfg.Scheduler.LeaseDuration, logger)
+	limits, err := NewLimiter(cfg.Limits, store)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := NewPolicy(cfg, store)
+	if err != nil {
+		return nil, err
+	}
+	dispatcher := NewDispatcher(jobs, workers, limits, policy, cfg.Scheduler.LeaseDuration, logger)
 
 	return &SchedulerServer{
 		Jobs:       jobs,
 		Workers:    workers,
 		Dispatcher: dispatcher,
+		Events:     events,
+		Artifacts:  NewArtifactStore(blobs, jobs, cfg.Artifacts.MaxBytes),
+		Tasks:      tasks,
+		Limits:     limits,
+		Policy:     policy,
 		Logger:     logger,
-	}
+
+		maxPullWait: cfg.Scheduler.MaxPullWait,
+	}, nil
 }
 
 func (s *SchedulerServer) SubmitJob(ctx context.Context, req *pb.JobRequest) (*pb.JobResponse, error) {
-	jobID := s.Jobs.Submit(req.Task, req.Args)
-	s.Dispatcher.JobQueue <- jobID
+	spec := JobSpec{
+		Task:      req.Task,
+		Args:      req.Args,
+		Payload:   structJSON(req.Payload),
+		Priority:  int(req.Priority),
+		Tenant:    req.Tenant,
+		Resources: resources(req.Resources),
+		Inputs:    jobInputs(req.Inputs),
+		Outputs:   jobOutputs(req.Outputs),
+		Timeout:   req.Timeout.AsDuration(),
+		Cacheable: req.Cacheable,
+
+		ConcurrencyKey:   req.ConcurrencyKey,
+		ConcurrencyLimit: int(req.ConcurrencyLimit),
+	}
+	if spec.Timeout < 0 {
+		return nil, status.Error(codes.InvalidArgument, "timeout must not be negative")
+	}
+	if spec.Resources.CPU < 0 || spec.Resources.MemoryMB < 0 {
+		return nil, status.Error(codes.InvalidArgument, "resources must not be negative")
+	}
+	if spec.ConcurrencyLimit < 0 {
+		return nil, status.Error(codes.InvalidArgument, "concurrency_limit must not be negative")
+	}
+	if spec.ConcurrencyLimit > 0 && spec.ConcurrencyKey == "" {
+		return nil, status.Error(codes.InvalidArgument, "concurrency_limit needs a concurrency_key")
+	}
+	if req.Unique != nil {
+		var err error
+		if spec.Unique, err = uniquePolicy(req.Unique); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.Tasks.Resolve(&spec); err != nil {
+		return nil, statusError(err)
+	}
+	if err := models.ValidateFiles(spec.Inputs, spec.Outputs); err != nil {
+		return nil, statusError(err)
+	}
+	if err := s.Artifacts.CheckInputs(ctx, spec.Inputs); err != nil {
+		return nil, statusError(err)
+	}
+	job, existing, err := s.Jobs.Submit(spec)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	if existing {
+		s.Logger.Info("Duplicate job not submitted", zap.String("job_id", job.ID), zap.String("task", req.Task), zap.String("status", string(job.Status)))
+		return &pb.JobResponse{JobId: job.ID, Duplicate: true}, nil
+	}
+	if job.CachedFrom != "" {
+		s.Logger.Info("Job served from cache", zap.String("job_id", job.ID), zap.String("task", req.Task), zap.String("cached_from", job.CachedFrom))
+		return &pb.JobResponse{JobId: job.ID, CacheHit: true}, nil
+	}
+	if err := s.Dispatcher.Enqueue(job.ID, "submitted"); err != nil {
+		return nil, statusError(err)
+	}
 
-	s.Logger.Info("Job submitted", zap.String("job_id", jobID), zap.String("task", req.Task))
-	return &pb.JobResponse{JobId: jobID}, nil
+	s.Logger.Info("Job submitted", zap.String("job_id", job.ID), zap.String("task", req.Task), zap.String("tenant", job.Tenant))
+	return &pb.JobResponse{JobId: job.ID}, nil
 }
 
 func (s *SchedulerServer) GetJobStatus(ctx context.Context, req *pb.JobStatusRequest) (*pb.JobStatusResponse, error) {
-	job, ok := s.Jobs.Get(req.JobId)
-	if !ok {
+	job, err := s.Jobs.Get(req.JobId)
+	if errors.Is(err, ErrJobNotFound) {
 		return &pb.JobStatusResponse{Status: "not_found"}, nil
 	}
-	return &pb.JobStatusResponse{Status: job.Status, Result: job.Result}, nil
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.JobStatusResponse{
+		Status:  string(job.Status),
+		Result:  job.Result,
+		Output:  jsonStruct(job.Output),
+		Details: s.jobDetails(job),
+	}, nil
+}
+
+// WatchJob streams the job's status every time it changes, ending once the
+// job reaches a terminal state.
+func (s *SchedulerServer) WatchJob(req *pb.JobStatusRequest, stream pb.Orchestrator_WatchJobServer) error {
+	wake, unsubscribe := s.Events.Subscribe(jobEvents(req.JobId))
+	defer unsubscribe()
+
+	var last models.TaskStatus
+	for {
+		job, err := s.Jobs.Get(req.JobId)
+		if err != nil {
+			return statusError(err)
+		}
+		if job.Status != last {
+			last = job.Status
+			err := stream.Send(&pb.JobStatusResponse{
+				Status:  string(job.Status),
+				Result:  job.Result,
+				Output:  jsonStruct(job.Output),
+				Details: s.jobDetails(job),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if job.Status.IsTerminal() {
+			return nil
+		}
+		if !s.Events.Wait(stream.Context(), wake) {
+			return stream.Context().Err()
+		}
+	}
 }
 
 func (s *SchedulerServer) RegisterWorker(ctx context.Context, req *pb.RegisterWorkerRequest) (*pb.RegisterWorkerResponse, error) {
-	s.Workers.Register(req.WorkerId, req.Host)
-	s.Logger.Info("Worker registered", zap.String("worker_id", req.WorkerId), zap.String("host", req.Host))
+	tasks := make([]models.TaskCapability, 0, len(req.Tasks))
+	for _, c := range req.Tasks {
+		if c.Task == "" {
+			return nil, status.Error(codes.InvalidArgument, "advertised task has no name")
+		}
+		tasks = append(tasks, models.TaskCapability{Task: c.Task, Version: c.Version})
+	}
+	// Workers that predate capabilities advertise nothing and have always
+	// been handed every task.
+	anyTask := req.AnyTask || len(tasks) == 0
+	capacity := resources(req.Capacity)
+	if capacity.CPU < 0 || capacity.MemoryMB < 0 {
+		return nil, status.Error(codes.InvalidArgument, "capacity must not be negative")
+	}
+	if err := s.Workers.Register(req.WorkerId, req.Host, tasks, anyTask, capacity); err != nil {
+		return nil, statusError(err)
+	}
+	s.Logger.Info("Worker registered",
+		zap.String("worker_id", req.WorkerId),
+		zap.String("host", req.Host),
+		zap.Any("tasks", tasks),
+		zap.Bool("any_task", anyTask),
+		zap.Any("capacity", capacity))
 	return &pb.RegisterWorkerResponse{Success: true}, nil
 }
 
 func (s *SchedulerServer) SendHeartbeat(ctx context.Context, req *pb.HeartbeatRequest) (*pb.HeartbeatResponse, error) {
 	alive := s.Workers.Heartbeat(req.WorkerId)
+	if alive {
+		s.Dispatcher.Heartbeat(req.WorkerId)
+	}
 	return &pb.HeartbeatResponse{Alive: alive}, nil
 }
 
 func (s *SchedulerServer) PullJob(ctx context.Context, req *pb.PullJobRequest) (*pb.PullJobResponse, error) {
-	job := s.Dispatcher.NextJob()
+	// Without a registration there is no knowing which jobs the worker can
+	// run; it registers again when told so.
+	if _, ok := s.Workers.Get(req.WorkerId); !ok {
+		return nil, status.Errorf(codes.FailedPrecondition, "worker %q is not registered", req.WorkerId)
+	}
+	var job *Job
+	if wait := min(req.Wait.AsDuration(), s.maxPullWait); wait > 0 {
+		waitCtx, cancel := context.WithTimeout(ctx, wait)
+		job = s.Dispatcher.WaitForJob(waitCtx, req.WorkerId)
+		cancel()
+	} else {
+		job = s.Dispatcher.NextJob(req.WorkerId)
+	}
 	if job == nil {
 		return &pb.PullJobResponse{Found: false}, nil
 	}
 	s.Logger.Info("Job pulled", zap.String("job_id", job.ID), zap.String("worker", req.WorkerId))
 	return &pb.PullJobResponse{
-		Found: true,
-		JobId: job.ID,
-		Task:  job.Task,
-		Args:  job.Args,
+		Found:   true,
+		JobId:   job.ID,
+		Task:    job.Type,
+		Args:    job.Args,
+		Payload: jsonStruct(job.Payload),
+		Timeout: optionalDuration(job.Timeout),
+		Attempt: int32(job.Attempts),
+		Inputs:  pbInputs(job.Inputs),
+		Outputs: pbOutputs(job.Outputs),
 	}, nil
 }
 
+func (s *SchedulerServer) StartJob(ctx context.Context, req *pb.StartJobRequest) (*pb.StartJobResponse, error) {
+	if err := s.Jobs.Start(req.JobId, req.WorkerId); err != nil {
+		s.Logger.Warn("Job start rejected", zap.String("job_id", req.JobId), zap.String("worker", req.WorkerId), zap.Error(err))
+		return &pb.StartJobResponse{Success: false}, nil
+	}
+	return &pb.StartJobResponse{Success: true}, nil
+}
+
 func (s *SchedulerServer) CompleteJob(ctx context.Context, req *pb.CompleteJobRequest) (*pb.CompleteJobResponse, error) {
-	ok := s.Jobs.Complete(req.JobId, req.Result)
-	if ok {
-		s.Logger.Info("Job completed", zap.String("job_id", req.JobId))
+	var err error
+	if req.Error != "" {
+		err = s.Dispatcher.Fail(req.JobId, req.WorkerId, req.Error, int(req.ExitCode))
+	} else {
+		err = s.Jobs.Complete(req.JobId, req.WorkerId, req.Result, structJSON(req.Output))
+	}
+	if err != nil {
+		s.Logger.Warn("Job completion rejected", zap.String("job_id", req.JobId), zap.String("worker", req.WorkerId), zap.Error(err))
+		return &pb.CompleteJobResponse{Success: false}, nil
+	}
+	s.Logger.Info("Job finished", zap.String("job_id", req.JobId), zap.Bool("failed", req.Error != ""))
+	return &pb.CompleteJobResponse{Success: true}, nil
+}
+
+func (s *SchedulerServer) CancelJob(ctx context.Context, req *pb.CancelJobRequest) (*pb.CancelJobResponse, error) {
+	reason := req.Reason
+	if reason == "" {
+		reason = "cancelled by client"
+	}
+	if err := s.Jobs.Cancel(req.JobId, models.ActorClient, reason); err != nil {
+		return nil, statusError(err)
 	}
-	return &pb.CompleteJobResponse{Success: ok}, nil
+	s.Logger.Info("Job cancelled", zap.String("job_id", req.JobId))
+	return &pb.CancelJobResponse{Success: true}, nil
+}
+
+func (s *SchedulerServer) GetJobHistory(ctx context.Context, req *pb.JobHistoryRequest) (*pb.JobHistoryResponse, error) {
+	history, err := s.Jobs.History(req.JobId)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	resp := &pb.JobHistoryResponse{}
+	for _, t := range history {
+		resp.Transitions = append(resp.Transitions, &pb.JobTransition{
+			From:      string(t.From),
+			To:        string(t.To),
+			Timestamp: timestamppb.New(t.At),
+			Actor:     t.Actor,
+			Reason:    t.Reason,
+		})
+	}
+	return resp, nil
 }
 
 func (s *SchedulerServer) ListJobs(ctx context.Context, req *pb.ListJobsRequest) (*pb.ListJobsResponse, error) {
-	s.Jobs.mu.RLock()
-	defer s.Jobs.mu.RUnlock()
+	filter := storage.TaskFilter{
+		Type:     req.Task,
+		Queue:    req.Queue,
+		Tenant:   req.Tenant,
+		WorkerID: req.WorkerId,
+		Offset:   int(req.Offset),
+		Limit:    int(req.Limit),
+	}
+	for _, st := range req.Statuses {
+		if !models.TaskStatus(st).Valid() {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown status %q", st)
+		}
+		filter.Statuses = append(filter.Statuses, models.TaskStatus(st))
+	}
+	if filter.Offset < 0 || filter.Limit < 0 {
+		return nil, status.Error(codes.InvalidArgument, "offset and limit must not be negative")
+	}
+	// Fetch one extra job to learn whether there is another page.
+	if filter.Limit > 0 {
+		filter.Limit++
+	}
+	jobs, err := s.Jobs.List(filter)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	resp := &pb.ListJobsResponse{}
+	if req.Limit > 0 && len(jobs) > int(req.Limit) {
+		jobs = jobs[:req.Limit]
+		resp.NextOffset = req.Offset + req.Limit
+	}
 
 	var jobStatuses []*pb.JobStatus
-	for _, j := range s.Jobs.jobs {
+	for _, j := range jobs {
 		jobStatuses = append(jobStatuses, &pb.JobStatus{
-			JobId:  j.ID,
-			Status: j.Status,
-			Result: j.Result,
+			JobId:   j.ID,
+			Status:  string(j.Status),
+			Result:  j.Result,
+			Output:  jsonStruct(j.Output),
+			Details: jobDetails(j),
 		})
 	}
 
-	return &pb.ListJobsResponse{Jobs: jobStatuses}, nil
+	resp.Jobs = jobStatuses
+	return resp, nil
 }
 
+// StreamLogs persists log lines sent by a worker one at a time.
 func (s *SchedulerServer) StreamLogs(stream pb.Orchestrator_StreamLogsServer) error {
+	known := make(map[string]bool) // job IDs already checked on this stream
 	for {
 		entry, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
 		if err != nil {
 			return err
 		}
-		fmt.Printf("[Job %s | Worker %s | %s] %s\n", entry.JobId, entry.WorkerId, entry.Timestamp, entry.Message)
+		if !known[entry.JobId] {
+			if _, err := s.Jobs.Get(entry.JobId); err != nil {
+				return statusError(err)
+			}
+			known[entry.JobId] = true
+		}
+		if err := s.Jobs.ShipLogs([]ShippedLine{shippedLine(entry)}); err != nil {
+			return statusError(err)
+		}
 		if err := stream.Send(&pb.LogAck{Received: true}); err != nil {
 			return err
 		}
 	}
 }
+
+// ShipLogs persists batches of log lines sent by a worker's log shipper,
+// acking each batch once it is stored. Resent lines are de-duplicated by
+// their seq.
+func (s *SchedulerServer) ShipLogs(stream pb.Orchestrator_ShipLogsServer) error {
+	for {
+		batch, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		lines := make([]ShippedLine, 0, len(batch.Entries))
+		for _, entry := range batch.Entries {
+			lines = append(lines, shippedLine(entry))
+		}
+		if err := s.Jobs.ShipLogs(lines); err != nil {
+			return statusError(err)
+		}
+		if err := stream.Send(&pb.LogAck{Received: true, BatchId: batch.BatchId}); err != nil {
+			return err
+		}
+	}
+}
+
+func shippedLine(entry *pb.LogEntry) ShippedLine {
+	at, err := time.Parse(time.RFC3339Nano, entry.Timestamp)
+	if err != nil {
+		at = time.Now()
+	}
+	return ShippedLine{
+		JobID:   entry.JobId,
+		Attempt: int(entry.Attempt),
+		Seq:     entry.Seq,
+		At:      at,
+		Message: entry.Message,
+	}
+}
+
+func (s *SchedulerServer) GetJobLogs(ctx context.Context, req *pb.JobLogsRequest) (*pb.JobLogsResponse, error) {
+	lines, err := s.Jobs.Logs(req.JobId, req.Offset, int(req.Limit))
+	if err != nil {
+		return nil, statusError(err)
+	}
+	resp := &pb.JobLogsResponse{NextOffset: req.Offset}
+	for _, l := range lines {
+		resp.Lines = append(resp.Lines, logLine(l))
+		resp.NextOffset = l.Seq
+	}
+	return resp, nil
+}
+
+// TailJobLogs sends the job's log from req.Offset, then follows it until the
+// job reaches a terminal state and every line has been sent.
+func (s *SchedulerServer) TailJobLogs(req *pb.JobLogsRequest, stream pb.Orchestrator_TailJobLogsServer) error {
+	wake, unsubscribe := s.Events.Subscribe(jobEvents(req.JobId))
+	defer unsubscribe()
+
+	after := req.Offset
+	for {
+		// Read the status first: once it is terminal, any lines written
+		// before it are visible to the read below.
+		job, err := s.Jobs.Get(req.JobId)
+		if err != nil {
+			return statusError(err)
+		}
+		lines, err := s.Jobs.Logs(req.JobId, after, maxLogPage)
+		if err != nil {
+			return statusError(err)
+		}
+		for _, l := range lines {
+			if err := stream.Send(logLine(l)); err != nil {
+				return err
+			}
+			after = l.Seq
+		}
+		if len(lines) == maxLogPage {
+			continue
+		}
+		if job.Status.IsTerminal() {
+			return nil
+		}
+		if !s.Events.Wait(stream.Context(), wake) {
+			return stream.Context().Err()
+		}
+	}
+}
+
+// artifactChunkSize is the most artifact data sent in one message.
+const artifactChunkSize = 256 << 10
+
+// UploadArtifact stores an artifact streamed by the worker holding the job.
+func (s *SchedulerServer) UploadArtifact(stream pb.Orchestrator_UploadArtifactServer) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	h := first.Header
+	if h == nil {
+		return status.Error(codes.InvalidArgument, "the first message must carry the artifact header")
+	}
+	r := &artifactReader{stream: stream, buf: first.Data}
+	art, err := s.Artifacts.Upload(stream.Context(), h.JobId, h.WorkerId, h.Name, h.ContentType, r)
+	if err != nil {
+		if r.err != nil {
+			return r.err
+		}
+		return statusError(err)
+	}
+	s.Logger.Info("Artifact stored", zap.String("job_id", art.JobID), zap.String("name", art.Name), zap.Int64("size", art.Size))
+	return stream.SendAndClose(&pb.UploadArtifactResponse{Artifact: artifactInfo(art)})
+}
+
+// UploadInput stores a file streamed by a client for jobs to take as an
+// input. Only the header's name and content type are used.
+func (s *SchedulerServer) UploadInput(stream pb.Orchestrator_UploadInputServer) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	h := first.Header
+	if h == nil {
+		return status.Error(codes.InvalidArgument, "the first message must carry the artifact header")
+	}
+	r := &artifactReader{stream: stream, buf: first.Data}
+	art, err := s.Artifacts.UploadInput(stream.Context(), h.Name, h.ContentType, r)
+	if err != nil {
+		if r.err != nil {
+			return r.err
+		}
+		return statusError(err)
+	}
+	s.Logger.Info("Input uploaded", zap.String("upload_id", art.UploadID), zap.String("name", art.Name), zap.Int64("size", art.Size))
+	return stream.SendAndClose(&pb.UploadInputResponse{UploadId: art.UploadID, Artifact: artifactInfo(art)})
+}
+
+// artifactReader reads the data of an upload stream after its header.
+type artifactReader struct {
+	stream interface {
+		Recv() (*pb.ArtifactChunk, error)
+	}
+	buf []byte
+	err error // stream error, reported instead of the upload's
+}
+
+func (r *artifactReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		chunk, err := r.stream.Recv()
+		if err == io.EOF {
+			return 0, io.EOF
+		}
+		if err != nil {
+			r.err = err
+			return 0, err
+		}
+		if chunk.Header != nil {
+			r.err = status.Error(codes.InvalidArgument, "only the first message may carry the artifact header")
+			return 0, r.err
+		}
+		r.buf = chunk.Data
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+func (s *SchedulerServer) ListArtifacts(ctx context.Context, req *pb.ListArtifactsRequest) (*pb.ListArtifactsResponse, error) {
+	arts, err := s.Artifacts.List(ctx, req.JobId)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	resp := &pb.ListArtifactsResponse{}
+	for _, art := range arts {
+		resp.Artifacts = append(resp.Artifacts, artifactInfo(art))
+	}
+	return resp, nil
+}
+
+// DownloadArtifact streams an artifact, or an uploaded input if the request
+// names an upload ID, its info in the first message.
+func (s *SchedulerServer) DownloadArtifact(req *pb.DownloadArtifactRequest, stream pb.Orchestrator_DownloadArtifactServer) error {
+	var (
+		art  *models.Artifact
+		body io.ReadCloser
+		err  error
+	)
+	if req.UploadId != "" {
+		art, body, err = s.Artifacts.OpenUpload(stream.Context(), req.UploadId)
+	} else {
+		art, body, err = s.Artifacts.Open(stream.Context(), req.JobId, req.Name)
+	}
+	if err != nil {
+		return statusError(err)
+	}
+	defer body.Close()
+
+	msg := &pb.ArtifactData{Info: artifactInfo(art)}
+	buf := make([]byte, artifactChunkSize)
+	for {
+		n, err := io.ReadFull(body, buf)
+		if n > 0 || msg.Info != nil {
+			msg.Data = buf[:n]
+			if err := stream.Send(msg); err != nil {
+				return err
+			}
+			msg = &pb.ArtifactData{}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil
+		}
+		if err != nil {
+			return statusError(err)
+		}
+	}
+}
+
+func artifactInfo(a *models.Artifact) *pb.ArtifactInfo {
+	return &pb.ArtifactInfo{
+		JobId:       a.JobID,
+		UploadId:    a.UploadID,
+		Name:        a.Name,
+		Size:        a.Size,
+		Sha256:      a.SHA256,
+		ContentType: a.ContentType,
+		CreatedAt:   timestamppb.New(a.CreatedAt),
+	}
+}
+
+func (s *SchedulerServer) ListTaskTypes(ctx context.Context, req *pb.ListTaskTypesRequest) (*pb.ListTaskTypesResponse, error) {
+	resp := &pb.ListTaskTypesResponse{Strict: s.Tasks.strict}
+	for _, tt := range s.Tasks.List() {
+		resp.TaskTypes = append(resp.TaskTypes, &pb.TaskType{
+			Name:           tt.Name,
+			Description:    tt.Description,
+			Queue:          tt.Queue,
+			Timeout:        optionalDuration(tt.Timeout),
+			MaxAttempts:    int32(tt.Retry.MaxAttempts),
+			InitialBackoff: durationpb.New(tt.Retry.InitialBackoff),
+			MaxBackoff:     durationpb.New(tt.Retry.MaxBackoff),
+			ArgsSchema:     string(tt.ArgsSchema),
+			PayloadSchema:  string(tt.PayloadSchema),
+			Version:        tt.Version,
+			Cacheable:      tt.Cacheable,
+			CacheTtl:       optionalDuration(tt.CacheTTL),
+		})
+	}
+	return resp, nil
+}
+
+func (s *SchedulerServer) ListWorkers(ctx context.Context, req *pb.ListWorkersRequest) (*pb.ListWorkersResponse, error) {
+	workers, err := s.Workers.List()
+	if err != nil {
+		return nil, statusError(err)
+	}
+	resp := &pb.ListWorkersResponse{}
+	for _, w := range workers {
+		details := &pb.WorkerDetails{
+			WorkerId:  w.ID,
+			Host:      w.Host,
+			LastSeen:  timestamppb.New(w.LastSeen),
+			Connected: s.Workers.Connected(w),
+			AnyTask:   w.AnyTask,
+			Capacity:  pbResources(w.Capacity),
+		}
+		for _, c := range w.Tasks {
+			details.Tasks = append(details.Tasks, &pb.TaskCapability{Task: c.Task, Version: c.Version})
+		}
+		resp.Workers = append(resp.Workers, details)
+	}
+	return resp, nil
+}
+
+func (s *SchedulerServer) ListCacheEntries(ctx context.Context, req *pb.ListCacheEntriesRequest) (*pb.ListCacheEntriesResponse, error) {
+	entries, err := s.Jobs.CacheEntries(CacheFilter{
+		Task:           req.Task,
+		Key:            req.Key,
+		IncludeExpired: req.IncludeExpired,
+	})
+	if err != nil {
+		return nil, statusError(err)
+	}
+	now := time.Now()
+	resp := &pb.ListCacheEntriesResponse{}
+	for _, e := range entries {
+		resp.Entries = append(resp.Entries, &pb.CacheEntry{
+			Key:       e.Key,
+			Task:      e.Task,
+			JobId:     e.JobID,
+			CreatedAt: timestamppb.New(e.CreatedAt),
+			ExpiresAt: timestamp(e.ExpiresAt),
+			Expired:   e.Expired(now),
+			Hits:      int32(e.Hits),
+		})
+	}
+	return resp, nil
+}
+
+func (s *SchedulerServer) InvalidateCache(ctx context.Context, req *pb.InvalidateCacheRequest) (*pb.InvalidateCacheResponse, error) {
+	if req.Task == "" && req.Key == "" && !req.All {
+		return nil, status.Error(codes.InvalidArgument, "name a task or key, or set all")
+	}
+	n, err := s.Jobs.InvalidateCache(req.Task, req.Key)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	s.Logger.Info("Cache invalidated", zap.String("task", req.Task), zap.String("key", req.Key), zap.Int("entries", n))
+	return &pb.InvalidateCacheResponse{Invalidated: int32(n)}, nil
+}
+
+// jobDetails describes a job like the jobDetails function, adding for a
+// queued job that no connected worker can run it.
+func (s *SchedulerServer) jobDetails(j *Job) *pb.JobDetails {
+	details := jobDetails(j)
+	if j.Status != models.TaskStatusQueued {
+		return details
+	}
+	version := s.Tasks.Version(j.Type)
+	capable, err := s.Workers.Capable(j.Type, version)
+	if err != nil {
+		s.Logger.Warn("Failed to check worker capabilities", zap.String("job_id", j.ID), zap.Error(err))
+		return details
+	}
+	if !capable {
+		if version != "" {
+			details.WaitingReason = fmt.Sprintf("no capable worker: no connected worker runs %q version %s", j.Type, version)
+		} else {
+			details.WaitingReason = fmt.Sprintf("no capable worker: no connected worker runs %q", j.Type)
+		}
+		return details
+	}
+	if _, packing := s.Policy.(*BinPacking); packing && !j.Resources.IsZero() {
+		fit, err := s.Workers.Fits(j.Type, version, j.Resources)
+		if err != nil {
+			s.Logger.Warn("Failed to check worker capacity", zap.String("job_id", j.ID), zap.Error(err))
+			return details
+		}
+		if !fit {
+			details.WaitingReason = fmt.Sprintf("no worker large enough: no connected worker running %q has capacity for %s", j.Type, j.Resources)
+			return details
+		}
+	}
+	throttled, err := s.Limits.Throttled(j)
+	if err != nil {
+		s.Logger.Warn("Failed to check limits", zap.String("job_id", j.ID), zap.Error(err))
+		return details
+	}
+	if throttled != "" {
+		details.WaitingReason = "throttled: " + throttled
+		return details
+	}
+	if j.ConcurrencyKey == "" {
+		return details
+	}
+	holders, err := s.Jobs.KeyHolders(j.ConcurrencyKey)
+	if err != nil {
+		s.Logger.Warn("Failed to check concurrency key", zap.String("job_id", j.ID), zap.Error(err))
+		return details
+	}
+	if len(holders) >= j.KeyLimit() {
+		details.WaitingReason = fmt.Sprintf("concurrency key %q is held by %s", j.ConcurrencyKey, strings.Join(holders, ", "))
+	}
+	return details
+}
+
+func jobDetails(j *Job) *pb.JobDetails {
+	return &pb.JobDetails{
+		Task:             j.Type,
+		Args:             j.Args,
+		Payload:          jsonStruct(j.Payload),
+		Priority:         int32(j.Priority),
+		Queue:            j.Queue,
+		Timeout:          optionalDuration(j.Timeout),
+		CreatedAt:        timestamp(&j.CreatedAt),
+		QueuedAt:         timestamp(j.QueuedAt),
+		StartedAt:        timestamp(j.StartedAt),
+		FinishedAt:       timestamp(j.CompletedAt),
+		WorkerId:         j.WorkerID,
+		Attempt:          int32(j.Attempts),
+		ExitCode:         int32(j.ExitCode),
+		Error:            j.Error,
+		QueueDuration:    durationpb.New(j.QueueDuration()),
+		RunDuration:      durationpb.New(j.RunDuration()),
+		LeaseExpiresAt:   timestamp(j.LeaseExpiresAt),
+		Inputs:           pbInputs(j.Inputs),
+		Outputs:          pbOutputs(j.Outputs),
+		CacheKey:         j.CacheKey,
+		CacheHit:         j.CachedFrom != "",
+		CachedFrom:       j.CachedFrom,
+		ConcurrencyKey:   j.ConcurrencyKey,
+		ConcurrencyLimit: int32(j.ConcurrencyLimit),
+		UniqueKey:        j.UniqueKey,
+		Tenant:           j.Tenant,
+		Resources:        pbResources(j.Resources),
+	}
+}
+
+// uniquePolicy converts a submission's unique policy, failing on unknown
+// scopes and on a window that does not go with the scope.
+func uniquePolicy(p *pb.UniquePolicy) (*UniquePolicy, error) {
+	policy := &UniquePolicy{Key: p.Key, Window: p.Window.AsDuration()}
+	switch p.Scope {
+	case pb.UniqueScope_UNIQUE_SCOPE_QUEUED:
+		policy.Scope = UniqueQueued
+	case pb.UniqueScope_UNIQUE_SCOPE_ACTIVE:
+		policy.Scope = UniqueActive
+	case pb.UniqueScope_UNIQUE_SCOPE_WINDOW:
+		policy.Scope = UniqueWindow
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown unique scope %v", p.Scope)
+	}
+	if (policy.Scope == UniqueWindow) != (policy.Window > 0) {
+		return nil, status.Error(codes.InvalidArgument, "unique window must be positive with the window scope and unset otherwise")
+	}
+	return policy, nil
+}
+
+// structJSON encodes a structured payload or output as a JSON object, or
+// nil if it is unset.
+func structJSON(s *structpb.Struct) json.RawMessage {
+	if s == nil {
+		return nil
+	}
+	// A Struct always encodes; its fields are JSON values by construction.
+	raw, _ := json.Marshal(s.AsMap())
+	return raw
+}
+
+// jsonStruct decodes a JSON object written by structJSON.
+func jsonStruct(raw json.RawMessage) *structpb.Struct {
+	if len(raw) == 0 {
+		return nil
+	}
+	s := &structpb.Struct{}
+	if err := protojson.Unmarshal(raw, s); err != nil {
+		return nil
+	}
+	return s
+}
+
+func jobInputs(inputs []*pb.JobInput) []models.JobInput {
+	var out []models.JobInput
+	for _, in := range inputs {
+		ji := models.JobInput{Path: in.Path, UploadID: in.GetUploadId()}
+		if ref := in.GetArtifact(); ref != nil {
+			ji.JobID, ji.Artifact = ref.JobId, ref.Name
+		}
+		out = append(out, ji)
+	}
+	return out
+}
+
+func pbInputs(inputs []models.JobInput) []*pb.JobInput {
+	var out []*pb.JobInput
+	for _, in := range inputs {
+		pi := &pb.JobInput{Path: in.Path}
+		if in.UploadID != "" {
+			pi.Source = &pb.JobInput_UploadId{UploadId: in.UploadID}
+		} else {
+			pi.Source = &pb.JobInput_Artifact{Artifact: &pb.ArtifactRef{JobId: in.JobID, Name: in.Artifact}}
+		}
+		out = append(out, pi)
+	}
+	return out
+}
+
+func jobOutputs(outputs []*pb.JobOutput) []models.JobOutput {
+	var out []models.JobOutput
+	for _, o := range outputs {
+		out = append(out, models.JobOutput{Path: o.Path, Artifact: o.Artifact})
+	}
+	return out
+}
+
+func pbOutputs(outputs []models.JobOutput) []*pb.JobOutput {
+	var out []*pb.JobOutput
+	for _, o := range outputs {
+		out = append(out, &pb.JobOutput{Path: o.Path, Artifact: o.Artifact})
+	}
+	return out
+}
+
+func logLine(l models.LogLine) *pb.LogLine {
+	return &pb.LogLine{Seq: l.Seq, Timestamp: timestamppb.New(l.At), Message: l.Message}
+}
+
+// resources converts r; nil is no resources.
+func resources(r *pb.Resources) models.Resources {
+	return models.Resources{CPU: r.GetCpu(), MemoryMB: r.GetMemoryMb()}
+}
+
+// pbResources converts r, leaving zero resources unset.
+func pbResources(r models.Resources) *pb.Resources {
+	if r.IsZero() {
+		return nil
+	}
+	return &pb.Resources{Cpu: r.CPU, MemoryMb: r.MemoryMB}
+}
+
+// optionalDuration converts d, leaving zero durations unset.
+func optionalDuration(d time.Duration) *durationpb.Duration {
+	if d == 0 {
+		return nil
+	}
+	return durationpb.New(d)
+}
+
+// timestamp converts t, leaving nil and zero times unset.
+func timestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil || t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+// statusError maps JobManager errors to gRPC status errors.
+func statusError(err error) error {
+	var invalid *models.InvalidTransitionError
+	var submission *InvalidSubmissionError
+	switch {
+	case errors.As(err, &submission):
+		return invalidSubmissionStatus(submission)
+	case errors.Is(err, ErrUnknownTask):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, ErrJobNotFound), errors.Is(err, ErrArtifactNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, models.ErrInvalidFiles):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, ErrInvalidArtifactName):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, ErrArtifactTooLarge):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, ErrLeaseLost), errors.Is(err, ErrJobNotRunning), errors.Is(err, logsink.ErrNotReadable):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.As(err, &invalid):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// invalidSubmissionStatus reports schema violations as an InvalidArgument
+// status carrying a BadRequest detail with one entry per violation.
+func invalidSubmissionStatus(e *InvalidSubmissionError) error {
+	br := &errdetails.BadRequest{}
+	for _, v := range e.Violations {
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.Field,
+			Description: v.Description,
+		})
+	}
+	st, err := status.New(codes.InvalidArgument, e.Error()).WithDetails(br)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, e.Error())
+	}
+	return st.Err()
+}
@@ -1,17 +1,496 @@
 package scheduler
 
-// State manages the scheduler's state
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/config"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/models"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/storage"
+)
+
+const (
+	snapshotFile = "snapshot.json"
+	walFile      = "wal.log"
+)
+
+// Fsync policies for the write-ahead log.
+const (
+	FsyncAlways   = "always"
+	FsyncInterval = "interval"
+	FsyncNever    = "never"
+)
+
+// walRecord is one line of the write-ahead log. Records are applied in Seq
+// order; a snapshot covers every record up to its own Seq.
+type walRecord struct {
+	Seq  uint64       `json:"seq"`
+	Op   string       `json:"op"` // "task", "renew", "worker", "seen", "logs" or "trim"
+	Task *models.Task `json:"task,omitempty"`
+	// Worker is set for "worker" records.
+	Worker *models.Worker `json:"worker,omitempty"`
+	// WorkerID and Until are set for "renew" records, WorkerID and At for
+	// "seen" records.
+	WorkerID string     `json:"worker_id,omitempty"`
+	Until    *time.Time `json:"until,omitempty"`
+	At       *time.Time `json:"at,omitempty"`
+	// JobID and Lines are set for "logs" records, JobID and Through for
+	// "trim" records.
+	JobID   string           `json:"job_id,omitempty"`
+	Lines   []models.LogLine `json:"lines,omitempty"`
+	Through int64            `json:"through,omitempty"`
+}
+
+type snapshot struct {
+	Seq     uint64                      `json:"seq"`
+	Tasks   []*models.Task              `json:"tasks"`
+	Workers []*models.Worker            `json:"workers"`
+	Logs    map[string][]models.LogLine `json:"logs,omitempty"`
+	// Shipped holds the highest ShipSeq stored per job attempt.
+	Shipped map[string]map[int]int64 `json:"shipped,omitempty"`
+}
+
+// State makes the memory backend survive restarts. It wraps a MemoryStore
+// as a storage.JobStore and appends every write the JobManager makes to a
+// write-ahead log, which is periodically compacted into a snapshot. On
+// open, the snapshot and the rest of the log are replayed to rebuild the
+// jobs and their queues.
 type State struct {
-	// TODO: Add state fields
+	mu     sync.Mutex // serializes writes so the log matches apply order
+	store  *storage.MemoryStore
+	cfg    config.WALConfig
+	logger *zap.Logger
+
+	wal   *os.File
+	seq   uint64
+	dirty bool // records written since the last fsync
 }
 
-// NewState creates a new scheduler state
-func NewState() *State {
-	return &State{}
+// OpenState restores store from cfg.Dir and starts a fresh log.
+func OpenState(store *storage.MemoryStore, cfg config.WALConfig, logger *zap.Logger) (*State, error) {
+	switch cfg.Fsync {
+	case FsyncAlways, FsyncInterval, FsyncNever:
+	default:
+		return nil, fmt.Errorf("unknown fsync policy %q", cfg.Fsync)
+	}
+	if err := os.MkdirAll(cfg.Dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	s := &State{store: store, cfg: cfg, logger: logger}
+	if err := s.loadSnapshot(); err != nil {
+		return nil, fmt.Errorf("load snapshot: %w", err)
+	}
+	replayed, err := s.replay()
+	if err != nil {
+		return nil, fmt.Errorf("replay write-ahead log: %w", err)
+	}
+
+	// Compact right away so the log only holds records from this run.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.snapshot(); err != nil {
+		return nil, err
+	}
+	tasks, _ := store.ListTasks(storage.TaskFilter{})
+	logger.Info("Recovered scheduler state",
+		zap.String("dir", cfg.Dir),
+		zap.Int("jobs", len(tasks)),
+		zap.Int("replayed", replayed))
+	return s, nil
 }
 
-// UpdateState updates the scheduler's state
-func (s *State) UpdateState() error {
-	// TODO: Implement state update logic
+func (s *State) loadSnapshot() error {
+	data, err := os.ReadFile(filepath.Join(s.cfg.Dir, snapshotFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var snap snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return err
+	}
+	for _, t := range snap.Tasks {
+		s.store.Restore(t)
+	}
+	for _, w := range snap.Workers {
+		if err := s.store.SaveWorker(w); err != nil {
+			return err
+		}
+	}
+	for jobID, lines := range snap.Logs {
+		s.store.RestoreLogs(jobID, lines)
+	}
+	for jobID, seqs := range snap.Shipped {
+		s.store.RestoreShippedSeqs(jobID, seqs)
+	}
+	s.seq = snap.Seq
 	return nil
-} 
\ No newline at end of file
+}
+
+// replay applies log records newer than the snapshot. A record cut short
+// by a crash ends the replay; everything before it is kept.
+func (s *State) replay() (int, error) {
+	f, err := os.Open(filepath.Join(s.cfg.Dir, walFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	replayed := 0
+	dec := json.NewDecoder(f)
+	for {
+		var rec walRecord
+		if err := dec.Decode(&rec); err != nil {
+			if err != io.EOF {
+				s.logger.Warn("Discarding torn write-ahead log tail", zap.Uint64("after_seq", s.seq), zap.Error(err))
+			}
+			return replayed, nil
+		}
+		if rec.Seq <= s.seq {
+			continue
+		}
+		if err := s.apply(&rec); err != nil {
+			return replayed, fmt.Errorf("record %d: %w", rec.Seq, err)
+		}
+		s.seq = rec.Seq
+		replayed++
+	}
+}
+
+func (s *State) apply(rec *walRecord) error {
+	switch rec.Op {
+	case "task":
+		s.store.Restore(rec.Task)
+		return nil
+	case "renew":
+		return s.store.RenewLeases(rec.WorkerID, *rec.Until)
+	case "worker":
+		return s.store.SaveWorker(rec.Worker)
+	case "seen":
+		return s.store.TouchWorker(rec.WorkerID, *rec.At)
+	case "logs":
+		_, err := s.store.AppendLogs(rec.JobID, rec.Lines)
+		return err
+	case "trim":
+		return s.store.DeleteLogs(rec.JobID, rec.Through)
+	default:
+		return fmt.Errorf("unknown op %q", rec.Op)
+	}
+}
+
+// snapshot writes the whole store to the snapshot file and truncates the
+// log. Writes are blocked meanwhile. The caller must hold s.mu.
+func (s *State) snapshot() error {
+	tasks, err := s.store.ListTasks(storage.TaskFilter{})
+	if err != nil {
+		return err
+	}
+	workers, err := s.store.ListWorkers()
+	if err != nil {
+		return err
+	}
+	logs := make(map[string][]models.LogLine)
+	shipped := make(map[string]map[int]int64)
+	for _, t := range tasks {
+		lines, err := s.store.JobLogs(t.ID, 0, 0)
+		if err != nil {
+			return err
+		}
+		if len(lines) > 0 {
+			logs[t.ID] = lines
+		}
+		if seqs := s.store.ShippedSeqs(t.ID); len(seqs) > 0 {
+			shipped[t.ID] = seqs
+		}
+	}
+	data, err := json.Marshal(snapshot{Seq: s.seq, Tasks: tasks, Workers: workers, Logs: logs, Shipped: shipped})
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(s.cfg.Dir, snapshotFile)
+	if err := writeFileSync(path+".tmp", data); err != nil {
+		return fmt.Errorf("write snapshot: %w", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("write snapshot: %w", err)
+	}
+	if err := syncDir(s.cfg.Dir); err != nil {
+		return fmt.Errorf("write snapshot: %w", err)
+	}
+
+	// Records up to s.seq are now in the snapshot, so a crash before the
+	// truncation below only leaves records that replay will skip.
+	if s.wal != nil {
+		s.wal.Close()
+	}
+	s.wal, err = os.Create(filepath.Join(s.cfg.Dir, walFile))
+	if err != nil {
+		return fmt.Errorf("truncate write-ahead log: %w", err)
+	}
+	s.dirty = false
+	return nil
+}
+
+func writeFileSync(path string, data []byte) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// append writes rec to the log. The caller must hold s.mu and have applied
+// the change to the store already; an error here means the change may be
+// lost on restart.
+func (s *State) append(rec walRecord) error {
+	s.seq++
+	rec.Seq = s.seq
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if _, err := s.wal.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("write-ahead log: %w", err)
+	}
+	if s.cfg.Fsync == FsyncAlways {
+		if err := s.wal.Sync(); err != nil {
+			return fmt.Errorf("write-ahead log: %w", err)
+		}
+		return nil
+	}
+	s.dirty = true
+	return nil
+}
+
+// Run takes a snapshot every SnapshotInterval and, with the "interval"
+// policy, syncs the log every FsyncInterval until ctx is done.
+func (s *State) Run(ctx context.Context) {
+	snapshots := time.NewTicker(s.cfg.SnapshotInterval)
+	defer snapshots.Stop()
+	var syncs <-chan time.Time
+	if s.cfg.Fsync == FsyncInterval {
+		t := time.NewTicker(s.cfg.FsyncInterval)
+		defer t.Stop()
+		syncs = t.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-snapshots.C:
+			s.mu.Lock()
+			if err := s.snapshot(); err != nil {
+				s.logger.Error("Failed to snapshot scheduler state", zap.Error(err))
+			}
+			s.mu.Unlock()
+		case <-syncs:
+			s.mu.Lock()
+			if s.dirty {
+				if err := s.wal.Sync(); err != nil {
+					s.logger.Error("Failed to sync write-ahead log", zap.Error(err))
+				} else {
+					s.dirty = false
+				}
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+// SaveTask implements storage.Store.
+func (s *State) SaveTask(task *models.Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.store.SaveTask(task); err != nil {
+		return err
+	}
+	return s.append(walRecord{Op: "task", Task: task})
+}
+
+// GetTask implements storage.Store.
+func (s *State) GetTask(id string) (*models.Task, error) {
+	return s.store.GetTask(id)
+}
+
+// UpdateTask implements storage.Store.
+func (s *State) UpdateTask(task *models.Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.store.UpdateTask(task); err != nil {
+		return err
+	}
+	return s.append(walRecord{Op: "task", Task: task})
+}
+
+// ListTasks implements storage.Store.
+func (s *State) ListTasks(filter storage.TaskFilter) ([]*models.Task, error) {
+	return s.store.ListTasks(filter)
+}
+
+// ClaimTask implements storage.JobStore.
+func (s *State) ClaimTask(filter storage.ClaimFilter, apply func(task *models.Task) error) (*models.Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	task, err := s.store.ClaimTask(filter, apply)
+	if err != nil || task == nil {
+		return task, err
+	}
+	return task, s.append(walRecord{Op: "task", Task: task})
+}
+
+// PeekTasks implements storage.JobStore.
+func (s *State) PeekTasks(filter storage.ClaimFilter, limit int) ([]*models.Task, error) {
+	return s.store.PeekTasks(filter, limit)
+}
+
+// SaveUniqueTask implements storage.JobStore.
+func (s *State) SaveUniqueTask(task *models.Task, dup storage.TaskFilter) (*models.Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	existing, err := s.store.SaveUniqueTask(task, dup)
+	if err != nil || existing != nil {
+		return existing, err
+	}
+	return nil, s.append(walRecord{Op: "task", Task: task})
+}
+
+// CachedTask implements storage.JobStore.
+func (s *State) CachedTask(key string) (*models.Task, error) {
+	return s.store.CachedTask(key)
+}
+
+// HeldCounts implements storage.JobStore.
+func (s *State) HeldCounts() (byType, byQueue map[string]int, err error) {
+	return s.store.HeldCounts()
+}
+
+// LimitStarts implements storage.JobStore. Start rate state is not
+// logged, so after a restart every rate allows a full burst again.
+func (s *State) LimitStarts() (byType, byQueue map[string]time.Time, err error) {
+	return s.store.LimitStarts()
+}
+
+// HeldTenants implements storage.JobStore.
+func (s *State) HeldTenants() (map[string]int, error) {
+	return s.store.HeldTenants()
+}
+
+// QueuedTenants implements storage.JobStore.
+func (s *State) QueuedTenants() (map[string]int, error) {
+	return s.store.QueuedTenants()
+}
+
+// RenewLeases implements storage.JobStore.
+func (s *State) RenewLeases(workerID string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.store.RenewLeases(workerID, until); err != nil {
+		return err
+	}
+	return s.append(walRecord{Op: "renew", WorkerID: workerID, Until: &until})
+}
+
+// ExpiredLeases implements storage.JobStore.
+func (s *State) ExpiredLeases(now time.Time) ([]*models.Task, error) {
+	return s.store.ExpiredLeases(now)
+}
+
+// SaveWorker implements storage.JobStore.
+func (s *State) SaveWorker(worker *models.Worker) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.store.SaveWorker(worker); err != nil {
+		return err
+	}
+	return s.append(walRecord{Op: "worker", Worker: worker})
+}
+
+// GetWorker implements storage.JobStore.
+func (s *State) GetWorker(id string) (*models.Worker, error) {
+	return s.store.GetWorker(id)
+}
+
+// TouchWorker implements storage.JobStore.
+func (s *State) TouchWorker(id string, seen time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.store.TouchWorker(id, seen); err != nil {
+		return err
+	}
+	return s.append(walRecord{Op: "seen", WorkerID: id, At: &seen})
+}
+
+// ListWorkers implements storage.JobStore.
+func (s *State) ListWorkers() ([]*models.Worker, error) {
+	return s.store.ListWorkers()
+}
+
+// AppendLogs implements storage.JobStore. The record keeps the lines'
+// ShipSeqs, so that replaying it also restores the job's shipped
+// sequences.
+func (s *State) AppendLogs(jobID string, lines []models.LogLine) ([]models.LogLine, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	added, err := s.store.AppendLogs(jobID, lines)
+	if err != nil || len(added) == 0 {
+		return added, err
+	}
+	return added, s.append(walRecord{Op: "logs", JobID: jobID, Lines: added})
+}
+
+// JobLogs implements storage.JobStore.
+func (s *State) JobLogs(jobID string, after int64, limit int) ([]models.LogLine, error) {
+	return s.store.JobLogs(jobID, after, limit)
+}
+
+// DeleteLogs implements storage.JobStore.
+func (s *State) DeleteLogs(jobID string, through int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.store.DeleteLogs(jobID, through); err != nil {
+		return err
+	}
+	return s.append(walRecord{Op: "trim", JobID: jobID, Through: through})
+}
+
+// Close takes a final snapshot and closes the log.
+func (s *State) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := s.snapshot()
+	return errors.Join(err, s.wal.Close(), s.store.Close())
+}
@@ -0,0 +1,66 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/config"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/models"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/storage"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/storage/storagetest"
+)
+
+func openTestState(t *testing.T, dir string) *State {
+	t.Helper()
+	s, err := OpenState(storage.NewMemoryStore(), config.WALConfig{
+		Enabled:          true,
+		Dir:              dir,
+		Fsync:            FsyncAlways,
+		FsyncInterval:    time.Second,
+		SnapshotInterval: time.Minute,
+	}, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestState(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storage.JobStore {
+		return openTestState(t, t.TempDir())
+	})
+}
+
+// TestStateShippedSeqs checks that a batch resent after a restart is
+// dropped, whether the restart replays the write-ahead log or loads a
+// snapshot.
+func TestStateShippedSeqs(t *testing.T) {
+	const id = "00000000-0000-0000-0000-000000000001"
+	dir := t.TempDir()
+	batch := []models.LogLine{
+		{At: time.Now(), Message: "one", Attempt: 1, ShipSeq: 1},
+		{At: time.Now(), Message: "two", Attempt: 1, ShipSeq: 2},
+	}
+
+	s := openTestState(t, dir)
+	if err := s.SaveTask(&models.Task{ID: id, Type: "echo", Status: models.TaskStatusRunning}); err != nil {
+		t.Fatal(err)
+	}
+	if added, err := s.AppendLogs(id, batch); err != nil || len(added) != 2 {
+		t.Fatalf("first append: %+v, %v", added, err)
+	}
+	// Reopen without closing, as after a crash, so the append is replayed.
+	s.wal.Close()
+
+	for _, restart := range []string{"replay", "snapshot"} {
+		s = openTestState(t, dir)
+		if added, err := s.AppendLogs(id, batch); err != nil || len(added) != 0 {
+			t.Fatalf("%s: resent batch appended %+v, %v", restart, added, err)
+		}
+		if err := s.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,303 @@
+package scheduler
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/config"
+)
+
+// DefaultQueue is the queue of jobs whose task type does not name one.
+const DefaultQueue = "default"
+
+// ErrUnknownTask is returned for submissions of undeclared task types when
+// the catalog is strict.
+var ErrUnknownTask = errors.New("unknown task type")
+
+// RetryPolicy bounds how often and how soon a failed job is retried.
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// Backoff returns the delay before retrying after the given attempt,
+// doubling from InitialBackoff up to MaxBackoff.
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	delay := p.InitialBackoff
+	for i := 1; i < attempt && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	return delay
+}
+
+// TaskType is a declared kind of job: the shape of its args and payload,
+// the queue its jobs go to, how long they may run, how they are retried and
+// whether their results are reused.
+type TaskType struct {
+	Name        string
+	Description string
+	Queue       string
+	Timeout     time.Duration
+	Retry       RetryPolicy
+	Version     string
+	Cacheable   bool
+	CacheTTL    time.Duration // 0 keeps results until invalidated
+
+	// ArgsSchema and PayloadSchema are the declared JSON Schemas, if any.
+	ArgsSchema    json.RawMessage
+	PayloadSchema json.RawMessage
+
+	args    *jsonschema.Schema
+	payload *jsonschema.Schema
+}
+
+// TaskRegistry is the scheduler's catalog of task types. Submissions are
+// checked against it before a job is created.
+type TaskRegistry struct {
+	types  map[string]*TaskType
+	strict bool
+	// retry and cacheTTL apply to undeclared types and unset fields.
+	retry    RetryPolicy
+	cacheTTL time.Duration
+}
+
+// NewTaskRegistry compiles the declared task types, failing on duplicate
+// names or invalid schemas.
+func NewTaskRegistry(cfg config.TaskTypesConfig, retry config.RetryConfig, cache config.CacheConfig) (*TaskRegistry, error) {
+	r := &TaskRegistry{
+		types:  make(map[string]*TaskType),
+		strict: cfg.Strict,
+		retry: RetryPolicy{
+			MaxAttempts:    max(retry.MaxAttempts, 1),
+			InitialBackoff: retry.InitialBackoff,
+			MaxBackoff:     retry.MaxBackoff,
+		},
+		cacheTTL: cache.TTL,
+	}
+	for _, tc := range cfg.Types {
+		if tc.Name == "" {
+			return nil, errors.New("task_types: every type needs a name")
+		}
+		if _, ok := r.types[tc.Name]; ok {
+			return nil, fmt.Errorf("task_types: %q is declared twice", tc.Name)
+		}
+		tt := &TaskType{
+			Name:        tc.Name,
+			Description: tc.Description,
+			Queue:       cmp.Or(tc.Queue, DefaultQueue),
+			Timeout:     tc.Timeout,
+			Retry: RetryPolicy{
+				MaxAttempts:    cmp.Or(tc.Retry.MaxAttempts, r.retry.MaxAttempts),
+				InitialBackoff: cmp.Or(tc.Retry.InitialBackoff, r.retry.InitialBackoff),
+				MaxBackoff:     cmp.Or(tc.Retry.MaxBackoff, r.retry.MaxBackoff),
+			},
+			Version:   tc.Version,
+			Cacheable: tc.Cacheable,
+			CacheTTL:  cmp.Or(tc.CacheTTL, r.cacheTTL),
+		}
+		var err error
+		if tt.ArgsSchema, tt.args, err = compileSchema(tc.Name, "args", tc.ArgsSchema); err != nil {
+			return nil, err
+		}
+		if tt.PayloadSchema, tt.payload, err = compileSchema(tc.Name, "payload", tc.PayloadSchema); err != nil {
+			return nil, err
+		}
+		r.types[tc.Name] = tt
+	}
+	return r, nil
+}
+
+func compileSchema(task, field, src string) (json.RawMessage, *jsonschema.Schema, error) {
+	if strings.TrimSpace(src) == "" {
+		return nil, nil, nil
+	}
+	doc, err := jsonschema.UnmarshalJSON(strings.NewReader(src))
+	if err != nil {
+		return nil, nil, fmt.Errorf("task_types: %s %s_schema: %w", task, field, err)
+	}
+	url := "tasktypes/" + task + "/" + field + ".json"
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource(url, doc); err != nil {
+		return nil, nil, fmt.Errorf("task_types: %s %s_schema: %w", task, field, err)
+	}
+	sch, err := c.Compile(url)
+	if err != nil {
+		return nil, nil, fmt.Errorf("task_types: %s %s_schema: %w", task, field, err)
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, []byte(src)); err != nil {
+		return nil, nil, fmt.Errorf("task_types: %s %s_schema: %w", task, field, err)
+	}
+	return compact.Bytes(), sch, nil
+}
+
+// Lookup returns a declared task type.
+func (r *TaskRegistry) Lookup(name string) (*TaskType, bool) {
+	tt, ok := r.types[name]
+	return tt, ok
+}
+
+// List returns the declared task types by name.
+func (r *TaskRegistry) List() []*TaskType {
+	types := make([]*TaskType, 0, len(r.types))
+	for _, tt := range r.types {
+		types = append(types, tt)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].Name < types[j].Name })
+	return types
+}
+
+// Retry returns the retry policy for jobs of a task type.
+func (r *TaskRegistry) Retry(task string) RetryPolicy {
+	if tt, ok := r.types[task]; ok {
+		return tt.Retry
+	}
+	return r.retry
+}
+
+// Version returns the declared version of a task type, or "" if it
+// declares none.
+func (r *TaskRegistry) Version(task string) string {
+	if tt, ok := r.types[task]; ok {
+		return tt.Version
+	}
+	return ""
+}
+
+// CacheTTL returns how long results of a task type are reused; 0 means
+// until invalidated.
+func (r *TaskRegistry) CacheTTL(task string) time.Duration {
+	if tt, ok := r.types[task]; ok {
+		return tt.CacheTTL
+	}
+	return r.cacheTTL
+}
+
+// Resolve checks a submission against its task type and fills in the
+// type's queue, the type's timeout unless the submission sets one, and the
+// cache key for cacheable jobs. Jobs with input or output files are never
+// cached: their result depends on more than the key covers.
+func (r *TaskRegistry) Resolve(spec *JobSpec) error {
+	tt, ok := r.types[spec.Task]
+	if !ok {
+		if r.strict {
+			return fmt.Errorf("%w %q", ErrUnknownTask, spec.Task)
+		}
+		tt = &TaskType{Name: spec.Task, Queue: DefaultQueue}
+	}
+
+	invalid := &InvalidSubmissionError{Task: spec.Task}
+	if tt.args != nil {
+		args := make([]any, len(spec.Args))
+		for i, a := range spec.Args {
+			args[i] = a
+		}
+		invalid.check("args", tt.args, args)
+	}
+	if tt.payload != nil {
+		// A job without a payload is checked as an empty object, so that
+		// required properties are reported.
+		payload := []byte("{}")
+		if len(spec.Payload) > 0 {
+			payload = spec.Payload
+		}
+		doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		invalid.check("payload", tt.payload, doc)
+	}
+	cacheable := tt.Cacheable
+	if spec.Cacheable != nil {
+		cacheable = *spec.Cacheable
+	}
+	if cacheable && (len(spec.Inputs) > 0 || len(spec.Outputs) > 0) {
+		if spec.Cacheable != nil {
+			invalid.Violations = append(invalid.Violations, Violation{
+				Field:       "cacheable",
+				Description: "jobs with input or output files cannot be cached",
+			})
+		}
+		cacheable = false
+	}
+	if len(invalid.Violations) > 0 {
+		return invalid
+	}
+
+	spec.Queue = tt.Queue
+	if spec.Timeout == 0 {
+		spec.Timeout = tt.Timeout
+	}
+	if cacheable {
+		spec.CacheKey = CacheKey(spec.Task, tt.Version, spec.Args, spec.Payload)
+	}
+	return nil
+}
+
+// Violation is one way a submission fails its task type's schema. Field
+// is "args" or "payload" followed by a JSON pointer to the offending value.
+type Violation struct {
+	Field       string
+	Description string
+}
+
+// InvalidSubmissionError lists everything wrong with a submission.
+type InvalidSubmissionError struct {
+	Task       string
+	Violations []Violation
+}
+
+func (e *InvalidSubmissionError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "invalid %q job:", e.Task)
+	for i, v := range e.Violations {
+		if i > 0 {
+			b.WriteByte(';')
+		}
+		fmt.Fprintf(&b, " %s: %s", v.Field, v.Description)
+	}
+	return b.String()
+}
+
+var schemaPrinter = message.NewPrinter(language.English)
+
+// check validates v, recording the innermost cause of each failure.
+func (e *InvalidSubmissionError) check(field string, sch *jsonschema.Schema, v any) {
+	var verr *jsonschema.ValidationError
+	if err := sch.Validate(v); errors.As(err, &verr) {
+		e.addLeaves(field, verr)
+	} else if err != nil {
+		e.Violations = append(e.Violations, Violation{Field: field, Description: err.Error()})
+	}
+}
+
+func (e *InvalidSubmissionError) addLeaves(field string, verr *jsonschema.ValidationError) {
+	if len(verr.Causes) == 0 {
+		path := field
+		for _, tok := range verr.InstanceLocation {
+			path += "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(tok)
+		}
+		e.Violations = append(e.Violations, Violation{
+			Field:       path,
+			Description: verr.ErrorKind.LocalizedString(schemaPrinter),
+		})
+		return
+	}
+	for _, cause := range verr.Causes {
+		e.addLeaves(field, cause)
+	}
+}
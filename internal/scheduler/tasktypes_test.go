@@ -0,0 +1,145 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/config"
+)
+
+const resizeSchema = `{
+	"type": "object",
+	"required": ["image", "width"],
+	"properties": {
+		"image": {"type": "string"},
+		"width": {"type": "integer", "minimum": 1}
+	},
+	"additionalProperties": false
+}`
+
+// TestResolveSchema checks that submissions failing their task type's
+// schemas are rejected as InvalidArgument, with one field violation per
+// offending value.
+func TestResolveSchema(t *testing.T) {
+	tasks := newTestRegistry(t, config.TaskTypeConfig{
+		Name:          "resize",
+		ArgsSchema:    `{"type": "array", "maxItems": 1, "items": {"enum": ["fast", "slow"]}}`,
+		PayloadSchema: resizeSchema,
+	})
+	cases := []struct {
+		name    string
+		args    []string
+		payload string
+		want    map[string]bool // violated fields, nil if valid
+	}{
+		{"valid", []string{"fast"}, `{"image": "a.png", "width": 10}`, nil},
+		{"no payload", nil, ``, map[string]bool{"payload": true}},
+		{"wrong types", nil, `{"image": 1, "width": 0}`, map[string]bool{"payload/image": true, "payload/width": true}},
+		{"unknown property", nil, `{"image": "a.png", "width": 10, "height": 5}`, map[string]bool{"payload": true}},
+		{"bad arg", []string{"fast", "medium"}, `{"image": "a.png", "width": 10}`, map[string]bool{"args": true, "args/1": true}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			spec := JobSpec{Task: "resize", Args: c.args, Payload: json.RawMessage(c.payload)}
+			err := tasks.Resolve(&spec)
+			if c.want == nil {
+				if err != nil {
+					t.Fatalf("Resolve: %v", err)
+				}
+				return
+			}
+			var invalid *InvalidSubmissionError
+			if !errors.As(err, &invalid) {
+				t.Fatalf("Resolve: %v, want an InvalidSubmissionError", err)
+			}
+
+			st := status.Convert(statusError(err))
+			if st.Code() != codes.InvalidArgument {
+				t.Fatalf("code %s, want %s", st.Code(), codes.InvalidArgument)
+			}
+			got := make(map[string]bool)
+			for _, d := range st.Details() {
+				br, ok := d.(*errdetails.BadRequest)
+				if !ok {
+					continue
+				}
+				for _, v := range br.FieldViolations {
+					if v.Description == "" {
+						t.Errorf("violation of %s has no description", v.Field)
+					}
+					got[v.Field] = true
+				}
+			}
+			if len(got) != len(c.want) {
+				t.Fatalf("violated fields %v, want %v", got, c.want)
+			}
+			for field := range c.want {
+				if !got[field] {
+					t.Fatalf("violated fields %v, want %v", got, c.want)
+				}
+			}
+		})
+	}
+}
+
+// TestResolveUnknownTask checks that undeclared task types are rejected
+// by a strict catalog and accepted on the default queue otherwise.
+func TestResolveUnknownTask(t *testing.T) {
+	for _, strict := range []bool{false, true} {
+		tasks, err := NewTaskRegistry(config.TaskTypesConfig{
+			Strict: strict,
+			Types:  []config.TaskTypeConfig{{Name: "resize", Queue: "images", PayloadSchema: resizeSchema}},
+		}, config.RetryConfig{MaxAttempts: 3}, config.CacheConfig{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		spec := JobSpec{Task: "transcode", Payload: json.RawMessage(`{"anything": true}`)}
+		err = tasks.Resolve(&spec)
+		if strict {
+			if !errors.Is(err, ErrUnknownTask) {
+				t.Fatalf("strict: Resolve: %v, want %v", err, ErrUnknownTask)
+			}
+			if code := status.Code(statusError(err)); code != codes.InvalidArgument {
+				t.Fatalf("strict: code %s, want %s", code, codes.InvalidArgument)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Resolve: %v", err)
+		}
+		if spec.Queue != DefaultQueue {
+			t.Fatalf("queue %q, want %q", spec.Queue, DefaultQueue)
+		}
+	}
+}
+
+// TestResolveVersion checks that a task type's version reaches the cache
+// key of its jobs, and that undeclared types have no version.
+func TestResolveVersion(t *testing.T) {
+	key := func(version string) string {
+		t.Helper()
+		tasks := newTestRegistry(t, config.TaskTypeConfig{Name: "resize", Version: version, Cacheable: true})
+		if got := tasks.Version("resize"); got != version {
+			t.Fatalf("Version = %q, want %q", got, version)
+		}
+		if got := tasks.Version("transcode"); got != "" {
+			t.Fatalf("Version of an undeclared type = %q, want none", got)
+		}
+		spec := JobSpec{Task: "resize", Payload: json.RawMessage(`{"image": "a.png"}`)}
+		if err := tasks.Resolve(&spec); err != nil {
+			t.Fatal(err)
+		}
+		if spec.CacheKey == "" {
+			t.Fatal("cacheable job has no cache key")
+		}
+		return spec.CacheKey
+	}
+	if key("1") == key("2") {
+		t.Fatal("jobs of different versions share a cache key")
+	}
+}
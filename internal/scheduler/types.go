@@ -1,17 +1,9 @@
 package scheduler
 
-import "time"
+import "github.com/dheeraj-sn/distributed-orchestrator/internal/models"
 
-type WorkerInfo struct {
-	ID       string
-	Host     string
-	LastSeen time.Time
-}
+// WorkerInfo is the scheduler's view of a registered worker.
+type WorkerInfo = models.Worker
 
-type Job struct {
-	ID     string
-	Task   string
-	Args   []string
-	Status string
-	Result string
-}
+// Job is the scheduler's view of a persisted task.
+type Job = models.Task
@@ -0,0 +1,59 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/models"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/storage"
+)
+
+// UniqueScope selects the earlier jobs that make a unique submission a
+// duplicate.
+type UniqueScope int
+
+const (
+	// UniqueQueued matches jobs that are waiting to run, including ones
+	// waiting for a retry.
+	UniqueQueued UniqueScope = iota
+	// UniqueActive matches jobs that are waiting to run or running.
+	UniqueActive
+	// UniqueWindow matches jobs submitted within the policy's window,
+	// whatever their state.
+	UniqueWindow
+)
+
+// UniquePolicy makes Submit answer a submission with an existing job of
+// the same task type and key instead of creating a duplicate.
+type UniquePolicy struct {
+	// Key defaults to one derived from the job's args and payload.
+	Key    string
+	Scope  UniqueScope
+	Window time.Duration
+}
+
+// UniqueKey derives the key of a unique job submitted without one, so
+// that jobs with the same task, args and payload are duplicates.
+func UniqueKey(task string, args []string, payload json.RawMessage) string {
+	return CacheKey(task, "", args, payload)
+}
+
+// duplicates returns the filter matching the jobs that make job a
+// duplicate under the policy.
+func (p *UniquePolicy) duplicates(job *Job) storage.TaskFilter {
+	filter := storage.TaskFilter{Type: job.Type, UniqueKey: job.UniqueKey}
+	switch p.Scope {
+	case UniqueQueued:
+		filter.Statuses = []models.TaskStatus{
+			models.TaskStatusPending, models.TaskStatusScheduled, models.TaskStatusQueued,
+		}
+	case UniqueActive:
+		filter.Statuses = []models.TaskStatus{
+			models.TaskStatusPending, models.TaskStatusScheduled, models.TaskStatusQueued,
+			models.TaskStatusLeased, models.TaskStatusRunning,
+		}
+	case UniqueWindow:
+		filter.CreatedSince = job.CreatedAt.Add(-p.Window)
+	}
+	return filter
+}
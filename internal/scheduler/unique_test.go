@@ -0,0 +1,123 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/models"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/storage"
+)
+
+// TestUniqueScopes checks, for each scope, which states of an earlier job
+// make a unique submission a duplicate of it.
+func TestUniqueScopes(t *testing.T) {
+	const window = time.Hour
+	type step struct {
+		name string
+		do   func(t *testing.T, jm *JobManager, id string)
+	}
+	queued := step{"queued", func(t *testing.T, jm *JobManager, id string) {
+		if err := jm.Transition(id, models.TaskStatusQueued, models.ActorScheduler, "queued"); err != nil {
+			t.Fatal(err)
+		}
+	}}
+	running := step{"running", func(t *testing.T, jm *JobManager, id string) {
+		if _, err := jm.Claim("w1", storage.ClaimFilter{IDs: []string{id}}, time.Minute); err != nil {
+			t.Fatal(err)
+		}
+		if err := jm.Start(id, "w1"); err != nil {
+			t.Fatal(err)
+		}
+	}}
+	succeeded := step{"succeeded", func(t *testing.T, jm *JobManager, id string) {
+		if err := jm.Complete(id, "w1", "done", nil); err != nil {
+			t.Fatal(err)
+		}
+	}}
+	aged := step{"outside the window", func(t *testing.T, jm *JobManager, id string) {
+		if _, err := jm.update(id, func(j *Job) error {
+			j.CreatedAt = j.CreatedAt.Add(-window)
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}}
+
+	cases := []struct {
+		name  string
+		scope UniqueScope
+		steps []step
+		dup   []bool // whether a submission after each step is a duplicate
+	}{
+		{"queued", UniqueQueued, []step{queued, running, succeeded}, []bool{true, false, false}},
+		{"active", UniqueActive, []step{queued, running, succeeded}, []bool{true, true, false}},
+		{"window", UniqueWindow, []step{queued, running, succeeded, aged}, []bool{true, true, true, false}},
+	}
+	for _, c := range cases {
+		policy := &UniquePolicy{Scope: c.scope, Window: window}
+		t.Run(c.name, func(t *testing.T) {
+			jm := newTestJobs(t)
+			spec := JobSpec{Task: "echo", Args: []string{"vacuum"}, Unique: policy}
+			first, existing, err := jm.Submit(spec)
+			if err != nil || existing {
+				t.Fatalf("first Submit = %v, %v", existing, err)
+			}
+			for i, s := range c.steps {
+				s.do(t, jm, first.ID)
+				job, existing, err := jm.Submit(spec)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if existing != c.dup[i] {
+					t.Fatalf("original %s: duplicate = %v, want %v", s.name, existing, c.dup[i])
+				}
+				if existing && job.ID != first.ID {
+					t.Fatalf("original %s: answered with %s, want %s", s.name, job.ID, first.ID)
+				}
+				if !existing {
+					// Clear the new job so that it does not answer later steps.
+					if err := jm.Cancel(job.ID, models.ActorClient, "test"); err != nil {
+						t.Fatal(err)
+					}
+					if c.scope == UniqueWindow {
+						aged.do(t, jm, job.ID)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestUniqueKeys checks that jobs are duplicates when they share a task
+// type and key, derived from the args and payload unless given.
+func TestUniqueKeys(t *testing.T) {
+	jm := newTestJobs(t)
+	submit := func(spec JobSpec) (string, bool) {
+		t.Helper()
+		job, existing, err := jm.Submit(spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return job.ID, existing
+	}
+	derived := &UniquePolicy{Scope: UniqueQueued}
+	first, _ := submit(JobSpec{Task: "echo", Args: []string{"a"}, Payload: []byte(`{"x": 1, "y": 2}`), Unique: derived})
+	if id, dup := submit(JobSpec{Task: "echo", Args: []string{"a"}, Payload: []byte(`{"y":2,"x":1}`), Unique: derived}); !dup || id != first {
+		t.Fatalf("same args and payload: duplicate = %v of %s, want %s", dup, id, first)
+	}
+	if _, dup := submit(JobSpec{Task: "echo", Args: []string{"b"}, Payload: []byte(`{"x": 1, "y": 2}`), Unique: derived}); dup {
+		t.Fatal("other args: duplicate")
+	}
+	if _, dup := submit(JobSpec{Task: "build", Args: []string{"a"}, Payload: []byte(`{"x": 1, "y": 2}`), Unique: derived}); dup {
+		t.Fatal("other task type: duplicate")
+	}
+	if _, dup := submit(JobSpec{Task: "echo", Args: []string{"a"}, Payload: []byte(`{"x": 1, "y": 2}`)}); dup {
+		t.Fatal("submission without a policy: duplicate")
+	}
+
+	keyed := &UniquePolicy{Key: "nightly", Scope: UniqueQueued}
+	nightly, _ := submit(JobSpec{Task: "echo", Args: []string{"1"}, Unique: keyed})
+	if id, dup := submit(JobSpec{Task: "echo", Args: []string{"2"}, Unique: keyed}); !dup || id != nightly {
+		t.Fatalf("same key, other args: duplicate = %v of %s, want %s", dup, id, nightly)
+	}
+}
@@ -1,37 +1,138 @@
 package scheduler
 
 import (
-	"sync"
+	"errors"
+	"sort"
 	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/models"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/storage"
 )
 
+// WorkerManager tracks worker registrations. They are kept only in the
+// store, which every scheduler replica shares, so a worker that registers
+// again through one replica is routed by its new capabilities on all.
 type WorkerManager struct {
-	mu      sync.RWMutex
-	workers map[string]*WorkerInfo
+	store  storage.JobStore
+	ttl    time.Duration
+	logger *zap.Logger
 }
 
-func NewWorkerManager() *WorkerManager {
+// NewWorkerManager returns a WorkerManager over the workers registered in
+// store. A worker counts as connected while it has been heard from within
+// ttl.
+func NewWorkerManager(store storage.JobStore, ttl time.Duration, logger *zap.Logger) *WorkerManager {
 	return &WorkerManager{
-		workers: make(map[string]*WorkerInfo),
+		store:  store,
+		ttl:    ttl,
+		logger: logger,
 	}
 }
 
-func (wm *WorkerManager) Register(id, host string) {
-	wm.mu.Lock()
-	defer wm.mu.Unlock()
-	wm.workers[id] = &WorkerInfo{
+// Register records a worker, the task types it runs and its capacity,
+// replacing any earlier registration under the same ID.
+func (wm *WorkerManager) Register(id, host string, tasks []models.TaskCapability, anyTask bool, capacity models.Resources) error {
+	return wm.store.SaveWorker(&WorkerInfo{
 		ID:       id,
 		Host:     host,
 		LastSeen: time.Now(),
-	}
+		Tasks:    tasks,
+		AnyTask:  anyTask,
+		Capacity: capacity,
+	})
 }
 
+// Heartbeat records that a worker was heard from, and reports whether it is
+// registered. Only the worker's LastSeen is written, so a heartbeat cannot
+// undo a newer registration made through another replica.
 func (wm *WorkerManager) Heartbeat(id string) bool {
-	wm.mu.Lock()
-	defer wm.mu.Unlock()
-	if worker, ok := wm.workers[id]; ok {
-		worker.LastSeen = time.Now()
-		return true
+	err := wm.store.TouchWorker(id, time.Now())
+	if errors.Is(err, storage.ErrNotFound) {
+		return false
+	}
+	if err != nil {
+		wm.logger.Warn("Failed to persist heartbeat", zap.String("worker_id", id), zap.Error(err))
+	}
+	return true
+}
+
+// Get returns a worker's current registration from the store.
+func (wm *WorkerManager) Get(id string) (*WorkerInfo, bool) {
+	w, err := wm.store.GetWorker(id)
+	if err != nil {
+		if !errors.Is(err, storage.ErrNotFound) {
+			wm.logger.Warn("Failed to load worker", zap.String("worker_id", id), zap.Error(err))
+		}
+		return nil, false
+	}
+	return w, true
+}
+
+// List returns every registered worker by ID. It reads the store, so that
+// workers connected to other replicas are included with their latest
+// heartbeat.
+func (wm *WorkerManager) List() ([]*WorkerInfo, error) {
+	workers, err := wm.store.ListWorkers()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(workers, func(i, j int) bool { return workers[i].ID < workers[j].ID })
+	return workers, nil
+}
+
+// Connected reports whether a worker has been heard from recently.
+func (wm *WorkerManager) Connected(w *WorkerInfo) bool {
+	return time.Since(w.LastSeen) <= wm.ttl
+}
+
+// Capable reports whether any connected worker runs jobs of the given task
+// type and version.
+func (wm *WorkerManager) Capable(task, version string) (bool, error) {
+	workers, err := wm.store.ListWorkers()
+	if err != nil {
+		return false, err
+	}
+	for _, w := range workers {
+		if wm.Connected(w) && w.Runs(task, version) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Fits reports whether any connected worker that runs jobs of the given
+// task type and version has the capacity for a job requesting req.
+func (wm *WorkerManager) Fits(task, version string, req models.Resources) (bool, error) {
+	workers, err := wm.store.ListWorkers()
+	if err != nil {
+		return false, err
+	}
+	for _, w := range workers {
+		if wm.Connected(w) && w.Runs(task, version) && fits(w.Capacity, req) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// claimFilter restricts the jobs a worker may claim to the task types it
+// runs at the version tasks declares for them.
+func claimFilter(w *WorkerInfo, tasks *TaskRegistry) storage.ClaimFilter {
+	var filter storage.ClaimFilter
+	if !w.AnyTask {
+		filter.Types = make([]string, 0, len(w.Tasks))
+	}
+	for _, c := range w.Tasks {
+		switch {
+		case !w.Runs(c.Task, tasks.Version(c.Task)):
+			if w.AnyTask {
+				filter.ExcludeTypes = append(filter.ExcludeTypes, c.Task)
+			}
+		case !w.AnyTask:
+			filter.Types = append(filter.Types, c.Task)
+		}
 	}
-	return false
+	return filter
 }
@@ -0,0 +1,101 @@
+package scheduler
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/config"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/models"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/storage"
+)
+
+// TestWorkerReregistration checks that two replicas sharing a store agree
+// on a worker's latest registration, whichever replica its heartbeats go
+// through.
+func TestWorkerReregistration(t *testing.T) {
+	store := storage.NewMemoryStore()
+	a := NewWorkerManager(store, time.Minute, zap.NewNop())
+	b := NewWorkerManager(store, time.Minute, zap.NewNop())
+
+	if err := a.Register("w1", "host", []models.TaskCapability{{Task: "echo"}}, false, models.Resources{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := a.Get("w1"); !ok {
+		t.Fatal("w1 not registered")
+	}
+	if err := b.Register("w1", "host", []models.TaskCapability{{Task: "build"}}, false, models.Resources{CPU: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if !a.Heartbeat("w1") {
+		t.Fatal("heartbeat through a: worker unknown")
+	}
+	for name, wm := range map[string]*WorkerManager{"a": a, "b": b} {
+		w, ok := wm.Get("w1")
+		if !ok {
+			t.Fatalf("%s: w1 not registered", name)
+		}
+		if got := fmt.Sprint(claimFilter(w, newTestRegistry(t)).Types); got != "[build]" || w.Capacity.CPU != 2 {
+			t.Fatalf("%s: w1 routes %s with capacity %+v, want the registration made through b", name, got, w.Capacity)
+		}
+	}
+	if a.Heartbeat("w2") {
+		t.Fatal("heartbeat for an unregistered worker succeeded")
+	}
+}
+
+// TestClaimFilterVersions checks that a worker is only handed jobs of a
+// task type whose declared version it runs.
+func TestClaimFilterVersions(t *testing.T) {
+	tasks := newTestRegistry(t,
+		config.TaskTypeConfig{Name: "echo", Version: "2"},
+		config.TaskTypeConfig{Name: "build"},
+	)
+	cases := []struct {
+		name    string
+		caps    []models.TaskCapability
+		anyTask bool
+		want    string // task type of the job claimed, "" for none
+	}{
+		{"older version", []models.TaskCapability{{Task: "echo", Version: "1"}}, false, ""},
+		{"declared version", []models.TaskCapability{{Task: "echo", Version: "2"}}, false, "echo"},
+		{"no version reported", []models.TaskCapability{{Task: "echo"}}, false, "echo"},
+		{"type without a version", []models.TaskCapability{{Task: "echo", Version: "1"}, {Task: "build", Version: "7"}}, false, "build"},
+		{"any task but an older version", []models.TaskCapability{{Task: "echo", Version: "1"}}, true, "build"},
+		{"any task", nil, true, "echo"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			store := storage.NewMemoryStore()
+			for i, task := range []string{"echo", "build"} {
+				err := store.SaveTask(&models.Task{
+					ID:        fmt.Sprintf("00000000-0000-0000-0000-%012d", i+1),
+					Type:      task,
+					Status:    models.TaskStatusQueued,
+					Priority:  2 - i, // echo first
+					CreatedAt: time.Now(),
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			w := &WorkerInfo{ID: "w1", Tasks: c.caps, AnyTask: c.anyTask}
+			claimed, err := store.ClaimTask(claimFilter(w, tasks), func(*models.Task) error { return nil })
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got string
+			if claimed != nil {
+				got = claimed.Type
+			}
+			if got != c.want {
+				t.Fatalf("claimed %q, want %q", got, c.want)
+			}
+			if runs := w.Runs("echo", tasks.Version("echo")); runs != (c.want == "echo") {
+				t.Fatalf("Runs(echo) = %v", runs)
+			}
+		})
+	}
+}
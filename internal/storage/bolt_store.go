@@ -0,0 +1,655 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/models"
+)
+
+var (
+	boltTasks   = []byte("tasks")   // task ID -> JSON task
+	boltQueue   = []byte("queue")   // claim order key -> task ID, for queued tasks
+	boltHeld    = []byte("held")    // task ID -> nothing, for leased and running tasks
+	boltWorkers = []byte("workers") // worker ID -> JSON worker
+	boltLogs    = []byte("logs")    // task ID -> bucket of seq -> JSON log line
+	boltShipped = []byte("shipped") // task ID -> bucket of attempt -> highest ShipSeq stored
+	boltCached  = []byte("cached")  // cache key order key -> task ID, for tasks CachedTask may return
+
+	boltTypeStarts  = []byte("type_starts")  // task type -> when its starts are paid off
+	boltQueueStarts = []byte("queue_starts") // queue -> when its starts are paid off
+)
+
+// BoltStore implements JobStore in a single local bbolt file, for dev
+// machines and small edge deployments. bbolt allows one writer at a time,
+// which makes claims atomic; the file is locked to a single process.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// OpenBoltStore opens or creates the store at path.
+func OpenBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		// Files written before the cache index existed have their index
+		// built on first open.
+		indexCache := tx.Bucket(boltCached) == nil
+		for _, name := range [][]byte{boltTasks, boltQueue, boltHeld, boltWorkers, boltLogs, boltShipped, boltCached, boltTypeStarts, boltQueueStarts} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		if !indexCache {
+			return nil
+		}
+		cached := tx.Bucket(boltCached)
+		return tx.Bucket(boltTasks).ForEach(func(_, v []byte) error {
+			var t models.Task
+			if err := json.Unmarshal(v, &t); err != nil {
+				return err
+			}
+			if !cacheResult(&t) {
+				return nil
+			}
+			return cached.Put(cachedKey(&t), []byte(t.ID))
+		})
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &BoltStore{db: db}, nil
+}
+
+// SaveTask inserts a new task.
+func (s *BoltStore) SaveTask(task *models.Task) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if tx.Bucket(boltTasks).Get([]byte(task.ID)) != nil {
+			return errors.New("task already exists: " + task.ID)
+		}
+		task.Version = 1
+		return putTask(tx, nil, task)
+	})
+}
+
+// SaveUniqueTask scans for duplicates and inserts in one write
+// transaction.
+func (s *BoltStore) SaveUniqueTask(task *models.Task, dup TaskFilter) (*models.Task, error) {
+	var existing *models.Task
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		err := tx.Bucket(boltTasks).ForEach(func(_, v []byte) error {
+			var t models.Task
+			if err := json.Unmarshal(v, &t); err != nil {
+				return err
+			}
+			if dup.Match(&t) && (existing == nil || t.CreatedAt.Before(existing.CreatedAt)) {
+				existing = &t
+			}
+			return nil
+		})
+		if err != nil || existing != nil {
+			return err
+		}
+		if tx.Bucket(boltTasks).Get([]byte(task.ID)) != nil {
+			return errors.New("task already exists: " + task.ID)
+		}
+		task.Version = 1
+		return putTask(tx, nil, task)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// GetTask loads a task.
+func (s *BoltStore) GetTask(id string) (*models.Task, error) {
+	var task *models.Task
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		task, err = getTask(tx, id)
+		return err
+	})
+	return task, err
+}
+
+// UpdateTask replaces a stored task if its version is current.
+func (s *BoltStore) UpdateTask(task *models.Task) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		stored, err := getTask(tx, task.ID)
+		if err != nil {
+			return err
+		}
+		if stored.Version != task.Version {
+			return ErrConflict
+		}
+		return putTask(tx, stored, bumpVersion(task))
+	})
+}
+
+// CachedTask loads the last task indexed under key; index entries sort by
+// completion time within a key.
+func (s *BoltStore) CachedTask(key string) (*models.Task, error) {
+	var task *models.Task
+	err := s.db.View(func(tx *bolt.Tx) error {
+		prefix := append([]byte(key), 0)
+		var id []byte
+		c := tx.Bucket(boltCached).Cursor()
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			id = v
+		}
+		if id == nil {
+			return nil
+		}
+		var err error
+		task, err = getTask(tx, string(id))
+		return err
+	})
+	return task, err
+}
+
+// ListTasks returns the matching tasks, oldest first.
+func (s *BoltStore) ListTasks(filter TaskFilter) ([]*models.Task, error) {
+	var tasks []*models.Task
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltTasks).ForEach(func(_, v []byte) error {
+			var t models.Task
+			if err := json.Unmarshal(v, &t); err != nil {
+				return err
+			}
+			if filter.Match(&t) {
+				tasks = append(tasks, &t)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(tasks, byCreation)
+	return filter.page(tasks), nil
+}
+
+// ClaimTask takes the first claimable task from the queue bucket in a
+// single write transaction.
+func (s *BoltStore) ClaimTask(filter ClaimFilter, apply func(task *models.Task) error) (*models.Task, error) {
+	var claimed *models.Task
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		holds, err := heldTasks(tx)
+		if err != nil {
+			return err
+		}
+		next, err := claimable(tx, holds, filter, 1)
+		if err != nil || len(next) == 0 {
+			return err
+		}
+		stored := next[0]
+		task := stored.Clone()
+		if err := apply(task); err != nil {
+			return err
+		}
+		if err := putTask(tx, stored, bumpVersion(task)); err != nil {
+			return err
+		}
+		holds.starts.start(task, filter, holds.now)
+		if err := putStarts(tx, holds.starts); err != nil {
+			return err
+		}
+		claimed = task
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claimed, nil
+}
+
+// PeekTasks reads the claimable tasks from the queue bucket.
+func (s *BoltStore) PeekTasks(filter ClaimFilter, limit int) ([]*models.Task, error) {
+	var tasks []*models.Task
+	err := s.db.View(func(tx *bolt.Tx) error {
+		holds, err := heldTasks(tx)
+		if err != nil {
+			return err
+		}
+		tasks, err = claimable(tx, holds, filter, limit)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// claimable returns up to limit of the queued tasks that the filter and
+// holds admit, in the filter's order. The queue bucket is kept in priority
+// order, so by age every entry is read.
+func claimable(tx *bolt.Tx, holds *holds, filter ClaimFilter, limit int) ([]*models.Task, error) {
+	var tasks []*models.Task
+	c := tx.Bucket(boltQueue).Cursor()
+	for _, id := c.First(); id != nil; _, id = c.Next() {
+		if filter.Order == ByPriority && len(tasks) >= limit {
+			break
+		}
+		t, err := getTask(tx, string(id))
+		if err != nil {
+			return nil, err
+		}
+		if filter.Allows(t) && holds.admits(t, filter) {
+			tasks = append(tasks, t)
+		}
+	}
+	if filter.Order != ByPriority {
+		sort.SliceStable(tasks, func(i, j int) bool { return filter.Order.Before(tasks[i], tasks[j]) })
+	}
+	if len(tasks) > limit {
+		tasks = tasks[:limit]
+	}
+	return tasks, nil
+}
+
+// heldTasks counts the tasks in the held bucket, with the start rate state.
+func heldTasks(tx *bolt.Tx) (*holds, error) {
+	starts, err := getStarts(tx)
+	if err != nil {
+		return nil, err
+	}
+	holds := newHolds(starts, time.Now())
+	err = tx.Bucket(boltHeld).ForEach(func(k, _ []byte) error {
+		t, err := getTask(tx, string(k))
+		if err != nil {
+			return err
+		}
+		holds.add(t)
+		return nil
+	})
+	return holds, err
+}
+
+// getStarts reads the start rate state.
+func getStarts(tx *bolt.Tx) (starts, error) {
+	starts := newStarts()
+	for _, b := range starts.buckets() {
+		err := tx.Bucket(b.name).ForEach(func(k, v []byte) error {
+			if len(v) != 8 {
+				return fmt.Errorf("%s %q: corrupt start time", b.name, k)
+			}
+			b.paidOff[string(k)] = time.Unix(0, int64(binary.BigEndian.Uint64(v)))
+			return nil
+		})
+		if err != nil {
+			return starts, err
+		}
+	}
+	return starts, nil
+}
+
+// putStarts writes the start rate state.
+func putStarts(tx *bolt.Tx, starts starts) error {
+	for _, b := range starts.buckets() {
+		bucket := tx.Bucket(b.name)
+		for name, paidOff := range b.paidOff {
+			if err := bucket.Put([]byte(name), binary.BigEndian.AppendUint64(nil, uint64(paidOff.UnixNano()))); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+type startsBucket struct {
+	name    []byte
+	paidOff map[string]time.Time
+}
+
+// buckets pairs the start rate state with the buckets it is kept in.
+func (s starts) buckets() []startsBucket {
+	return []startsBucket{{boltTypeStarts, s.types}, {boltQueueStarts, s.queues}}
+}
+
+// LimitStarts reads the start rate state.
+func (s *BoltStore) LimitStarts() (byType, byQueue map[string]time.Time, err error) {
+	var starts starts
+	err = s.db.View(func(tx *bolt.Tx) error {
+		starts, err = getStarts(tx)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return starts.types, starts.queues, nil
+}
+
+// HeldCounts counts the entries of the held bucket by type and by queue.
+func (s *BoltStore) HeldCounts() (byType, byQueue map[string]int, err error) {
+	var holds *holds
+	err = s.db.View(func(tx *bolt.Tx) error {
+		holds, err = heldTasks(tx)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return holds.types, holds.queues, nil
+}
+
+// HeldTenants counts the entries of the held bucket by tenant.
+func (s *BoltStore) HeldTenants() (map[string]int, error) {
+	var holds *holds
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		holds, err = heldTasks(tx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return holds.tenants, nil
+}
+
+// QueuedTenants counts the entries of the queue bucket by tenant.
+func (s *BoltStore) QueuedTenants() (map[string]int, error) {
+	counts := make(map[string]int)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltQueue).ForEach(func(_, id []byte) error {
+			t, err := getTask(tx, string(id))
+			if err != nil {
+				return err
+			}
+			counts[t.Tenant]++
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// RenewLeases extends the lease of every task held by workerID.
+func (s *BoltStore) RenewLeases(workerID string, until time.Time) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltHeld).ForEach(func(k, _ []byte) error {
+			task, err := getTask(tx, string(k))
+			if err != nil {
+				return err
+			}
+			if task.WorkerID != workerID {
+				return nil
+			}
+			task.LeaseExpiresAt = &until
+			task.Version++
+			return putJSON(tx.Bucket(boltTasks), k, task)
+		})
+	})
+}
+
+// ExpiredLeases returns held tasks whose lease ran out before now.
+func (s *BoltStore) ExpiredLeases(now time.Time) ([]*models.Task, error) {
+	var expired []*models.Task
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltHeld).ForEach(func(k, _ []byte) error {
+			task, err := getTask(tx, string(k))
+			if err != nil {
+				return err
+			}
+			if task.LeaseExpired(now) {
+				expired = append(expired, task)
+			}
+			return nil
+		})
+	})
+	return expired, err
+}
+
+// SaveWorker inserts or replaces a worker registration.
+func (s *BoltStore) SaveWorker(worker *models.Worker) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return putJSON(tx.Bucket(boltWorkers), []byte(worker.ID), worker)
+	})
+}
+
+// GetWorker returns a worker registration.
+func (s *BoltStore) GetWorker(id string) (*models.Worker, error) {
+	var w models.Worker
+	err := s.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(boltWorkers).Get([]byte(id))
+		if v == nil {
+			return ErrNotFound
+		}
+		return json.Unmarshal(v, &w)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// TouchWorker records that a worker was seen. The registration is read and
+// written back in one transaction, so a concurrent SaveWorker is not lost.
+func (s *BoltStore) TouchWorker(id string, seen time.Time) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(boltWorkers)
+		v := b.Get([]byte(id))
+		if v == nil {
+			return ErrNotFound
+		}
+		var w models.Worker
+		if err := json.Unmarshal(v, &w); err != nil {
+			return err
+		}
+		w.LastSeen = seen
+		return putJSON(b, []byte(id), &w)
+	})
+}
+
+// ListWorkers returns all registered workers.
+func (s *BoltStore) ListWorkers() ([]*models.Worker, error) {
+	var workers []*models.Worker
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltWorkers).ForEach(func(_, v []byte) error {
+			var w models.Worker
+			if err := json.Unmarshal(v, &w); err != nil {
+				return err
+			}
+			workers = append(workers, &w)
+			return nil
+		})
+	})
+	return workers, err
+}
+
+// AppendLogs adds lines to a job's log, numbering them with the job log
+// bucket's sequence.
+func (s *BoltStore) AppendLogs(jobID string, lines []models.LogLine) ([]models.LogLine, error) {
+	var added []models.LogLine
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.Bucket(boltLogs).CreateBucketIfNotExists([]byte(jobID))
+		if err != nil {
+			return err
+		}
+		sb, err := tx.Bucket(boltShipped).CreateBucketIfNotExists([]byte(jobID))
+		if err != nil {
+			return err
+		}
+		shipped := make(map[int]int64)
+		err = sb.ForEach(func(k, v []byte) error {
+			if len(k) != 8 || len(v) != 8 {
+				return fmt.Errorf("job %s: corrupt shipped sequence", jobID)
+			}
+			shipped[int(binary.BigEndian.Uint64(k))] = int64(binary.BigEndian.Uint64(v))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		added = unshipped(lines, shipped)
+		for i := range added {
+			seq, err := b.NextSequence()
+			if err != nil {
+				return err
+			}
+			added[i].Seq = int64(seq)
+			if err := putJSON(b, seqKey(seq), added[i].Stored()); err != nil {
+				return err
+			}
+		}
+		for attempt, seq := range shipped {
+			if err := sb.Put(seqKey(uint64(attempt)), seqKey(uint64(seq))); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return added, nil
+}
+
+// JobLogs returns part of a job's log.
+func (s *BoltStore) JobLogs(jobID string, after int64, limit int) ([]models.LogLine, error) {
+	var lines []models.LogLine
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(boltLogs).Bucket([]byte(jobID))
+		if b == nil {
+			return nil
+		}
+		c := b.Cursor()
+		for k, v := c.Seek(seqKey(uint64(max(after, 0)) + 1)); k != nil; k, v = c.Next() {
+			if limit > 0 && len(lines) == limit {
+				break
+			}
+			var line models.LogLine
+			if err := json.Unmarshal(v, &line); err != nil {
+				return err
+			}
+			lines = append(lines, line)
+		}
+		return nil
+	})
+	return lines, err
+}
+
+// DeleteLogs removes the start of a job's log. The job's bucket, and with
+// it the sequence, is kept so later appends continue the numbering.
+func (s *BoltStore) DeleteLogs(jobID string, through int64) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(boltLogs).Bucket([]byte(jobID))
+		if b == nil {
+			return nil
+		}
+		// Collect first: deleting under a cursor makes it skip keys.
+		var keys [][]byte
+		c := b.Cursor()
+		for k, _ := c.First(); k != nil && int64(binary.BigEndian.Uint64(k)) <= through; k, _ = c.Next() {
+			keys = append(keys, k)
+		}
+		for _, k := range keys {
+			if err := b.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Close releases the file lock.
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+func bumpVersion(task *models.Task) *models.Task {
+	task.Version++
+	return task
+}
+
+func getTask(tx *bolt.Tx, id string) (*models.Task, error) {
+	v := tx.Bucket(boltTasks).Get([]byte(id))
+	if v == nil {
+		return nil, ErrNotFound
+	}
+	var task models.Task
+	if err := json.Unmarshal(v, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// putTask writes task and moves its index entries from those of the
+// previously stored version, if any.
+func putTask(tx *bolt.Tx, prev, task *models.Task) error {
+	queue, held, cached := tx.Bucket(boltQueue), tx.Bucket(boltHeld), tx.Bucket(boltCached)
+	if prev != nil {
+		if err := queue.Delete(queueKey(prev)); err != nil {
+			return err
+		}
+		if err := held.Delete([]byte(prev.ID)); err != nil {
+			return err
+		}
+		if cacheResult(prev) {
+			if err := cached.Delete(cachedKey(prev)); err != nil {
+				return err
+			}
+		}
+	}
+	if cacheResult(task) {
+		if err := cached.Put(cachedKey(task), []byte(task.ID)); err != nil {
+			return err
+		}
+	}
+	switch task.Status {
+	case models.TaskStatusQueued:
+		if err := queue.Put(queueKey(task), []byte(task.ID)); err != nil {
+			return err
+		}
+	case models.TaskStatusLeased, models.TaskStatusRunning:
+		if err := held.Put([]byte(task.ID), nil); err != nil {
+			return err
+		}
+	}
+	return putJSON(tx.Bucket(boltTasks), []byte(task.ID), task)
+}
+
+func putJSON(b *bolt.Bucket, key []byte, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return b.Put(key, data)
+}
+
+func seqKey(seq uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, seq)
+}
+
+// queueKey sorts by priority descending, then creation time ascending,
+// then ID. Flipping the sign bit makes signed priorities sort as unsigned
+// bytes, and inverting the result makes higher priorities sort first.
+func queueKey(t *models.Task) []byte {
+	key := make([]byte, 16, 16+len(t.ID))
+	binary.BigEndian.PutUint64(key[0:8], ^(uint64(int64(t.Priority)) ^ 1<<63))
+	binary.BigEndian.PutUint64(key[8:16], uint64(t.CreatedAt.UnixNano()))
+	return append(key, t.ID...)
+}
+
+// cachedKey groups tasks by cache key and sorts them by completion time,
+// then ID, within it. Cache keys never contain a zero byte.
+func cachedKey(t *models.Task) []byte {
+	key := make([]byte, 0, len(t.CacheKey)+9+len(t.ID))
+	key = append(append(key, t.CacheKey...), 0)
+	key = binary.BigEndian.AppendUint64(key, uint64(t.CompletedAt.UnixNano()))
+	return append(key, t.ID...)
+}
@@ -0,0 +1,61 @@
+package storage_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/models"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/storage"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/storage/storagetest"
+)
+
+func TestBoltStore(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storage.JobStore {
+		s, err := storage.OpenBoltStore(filepath.Join(t.TempDir(), "test.db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	})
+}
+
+// TestBoltStoreCacheIndex checks that a file written before the cache
+// index existed has it built when opened.
+func TestBoltStoreCacheIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	s, err := storage.OpenBoltStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := time.Now()
+	task := &models.Task{ID: "t1", Type: "echo", Status: models.TaskStatusSucceeded, CacheKey: "k1", CompletedAt: &done}
+	if err := s.SaveTask(task); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	db, err := bolt.Open(path, 0o600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Update(func(tx *bolt.Tx) error { return tx.DeleteBucket([]byte("cached")) }); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	s, err = storage.OpenBoltStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	got, err := s.CachedTask("k1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.ID != task.ID {
+		t.Fatalf("CachedTask after reopening = %v, want %s", got, task.ID)
+	}
+}
@@ -0,0 +1,25 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/models"
+)
+
+// JobEvent announces that a task was created or changed state, or, with
+// Logs set, that lines were appended to its log. ConcurrencyKey is the
+// task's key, so that waiters can tell when a key is released.
+type JobEvent struct {
+	TaskID         string            `json:"id"`
+	Status         models.TaskStatus `json:"status,omitempty"`
+	Logs           bool              `json:"logs,omitempty"`
+	ConcurrencyKey string            `json:"concurrency_key,omitempty"`
+}
+
+// EventSource is implemented by stores shared between scheduler replicas.
+// ListenEvents delivers events written by any replica until ctx is
+// cancelled, reconnecting as needed; events may be lost while disconnected,
+// so consumers must not rely on them alone.
+type EventSource interface {
+	ListenEvents(ctx context.Context, fn func(JobEvent))
+}
@@ -0,0 +1,312 @@
+package storage
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/models"
+)
+
+// TaskFilter selects and pages the tasks returned by ListTasks. The zero
+// value matches every task.
+type TaskFilter struct {
+	// Statuses, if set, matches tasks in any of the given states.
+	Statuses []models.TaskStatus
+	// Type, if set, matches tasks of this type only.
+	Type string
+	// Queue, if set, matches tasks in this queue only.
+	Queue string
+	// Tenant, if set, matches tasks of this tenant only.
+	Tenant string
+	// WorkerID, if set, matches tasks last assigned to this worker.
+	WorkerID string
+	// CacheKey, if set, matches tasks with this cache key only; Cached
+	// matches tasks with any cache key.
+	CacheKey string
+	Cached   bool
+	// ConcurrencyKey, if set, matches tasks with this concurrency key only.
+	ConcurrencyKey string
+	// UniqueKey, if set, matches tasks with this unique key only.
+	UniqueKey string
+	// CreatedSince, if set, matches tasks created at or after it.
+	CreatedSince time.Time
+	// FinishedBefore, if set, matches tasks that completed before it.
+	FinishedBefore time.Time
+
+	// Offset skips that many matching tasks, oldest first.
+	Offset int
+	// Limit caps the number of tasks returned; 0 means no limit.
+	Limit int
+}
+
+// Match reports whether task passes the filter, ignoring paging.
+func (f TaskFilter) Match(task *models.Task) bool {
+	if len(f.Statuses) > 0 && !slices.Contains(f.Statuses, task.Status) {
+		return false
+	}
+	if f.Type != "" && task.Type != f.Type {
+		return false
+	}
+	if f.Queue != "" && task.Queue != f.Queue {
+		return false
+	}
+	if f.Tenant != "" && task.Tenant != f.Tenant {
+		return false
+	}
+	if f.WorkerID != "" && task.WorkerID != f.WorkerID {
+		return false
+	}
+	if f.CacheKey != "" && task.CacheKey != f.CacheKey {
+		return false
+	}
+	if f.Cached && task.CacheKey == "" {
+		return false
+	}
+	if f.ConcurrencyKey != "" && task.ConcurrencyKey != f.ConcurrencyKey {
+		return false
+	}
+	if f.UniqueKey != "" && task.UniqueKey != f.UniqueKey {
+		return false
+	}
+	if !f.CreatedSince.IsZero() && task.CreatedAt.Before(f.CreatedSince) {
+		return false
+	}
+	if !f.FinishedBefore.IsZero() && (task.CompletedAt == nil || !task.CompletedAt.Before(f.FinishedBefore)) {
+		return false
+	}
+	return true
+}
+
+// byCreation orders tasks as ListTasks returns them: by creation time, with
+// ties broken by ID so that pages line up from one call to the next.
+func byCreation(a, b *models.Task) int {
+	return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+}
+
+// cacheResult reports whether CachedTask may return task: it succeeded
+// under a cache key without reusing another task's result.
+func cacheResult(task *models.Task) bool {
+	return task.Status == models.TaskStatusSucceeded && task.CacheKey != "" && task.CachedFrom == "" && task.CompletedAt != nil
+}
+
+// newerResult reports whether a completed after b, with ties broken by ID.
+func newerResult(a, b *models.Task) bool {
+	return cmp.Or(a.CompletedAt.Compare(*b.CompletedAt), cmp.Compare(a.ID, b.ID)) > 0
+}
+
+// unshipped returns the lines of a batch that are not already stored,
+// given the highest ShipSeq stored for each attempt of their job, and
+// raises those to cover the lines it returns. Lines without a ShipSeq are
+// always new.
+func unshipped(lines []models.LogLine, shipped map[int]int64) []models.LogLine {
+	added := make([]models.LogLine, 0, len(lines))
+	for _, l := range lines {
+		if l.ShipSeq > 0 {
+			if l.ShipSeq <= shipped[l.Attempt] {
+				continue
+			}
+			shipped[l.Attempt] = l.ShipSeq
+		}
+		added = append(added, l)
+	}
+	return added
+}
+
+// page applies Offset and Limit to tasks that already match the filter.
+func (f TaskFilter) page(tasks []*models.Task) []*models.Task {
+	if f.Offset > 0 {
+		if f.Offset >= len(tasks) {
+			return nil
+		}
+		tasks = tasks[f.Offset:]
+	}
+	if f.Limit > 0 && len(tasks) > f.Limit {
+		tasks = tasks[:f.Limit]
+	}
+	return tasks
+}
+
+// ClaimOrder is the order in which queued tasks are claimed.
+type ClaimOrder int
+
+const (
+	// ByPriority claims the highest priority first, then the oldest.
+	ByPriority ClaimOrder = iota
+	// ByAge claims the oldest first, whatever its priority.
+	ByAge
+)
+
+// Before reports whether a is claimed before b.
+func (o ClaimOrder) Before(a, b *models.Task) bool {
+	if o == ByPriority && a.Priority != b.Priority {
+		return a.Priority > b.Priority
+	}
+	return a.CreatedAt.Before(b.CreatedAt)
+}
+
+// ClaimFilter restricts the queued tasks ClaimTask may pick and sets the
+// order it picks them in. The zero value allows every task, highest
+// priority first.
+type ClaimFilter struct {
+	// Types, if non-nil, allows only tasks of these types; an empty
+	// non-nil slice allows none.
+	Types []string
+	// ExcludeTypes and ExcludeQueues skip tasks of these types and in
+	// these queues, e.g. ones that are being throttled.
+	ExcludeTypes  []string
+	ExcludeQueues []string
+	// Tenants, if non-nil, allows only tasks of these tenants.
+	Tenants []string
+	// IDs, if non-nil, allows only these tasks, e.g. one that a scheduling
+	// policy picked.
+	IDs []string
+	// TypeLimits and QueueLimits cap how many tasks of a type, or in a
+	// queue, may be leased or running at once. Tasks that would go over a
+	// cap are skipped.
+	TypeLimits  map[string]int
+	QueueLimits map[string]int
+	// TypeRates and QueueRates limit how often tasks of a type, or in a
+	// queue, start. Tasks whose rate allows no start now are skipped, and
+	// claiming a task counts a start against the rates that apply to it.
+	TypeRates  map[string]StartRate
+	QueueRates map[string]StartRate
+	Order      ClaimOrder
+}
+
+// Allows reports whether task may be claimed under the filter.
+func (f ClaimFilter) Allows(task *models.Task) bool {
+	if f.Types != nil && !slices.Contains(f.Types, task.Type) {
+		return false
+	}
+	if f.Tenants != nil && !slices.Contains(f.Tenants, task.Tenant) {
+		return false
+	}
+	if f.IDs != nil && !slices.Contains(f.IDs, task.ID) {
+		return false
+	}
+	return !slices.Contains(f.ExcludeTypes, task.Type) && !slices.Contains(f.ExcludeQueues, task.Queue)
+}
+
+// limited reports whether the filter caps or rate-limits any task type or
+// queue.
+func (f ClaimFilter) limited() bool {
+	return len(f.TypeLimits) > 0 || len(f.QueueLimits) > 0 ||
+		len(f.TypeRates) > 0 || len(f.QueueRates) > 0
+}
+
+// allowsNone reports whether the filter has an empty allow list, which
+// rules out every task.
+func (f ClaimFilter) allowsNone() bool {
+	return f.Types != nil && len(f.Types) == 0 ||
+		f.Tenants != nil && len(f.Tenants) == 0 ||
+		f.IDs != nil && len(f.IDs) == 0
+}
+
+// StartRate allows Rate starts per second on average, and up to Burst
+// starts at once.
+type StartRate struct {
+	Rate  float64
+	Burst int
+}
+
+// interval is how long one start takes to pay off.
+func (r StartRate) interval() time.Duration {
+	return time.Duration(float64(time.Second) / r.Rate)
+}
+
+// Tokens returns how many starts the rate allows at now, given when the
+// starts made so far are paid off. The zero time means none were made.
+func (r StartRate) Tokens(paidOff, now time.Time) float64 {
+	owed := max(paidOff.Sub(now), 0)
+	return float64(r.Burst) - float64(owed)/float64(r.interval())
+}
+
+// take returns when the starts are paid off after one more at now.
+func (r StartRate) take(paidOff, now time.Time) time.Time {
+	if paidOff.Before(now) {
+		paidOff = now
+	}
+	return paidOff.Add(r.interval())
+}
+
+// starts keeps, for each task type and queue with a StartRate, when the
+// starts made under it are paid off.
+type starts struct {
+	types, queues map[string]time.Time
+}
+
+func newStarts() starts {
+	return starts{types: make(map[string]time.Time), queues: make(map[string]time.Time)}
+}
+
+// allows reports whether the filter's rates allow task to start at now.
+func (s starts) allows(task *models.Task, filter ClaimFilter, now time.Time) bool {
+	if r, ok := filter.TypeRates[task.Type]; ok && r.Tokens(s.types[task.Type], now) < 1 {
+		return false
+	}
+	if r, ok := filter.QueueRates[task.Queue]; ok && r.Tokens(s.queues[task.Queue], now) < 1 {
+		return false
+	}
+	return true
+}
+
+// start counts a start of task at now against the filter's rates.
+func (s starts) start(task *models.Task, filter ClaimFilter, now time.Time) {
+	if r, ok := filter.TypeRates[task.Type]; ok {
+		s.types[task.Type] = r.take(s.types[task.Type], now)
+	}
+	if r, ok := filter.QueueRates[task.Queue]; ok {
+		s.queues[task.Queue] = r.take(s.queues[task.Queue], now)
+	}
+}
+
+// holds counts the leased and running tasks by concurrency key, type,
+// queue and tenant. ClaimTask skips tasks whose key is held by as many
+// tasks as their limit allows, and tasks whose type or queue is at the
+// filter's cap or out of starts.
+type holds struct {
+	keys, types, queues, tenants map[string]int
+	// starts and now are checked against the filter's rates.
+	starts starts
+	now    time.Time
+}
+
+func newHolds(starts starts, now time.Time) *holds {
+	return &holds{
+		keys:    make(map[string]int),
+		types:   make(map[string]int),
+		queues:  make(map[string]int),
+		tenants: make(map[string]int),
+		starts:  starts,
+		now:     now,
+	}
+}
+
+func (h *holds) add(task *models.Task) {
+	if task.ConcurrencyKey != "" {
+		h.keys[task.ConcurrencyKey]++
+	}
+	h.types[task.Type]++
+	h.queues[task.Queue]++
+	h.tenants[task.Tenant]++
+}
+
+// admits reports whether task may be claimed under the filter without
+// exceeding the limit of its concurrency key or the cap or rate on its type
+// or queue.
+func (h *holds) admits(task *models.Task, filter ClaimFilter) bool {
+	if !h.starts.allows(task, filter, h.now) {
+		return false
+	}
+	if task.ConcurrencyKey != "" && h.keys[task.ConcurrencyKey] >= task.KeyLimit() {
+		return false
+	}
+	if n, ok := filter.TypeLimits[task.Type]; ok && h.types[task.Type] >= n {
+		return false
+	}
+	if n, ok := filter.QueueLimits[task.Queue]; ok && h.queues[task.Queue] >= n {
+		return false
+	}
+	return true
+}
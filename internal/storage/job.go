@@ -7,12 +7,73 @@ import (
 )
 
 type Job struct {
-	ID        string         `gorm:"type:uuid;primaryKey"`
-	Task      string         `gorm:"not null"`
-	Args      pq.StringArray `gorm:"type:text[]"`
-	Status    string         `gorm:"not null"`
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID               string         `gorm:"type:uuid;primaryKey"`
+	Task             string         `gorm:"not null"`
+	Args             pq.StringArray `gorm:"type:text[]"`
+	Payload          []byte         `gorm:"type:jsonb"`
+	Inputs           []byte         `gorm:"type:jsonb"`
+	Outputs          []byte         `gorm:"type:jsonb"`
+	Status           string         `gorm:"not null"`
+	Priority         int            `gorm:"not null"`
+	Queue            string         `gorm:"not null"`
+	Tenant           string         `gorm:"not null"`
+	CPU              float64        `gorm:"not null"`
+	MemoryMB         int64          `gorm:"not null"`
+	Timeout          time.Duration  `gorm:"not null"`
+	Result           string
+	Output           []byte `gorm:"type:jsonb"`
+	Error            string
+	ExitCode         int
+	Attempts         int
+	WorkerID         string
+	QueuedAt         *time.Time
+	StartedAt        *time.Time
+	CompletedAt      *time.Time
+	LeaseExpiresAt   *time.Time
+	RetryAt          *time.Time
+	CacheKey         string          `gorm:"not null"`
+	CachedFrom       string          `gorm:"not null"`
+	ConcurrencyKey   string          `gorm:"not null"`
+	ConcurrencyLimit int             `gorm:"not null"`
+	UniqueKey        string          `gorm:"not null"`
+	Version          int             `gorm:"not null"`
+	Transitions      []JobTransition `gorm:"foreignKey:JobID"`
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// JobTransition is one entry of a job's state history.
+type JobTransition struct {
+	JobID      string `gorm:"type:uuid;primaryKey"`
+	Seq        int    `gorm:"primaryKey"`
+	FromStatus string
+	ToStatus   string `gorm:"not null"`
+	Actor      string `gorm:"not null"`
+	Reason     string
+	At         time.Time `gorm:"not null"`
+}
+
+// JobLogShippedSeq is the highest ShipSeq stored for one attempt of a job.
+type JobLogShippedSeq struct {
+	JobID   string `gorm:"type:uuid;primaryKey"`
+	Attempt int    `gorm:"primaryKey"`
+	ShipSeq int64  `gorm:"not null"`
+}
+
+// LimitStart records when the starts made under the start rate of a task
+// type or queue are paid off. Kind is "task" or "queue".
+type LimitStart struct {
+	Kind    string    `gorm:"primaryKey"`
+	Name    string    `gorm:"primaryKey"`
+	PaidOff time.Time `gorm:"not null"`
+}
+
+// JobLog is one line of a job's output.
+type JobLog struct {
+	JobID   string    `gorm:"type:uuid;primaryKey"`
+	Seq     int64     `gorm:"primaryKey"`
+	At      time.Time `gorm:"not null"`
+	Message string    `gorm:"not null"`
 }
 
 func SaveJob(job *Job) error {
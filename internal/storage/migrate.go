@@ -0,0 +1,271 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/config"
+	"github.com/dheeraj-sn/distributed-orchestrator/migrations"
+)
+
+// migrationLockID is the Postgres advisory lock key held while migrating,
+// so replicas starting together apply each migration once.
+const migrationLockID = 7_246_310_524_001
+
+// ErrSchemaTooNew is returned when the database has migrations this binary
+// does not know about, i.e. it was migrated by a newer release.
+var ErrSchemaTooNew = errors.New("database schema is newer than this binary")
+
+// Migration is one numbered schema change.
+type Migration struct {
+	Version int
+	Name    string
+	up      string
+	down    string
+}
+
+// MigrationStatus reports whether a migration has been applied.
+type MigrationStatus struct {
+	Migration
+	Applied bool
+}
+
+// Migrator applies the embedded migrations. The applied version is kept in
+// a single-row schema_migrations table, the same layout the migrate CLI
+// uses, so databases migrated by either can be taken over by the other.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+	logger     *zap.Logger
+}
+
+// NewMigrator loads the embedded migrations for db.
+func NewMigrator(db *sql.DB, logger *zap.Logger) (*Migrator, error) {
+	ms, err := loadMigrations(migrations.FS)
+	if err != nil {
+		return nil, fmt.Errorf("load migrations: %w", err)
+	}
+	return &Migrator{db: db, migrations: ms, logger: logger}, nil
+}
+
+// OpenMigrator connects to the configured database and returns a Migrator
+// for it.
+func OpenMigrator(cfg config.PostgresConfig, logger *zap.Logger) (*Migrator, error) {
+	if err := InitPostgres(cfg, logger); err != nil {
+		return nil, fmt.Errorf("connect to postgres: %w", err)
+	}
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return nil, err
+	}
+	return NewMigrator(sqlDB, logger)
+}
+
+func loadMigrations(fsys fs.FS) ([]Migration, error) {
+	files, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+	byVersion := map[int]*Migration{}
+	for _, file := range files {
+		base, direction, ok := strings.Cut(strings.TrimSuffix(file, ".sql"), ".")
+		if !ok || (direction != "up" && direction != "down") {
+			return nil, fmt.Errorf("%s: want <version>_<name>.up.sql or .down.sql", file)
+		}
+		prefix, name, _ := strings.Cut(base, "_")
+		version, err := strconv.Atoi(prefix)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("%s: bad version %q", file, prefix)
+		}
+		body, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		m := byVersion[version]
+		if m == nil {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		}
+		if direction == "up" {
+			m.up = string(body)
+		} else {
+			m.down = string(body)
+		}
+	}
+
+	ms := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.up == "" {
+			return nil, fmt.Errorf("migration %04d_%s has no up file", m.Version, m.Name)
+		}
+		ms = append(ms, *m)
+	}
+	sort.Slice(ms, func(i, j int) bool { return ms[i].Version < ms[j].Version })
+	return ms, nil
+}
+
+// Latest returns the version of the newest embedded migration.
+func (m *Migrator) Latest() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Up applies every pending migration and returns how many it applied.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	applied := 0
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		current, err := m.version(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if current > m.Latest() {
+			return fmt.Errorf("%w: database is at version %d, latest known is %d", ErrSchemaTooNew, current, m.Latest())
+		}
+		for _, mig := range m.migrations {
+			if mig.Version <= current {
+				continue
+			}
+			if err := m.apply(ctx, conn, mig.up, mig.Version); err != nil {
+				return fmt.Errorf("apply %04d_%s: %w", mig.Version, mig.Name, err)
+			}
+			m.logger.Info("Applied migration", zap.Int("version", mig.Version), zap.String("name", mig.Name))
+			applied++
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down rolls back the last steps applied migrations.
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	return m.locked(ctx, func(conn *sql.Conn) error {
+		current, err := m.version(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for ; steps > 0 && current > 0; steps-- {
+			i := sort.Search(len(m.migrations), func(i int) bool { return m.migrations[i].Version >= current })
+			if i == len(m.migrations) || m.migrations[i].Version != current {
+				return fmt.Errorf("database is at unknown version %d", current)
+			}
+			mig := m.migrations[i]
+			previous := 0
+			if i > 0 {
+				previous = m.migrations[i-1].Version
+			}
+			if err := m.apply(ctx, conn, mig.down, previous); err != nil {
+				return fmt.Errorf("roll back %04d_%s: %w", mig.Version, mig.Name, err)
+			}
+			m.logger.Info("Rolled back migration", zap.Int("version", mig.Version), zap.String("name", mig.Name))
+			current = previous
+		}
+		return nil
+	})
+}
+
+// Status returns the database's schema version and every known migration.
+func (m *Migrator) Status(ctx context.Context) (int, []MigrationStatus, error) {
+	var current int
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		var err error
+		current, err = m.version(ctx, conn)
+		return err
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		statuses = append(statuses, MigrationStatus{Migration: mig, Applied: mig.Version <= current})
+	}
+	return current, statuses, nil
+}
+
+// Check fails unless the database is at exactly the latest known version.
+func (m *Migrator) Check(ctx context.Context) error {
+	current, _, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	switch {
+	case current > m.Latest():
+		return fmt.Errorf("%w: database is at version %d, latest known is %d", ErrSchemaTooNew, current, m.Latest())
+	case current < m.Latest():
+		return fmt.Errorf("database schema is at version %d, want %d; run `scheduler migrate up`", current, m.Latest())
+	}
+	return nil
+}
+
+// locked runs fn on one connection while holding the migration lock.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockID); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockID)
+
+	if _, err := conn.ExecContext(ctx,
+		"CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)"); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+// version returns the applied schema version, 0 for an empty database. A
+// dirty version means a migration failed half way outside a transaction
+// and has to be repaired by hand.
+func (m *Migrator) version(ctx context.Context, conn *sql.Conn) (int, error) {
+	var (
+		version int
+		dirty   bool
+	)
+	err := conn.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if dirty {
+		return 0, fmt.Errorf("schema version %d is dirty; repair the database and fix schema_migrations by hand", version)
+	}
+	return version, nil
+}
+
+// apply runs one migration script and records the resulting version in the
+// same transaction.
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, script string, version int) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if strings.TrimSpace(script) != "" {
+		if _, err := tx.ExecContext(ctx, script); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations"); err != nil {
+		return err
+	}
+	if version > 0 {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version, dirty) VALUES ($1, false)", version); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/config"
+)
+
+// Open creates the JobStore selected by cfg.Backend.
+func Open(cfg config.StorageConfig, logger *zap.Logger) (JobStore, error) {
+	switch cfg.Backend {
+	case "", "memory":
+		return NewMemoryStore(), nil
+	case "postgres":
+		migrator, err := OpenMigrator(cfg.Postgres, logger)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.Postgres.AutoMigrate {
+			_, err = migrator.Up(context.Background())
+		} else {
+			err = migrator.Check(context.Background())
+		}
+		if err != nil {
+			return nil, fmt.Errorf("migrate schema: %w", err)
+		}
+		return NewPostgresStore(DB, logger), nil
+	case "bolt":
+		store, err := OpenBoltStore(cfg.Bolt.Path)
+		if err != nil {
+			return nil, fmt.Errorf("open %s: %w", cfg.Bolt.Path, err)
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.Backend)
+	}
+}
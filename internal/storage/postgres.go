@@ -1,17 +1,86 @@
 package storage
 
 import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/config"
 )
 
 var DB *gorm.DB
 
-func InitPostgres(dsn string) error {
-	var err error
-	DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+// InitPostgres connects to the database described by cfg and sets DB. The
+// first connection is retried with backoff for up to cfg.StartupTimeout.
+func InitPostgres(cfg config.PostgresConfig, logger *zap.Logger) error {
+	db, err := gorm.Open(postgres.Open(PostgresDSN(cfg)), &gorm.Config{DisableAutomaticPing: true})
+	if err != nil {
+		return err
+	}
+	sqlDB, err := db.DB()
 	if err != nil {
 		return err
 	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.StartupTimeout)
+	defer cancel()
+	backoff := 500 * time.Millisecond
+	for attempt := 1; ; attempt++ {
+		err = sqlDB.PingContext(ctx)
+		if err == nil {
+			break
+		}
+		logger.Warn("Postgres not reachable, retrying",
+			zap.String("host", cfg.Host), zap.Int("attempt", attempt), zap.Duration("backoff", backoff), zap.Error(err))
+		select {
+		case <-ctx.Done():
+			sqlDB.Close()
+			return fmt.Errorf("postgres at %s:%d not reachable within %s: %w", cfg.Host, cfg.Port, cfg.StartupTimeout, err)
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, 10*time.Second)
+	}
+
+	DB = db
 	return nil
 }
+
+// PostgresDSN builds a libpq keyword/value connection string from cfg.
+func PostgresDSN(cfg config.PostgresConfig) string {
+	params := []struct{ key, value string }{
+		{"host", cfg.Host},
+		{"port", fmt.Sprint(cfg.Port)},
+		{"user", cfg.User},
+		{"password", cfg.Password},
+		{"dbname", cfg.Database},
+		{"sslmode", cfg.SSLMode},
+		{"sslrootcert", cfg.SSLRootCert},
+		{"sslcert", cfg.SSLCert},
+		{"sslkey", cfg.SSLKey},
+	}
+	var parts []string
+	for _, p := range params {
+		if p.value != "" {
+			parts = append(parts, p.key+"="+quoteDSNValue(p.value))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// quoteDSNValue single-quotes values that are empty or contain spaces,
+// quotes or backslashes, as libpq requires.
+func quoteDSNValue(v string) string {
+	if v != "" && !strings.ContainsAny(v, ` '\`) {
+		return v
+	}
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(v) + "'"
+}
@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/models"
+)
+
+// jobEventsChannel is the LISTEN/NOTIFY channel for job events.
+const jobEventsChannel = "job_events"
+
+const (
+	listenMinBackoff = 500 * time.Millisecond
+	listenMaxBackoff = 30 * time.Second
+)
+
+// notify queues a job event on tx. Postgres delivers it to listeners when
+// the transaction commits, and drops it if it rolls back.
+func notify(tx *gorm.DB, task *models.Task) error {
+	return notifyEvent(tx, JobEvent{TaskID: task.ID, Status: task.Status, ConcurrencyKey: task.ConcurrencyKey})
+}
+
+func notifyEvent(tx *gorm.DB, ev JobEvent) error {
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	return tx.Exec("SELECT pg_notify(?, ?)", jobEventsChannel, string(payload)).Error
+}
+
+// ListenEvents holds a dedicated pool connection in LISTEN mode and
+// reconnects with exponential backoff whenever it is lost.
+func (s *PostgresStore) ListenEvents(ctx context.Context, fn func(JobEvent)) {
+	backoff := listenMinBackoff
+	for {
+		start := time.Now()
+		err := s.listen(ctx, fn)
+		if ctx.Err() != nil {
+			return
+		}
+		if time.Since(start) > listenMaxBackoff {
+			backoff = listenMinBackoff
+		}
+		s.logger.Warn("Job event listener disconnected", zap.Error(err), zap.Duration("retry_in", backoff))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, listenMaxBackoff)
+	}
+}
+
+func (s *PostgresStore) listen(ctx context.Context, fn func(JobEvent)) error {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn any) error {
+		err := s.waitForEvents(ctx, driverConn.(*stdlib.Conn).Conn(), fn)
+		// Never hand a connection that is still listening back to the pool.
+		return errors.Join(err, driver.ErrBadConn)
+	})
+}
+
+func (s *PostgresStore) waitForEvents(ctx context.Context, conn *pgx.Conn, fn func(JobEvent)) error {
+	if _, err := conn.Exec(ctx, "LISTEN "+jobEventsChannel); err != nil {
+		return err
+	}
+	s.logger.Info("Listening for job events", zap.String("channel", jobEventsChannel))
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		var ev JobEvent
+		if err := json.Unmarshal([]byte(n.Payload), &ev); err != nil {
+			s.logger.Warn("Ignoring malformed job event", zap.String("payload", n.Payload))
+			continue
+		}
+		fn(ev)
+	}
+}
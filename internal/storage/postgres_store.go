@@ -0,0 +1,822 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/models"
+)
+
+// PostgresStore implements JobStore on top of the gorm models in this
+// package. Schema is managed by the SQL files in migrations/.
+type PostgresStore struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+// NewPostgresStore creates a store using an open gorm connection.
+func NewPostgresStore(db *gorm.DB, logger *zap.Logger) *PostgresStore {
+	return &PostgresStore{db: db, logger: logger}
+}
+
+// SaveTask inserts a new task along with its history.
+func (s *PostgresStore) SaveTask(task *models.Task) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return insertTask(tx, task)
+	})
+}
+
+func insertTask(tx *gorm.DB, task *models.Task) error {
+	task.Version = 1
+	if err := tx.Omit("Transitions").Create(jobFromTask(task)).Error; err != nil {
+		return err
+	}
+	if err := saveTransitions(tx, task); err != nil {
+		return err
+	}
+	return notify(tx, task)
+}
+
+// SaveUniqueTask takes a transaction-scoped advisory lock on the task type
+// and unique key before looking for duplicates, so that the lookup sees
+// every task a concurrent call for the same key inserted.
+func (s *PostgresStore) SaveUniqueTask(task *models.Task, dup TaskFilter) (*models.Task, error) {
+	var existing *models.Task
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "unique_key:"+task.Type+":"+task.UniqueKey).Error; err != nil {
+			return err
+		}
+		var row Job
+		err := filterTasks(tx.Preload("Transitions", orderBySeq), dup).Order("created_at").Take(&row).Error
+		if err == nil {
+			existing = row.toTask()
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		return insertTask(tx, task)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// CachedTask looks the key up through jobs_cache_key_idx.
+func (s *PostgresStore) CachedTask(key string) (*models.Task, error) {
+	var row Job
+	err := s.db.Preload("Transitions", orderBySeq).
+		Where("cache_key = ? AND status = ? AND cached_from = '' AND completed_at IS NOT NULL", key, models.TaskStatusSucceeded).
+		Order("completed_at DESC, id DESC").
+		Take(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return row.toTask(), nil
+}
+
+// GetTask loads a task and its history.
+func (s *PostgresStore) GetTask(id string) (*models.Task, error) {
+	var row Job
+	err := s.db.Preload("Transitions", orderBySeq).First(&row, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return row.toTask(), nil
+}
+
+// UpdateTask writes a task if its version is current, appending any new
+// history entries.
+func (s *PostgresStore) UpdateTask(task *models.Task) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return updateTask(tx, task)
+	})
+}
+
+// ClaimTask locks the next queued row with FOR UPDATE SKIP LOCKED, so
+// concurrent claimers on any replica skip past it instead of waiting for or
+// double-claiming it. Rows with a concurrency key are only claimed under a
+// transaction-scoped advisory lock on the key, so that two claimers cannot
+// both take the key's last free slot. Likewise, claims under a filter with
+// caps or rates take one advisory lock before counting the held rows and
+// reading limit_starts, and record their start in limit_starts before
+// committing; the lock comes before any key lock, so claimers never wait
+// for each other in a cycle.
+func (s *PostgresStore) ClaimTask(filter ClaimFilter, apply func(task *models.Task) error) (*models.Task, error) {
+	if filter.allowsNone() {
+		return nil, nil
+	}
+	var claimed *models.Task
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if filter.limited() {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "claim_limits").Error; err != nil {
+				return err
+			}
+		}
+		now := time.Now()
+		allowed, starts, err := withinLimits(tx, filter, now)
+		if err != nil {
+			return err
+		}
+
+		var row *Job
+		var busy []string
+		for row == nil {
+			next, err := nextQueued(tx, allowed, busy)
+			if next == nil || err != nil {
+				return err
+			}
+			free, err := keyFree(tx, next)
+			if err != nil {
+				return err
+			}
+			if free {
+				row = next
+			} else {
+				// Taken by a claim that committed after the row was picked.
+				busy = append(busy, next.ConcurrencyKey)
+			}
+		}
+		if err := tx.Where("job_id = ?", row.ID).Order("seq").Find(&row.Transitions).Error; err != nil {
+			return err
+		}
+
+		task := row.toTask()
+		if err := apply(task); err != nil {
+			return err
+		}
+		if err := updateTask(tx, task); err != nil {
+			return err
+		}
+		if err := recordStart(tx, starts, task, filter, now); err != nil {
+			return err
+		}
+		claimed = task
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claimed, nil
+}
+
+// nextQueued locks the first queued row the filter allows whose
+// concurrency key, if any, is neither in busy nor held up to its limit.
+func nextQueued(tx *gorm.DB, filter ClaimFilter, busy []string) (*Job, error) {
+	q := claimableRows(tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}), filter, busy)
+	var row Job
+	err := q.Take(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &row, nil
+}
+
+// PeekTasks selects the claimable rows without locking them.
+func (s *PostgresStore) PeekTasks(filter ClaimFilter, limit int) ([]*models.Task, error) {
+	if filter.allowsNone() {
+		return nil, nil
+	}
+	filter, _, err := withinLimits(s.db, filter, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	var rows []Job
+	if err := claimableRows(s.db.Preload("Transitions", orderBySeq), filter, nil).Limit(limit).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	tasks := make([]*models.Task, len(rows))
+	for i := range rows {
+		tasks[i] = rows[i].toTask()
+	}
+	return tasks, nil
+}
+
+// claimableRows selects the queued rows the filter allows whose
+// concurrency key, if any, is neither in busy nor held up to its limit, in
+// the filter's order.
+func claimableRows(q *gorm.DB, filter ClaimFilter, busy []string) *gorm.DB {
+	q = q.Where("status = ?", string(models.TaskStatusQueued)).
+		Where("concurrency_key = '' OR (SELECT count(*) FROM jobs h WHERE h.concurrency_key = jobs.concurrency_key AND h.status IN ?) < GREATEST(concurrency_limit, 1)", heldStatuses)
+	if filter.Types != nil {
+		q = q.Where("task IN ?", filter.Types)
+	}
+	if len(filter.ExcludeTypes) > 0 {
+		q = q.Where("task NOT IN ?", filter.ExcludeTypes)
+	}
+	if len(filter.ExcludeQueues) > 0 {
+		q = q.Where("queue NOT IN ?", filter.ExcludeQueues)
+	}
+	if filter.Tenants != nil {
+		q = q.Where("tenant IN ?", filter.Tenants)
+	}
+	if filter.IDs != nil {
+		q = q.Where("id IN ?", filter.IDs)
+	}
+	if len(busy) > 0 {
+		q = q.Where("concurrency_key NOT IN ?", busy)
+	}
+	if filter.Order == ByAge {
+		return q.Order("created_at")
+	}
+	return q.Order("priority DESC, created_at")
+}
+
+// keyFree takes the advisory lock on the row's concurrency key and reports
+// whether the key still has a free slot. The count runs after the lock is
+// granted, so it sees every claim of the key that committed before.
+func keyFree(tx *gorm.DB, row *Job) (bool, error) {
+	if row.ConcurrencyKey == "" {
+		return true, nil
+	}
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "concurrency_key:"+row.ConcurrencyKey).Error; err != nil {
+		return false, err
+	}
+	var held int64
+	err := tx.Model(&Job{}).
+		Where("concurrency_key = ? AND status IN ?", row.ConcurrencyKey, heldStatuses).
+		Count(&held).Error
+	if err != nil {
+		return false, err
+	}
+	return held < int64(max(row.ConcurrencyLimit, 1)), nil
+}
+
+// withinLimits adds the task types and queues that are at the filter's
+// caps, or that its rates allow no start at now, to the ones it excludes.
+// It also returns the start rate state it read.
+func withinLimits(q *gorm.DB, filter ClaimFilter, now time.Time) (ClaimFilter, starts, error) {
+	if !filter.limited() {
+		return filter, starts{}, nil
+	}
+	byType, byQueue, err := heldCounts(q)
+	if err != nil {
+		return filter, starts{}, err
+	}
+	starts, err := limitStarts(q)
+	if err != nil {
+		return filter, starts, err
+	}
+	filter.ExcludeTypes = slices.Clip(filter.ExcludeTypes)
+	for task, n := range filter.TypeLimits {
+		if byType[task] >= n {
+			filter.ExcludeTypes = append(filter.ExcludeTypes, task)
+		}
+	}
+	for task, r := range filter.TypeRates {
+		if r.Tokens(starts.types[task], now) < 1 {
+			filter.ExcludeTypes = append(filter.ExcludeTypes, task)
+		}
+	}
+	filter.ExcludeQueues = slices.Clip(filter.ExcludeQueues)
+	for queue, n := range filter.QueueLimits {
+		if byQueue[queue] >= n {
+			filter.ExcludeQueues = append(filter.ExcludeQueues, queue)
+		}
+	}
+	for queue, r := range filter.QueueRates {
+		if r.Tokens(starts.queues[queue], now) < 1 {
+			filter.ExcludeQueues = append(filter.ExcludeQueues, queue)
+		}
+	}
+	return filter, starts, nil
+}
+
+// recordStart counts a start of task at now against the filter's rates in
+// limit_starts.
+func recordStart(tx *gorm.DB, starts starts, task *models.Task, filter ClaimFilter, now time.Time) error {
+	_, typed := filter.TypeRates[task.Type]
+	_, queued := filter.QueueRates[task.Queue]
+	if !typed && !queued {
+		return nil
+	}
+	starts.start(task, filter, now)
+	var rows []LimitStart
+	if typed {
+		rows = append(rows, LimitStart{Kind: "task", Name: task.Type, PaidOff: starts.types[task.Type]})
+	}
+	if queued {
+		rows = append(rows, LimitStart{Kind: "queue", Name: task.Queue, PaidOff: starts.queues[task.Queue]})
+	}
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "kind"}, {Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"paid_off"}),
+	}).Create(&rows).Error
+}
+
+// LimitStarts reads limit_starts.
+func (s *PostgresStore) LimitStarts() (byType, byQueue map[string]time.Time, err error) {
+	starts, err := limitStarts(s.db)
+	if err != nil {
+		return nil, nil, err
+	}
+	return starts.types, starts.queues, nil
+}
+
+func limitStarts(q *gorm.DB) (starts, error) {
+	starts := newStarts()
+	var rows []LimitStart
+	if err := q.Find(&rows).Error; err != nil {
+		return starts, err
+	}
+	for _, r := range rows {
+		if r.Kind == "task" {
+			starts.types[r.Name] = r.PaidOff
+		} else {
+			starts.queues[r.Name] = r.PaidOff
+		}
+	}
+	return starts, nil
+}
+
+// HeldCounts counts the leased and running rows by task type and by queue.
+func (s *PostgresStore) HeldCounts() (byType, byQueue map[string]int, err error) {
+	return heldCounts(s.db)
+}
+
+func heldCounts(q *gorm.DB) (byType, byQueue map[string]int, err error) {
+	var rows []struct {
+		Task  string
+		Queue string
+		Count int
+	}
+	err = q.Model(&Job{}).
+		Select("task, queue, count(*) AS count").
+		Where("status IN ?", heldStatuses).
+		Group("task, queue").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	byType = make(map[string]int)
+	byQueue = make(map[string]int)
+	for _, r := range rows {
+		byType[r.Task] += r.Count
+		byQueue[r.Queue] += r.Count
+	}
+	return byType, byQueue, nil
+}
+
+// QueuedTenants counts the queued rows by tenant.
+func (s *PostgresStore) QueuedTenants() (map[string]int, error) {
+	return s.tenantCounts([]string{string(models.TaskStatusQueued)})
+}
+
+// HeldTenants counts the leased and running rows by tenant.
+func (s *PostgresStore) HeldTenants() (map[string]int, error) {
+	return s.tenantCounts(heldStatuses)
+}
+
+func (s *PostgresStore) tenantCounts(statuses []string) (map[string]int, error) {
+	var rows []struct {
+		Tenant string
+		Count  int
+	}
+	err := s.db.Model(&Job{}).
+		Select("tenant, count(*) AS count").
+		Where("status IN ?", statuses).
+		Group("tenant").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int, len(rows))
+	for _, r := range rows {
+		counts[r.Tenant] = r.Count
+	}
+	return counts, nil
+}
+
+// RenewLeases extends the lease of every task held by workerID.
+func (s *PostgresStore) RenewLeases(workerID string, until time.Time) error {
+	return s.db.Model(&Job{}).
+		Where("worker_id = ? AND status IN ?", workerID, heldStatuses).
+		Updates(map[string]any{
+			"lease_expires_at": until,
+			"version":          gorm.Expr("version + 1"),
+		}).Error
+}
+
+// ExpiredLeases returns held tasks whose lease ran out before now.
+func (s *PostgresStore) ExpiredLeases(now time.Time) ([]*models.Task, error) {
+	var rows []Job
+	err := s.db.Preload("Transitions", orderBySeq).
+		Where("status IN ? AND lease_expires_at <= ?", heldStatuses, now).
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	tasks := make([]*models.Task, 0, len(rows))
+	for i := range rows {
+		tasks = append(tasks, rows[i].toTask())
+	}
+	return tasks, nil
+}
+
+var heldStatuses = []string{string(models.TaskStatusLeased), string(models.TaskStatusRunning)}
+
+// updateTask performs the versioned update of a job row inside tx.
+func updateTask(tx *gorm.DB, task *models.Task) error {
+	row := jobFromTask(task)
+	row.Version = task.Version + 1
+	res := tx.Model(&Job{}).
+		Where("id = ? AND version = ?", task.ID, task.Version).
+		Select("*").Omit("id", "created_at", "Transitions").
+		Updates(row)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		var count int64
+		if err := tx.Model(&Job{}).Where("id = ?", task.ID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return ErrNotFound
+		}
+		return ErrConflict
+	}
+	if err := saveTransitions(tx, task); err != nil {
+		return err
+	}
+	if err := notify(tx, task); err != nil {
+		return err
+	}
+	task.Version = row.Version
+	return nil
+}
+
+// ListTasks returns the matching tasks, oldest first.
+func (s *PostgresStore) ListTasks(filter TaskFilter) ([]*models.Task, error) {
+	q := filterTasks(s.db.Preload("Transitions", orderBySeq).Order("created_at, id"), filter)
+	if filter.Offset > 0 {
+		q = q.Offset(filter.Offset)
+	}
+	if filter.Limit > 0 {
+		q = q.Limit(filter.Limit)
+	}
+	var rows []Job
+	if err := q.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	tasks := make([]*models.Task, 0, len(rows))
+	for i := range rows {
+		tasks = append(tasks, rows[i].toTask())
+	}
+	return tasks, nil
+}
+
+// filterTasks adds the conditions of filter, but not its paging, to q.
+func filterTasks(q *gorm.DB, filter TaskFilter) *gorm.DB {
+	if len(filter.Statuses) > 0 {
+		q = q.Where("status IN ?", filter.Statuses)
+	}
+	if filter.Type != "" {
+		q = q.Where("task = ?", filter.Type)
+	}
+	if filter.Queue != "" {
+		q = q.Where("queue = ?", filter.Queue)
+	}
+	if filter.Tenant != "" {
+		q = q.Where("tenant = ?", filter.Tenant)
+	}
+	if filter.WorkerID != "" {
+		q = q.Where("worker_id = ?", filter.WorkerID)
+	}
+	if filter.CacheKey != "" {
+		q = q.Where("cache_key = ?", filter.CacheKey)
+	}
+	if filter.Cached {
+		q = q.Where("cache_key <> ''")
+	}
+	if filter.ConcurrencyKey != "" {
+		q = q.Where("concurrency_key = ?", filter.ConcurrencyKey)
+	}
+	if filter.UniqueKey != "" {
+		q = q.Where("unique_key = ?", filter.UniqueKey)
+	}
+	if !filter.CreatedSince.IsZero() {
+		q = q.Where("created_at >= ?", filter.CreatedSince)
+	}
+	if !filter.FinishedBefore.IsZero() {
+		q = q.Where("completed_at < ?", filter.FinishedBefore)
+	}
+	return q
+}
+
+// SaveWorker inserts or replaces a worker registration.
+func (s *PostgresStore) SaveWorker(worker *models.Worker) error {
+	return s.db.Save(&Worker{
+		ID:            worker.ID,
+		Host:          worker.Host,
+		LastHeartbeat: worker.LastSeen,
+		Tasks:         jsonColumn(worker.Tasks),
+		AnyTask:       worker.AnyTask,
+
+		CapacityCPU:      worker.Capacity.CPU,
+		CapacityMemoryMB: worker.Capacity.MemoryMB,
+	}).Error
+}
+
+// GetWorker returns a worker registration.
+func (s *PostgresStore) GetWorker(id string) (*models.Worker, error) {
+	var row Worker
+	err := s.db.First(&row, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toWorker(&row)
+}
+
+// TouchWorker updates only a worker's last_heartbeat column, so that a
+// heartbeat through one replica cannot write back a registration that the
+// worker has since replaced through another.
+func (s *PostgresStore) TouchWorker(id string, seen time.Time) error {
+	res := s.db.Model(&Worker{}).Where("id = ?", id).Update("last_heartbeat", seen)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListWorkers returns all registered workers.
+func (s *PostgresStore) ListWorkers() ([]*models.Worker, error) {
+	var rows []Worker
+	if err := s.db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	workers := make([]*models.Worker, 0, len(rows))
+	for i := range rows {
+		w, err := toWorker(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		workers = append(workers, w)
+	}
+	return workers, nil
+}
+
+func toWorker(r *Worker) (*models.Worker, error) {
+	w := &models.Worker{
+		ID:       r.ID,
+		Host:     r.Host,
+		LastSeen: r.LastHeartbeat,
+		AnyTask:  r.AnyTask,
+		Capacity: models.Resources{CPU: r.CapacityCPU, MemoryMB: r.CapacityMemoryMB},
+	}
+	if len(r.Tasks) == 0 {
+		return w, nil
+	}
+	if err := json.Unmarshal(r.Tasks, &w.Tasks); err != nil {
+		return nil, fmt.Errorf("worker %s: tasks: %w", r.ID, err)
+	}
+	return w, nil
+}
+
+// AppendLogs adds lines to a job's log. The job's row in job_log_seqs is
+// locked first, which serializes concurrent appends to the same job across
+// replicas while the lines are checked against job_log_shipped_seqs and
+// numbered.
+func (s *PostgresStore) AppendLogs(jobID string, lines []models.LogLine) ([]models.LogLine, error) {
+	if len(lines) == 0 {
+		return nil, nil
+	}
+	var added []models.LogLine
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var last int64
+		err := tx.Raw(`INSERT INTO job_log_seqs (job_id, last_seq) VALUES (?, 0)
+			ON CONFLICT (job_id) DO UPDATE SET last_seq = job_log_seqs.last_seq
+			RETURNING last_seq`, jobID).Scan(&last).Error
+		if err != nil {
+			return err
+		}
+
+		var seqs []JobLogShippedSeq
+		if err := tx.Where("job_id = ?", jobID).Find(&seqs).Error; err != nil {
+			return err
+		}
+		shipped := make(map[int]int64, len(seqs))
+		for _, r := range seqs {
+			shipped[r.Attempt] = r.ShipSeq
+		}
+		before := maps.Clone(shipped)
+		added = unshipped(lines, shipped)
+		if len(added) == 0 {
+			return nil
+		}
+		seqs = seqs[:0]
+		for attempt, seq := range shipped {
+			if seq != before[attempt] {
+				seqs = append(seqs, JobLogShippedSeq{JobID: jobID, Attempt: attempt, ShipSeq: seq})
+			}
+		}
+		if len(seqs) > 0 {
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "job_id"}, {Name: "attempt"}},
+				DoUpdates: clause.AssignmentColumns([]string{"ship_seq"}),
+			}).Create(&seqs).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		rows := make([]JobLog, 0, len(added))
+		for i := range added {
+			added[i].Seq = last + int64(i) + 1
+			rows = append(rows, JobLog{JobID: jobID, Seq: added[i].Seq, At: added[i].At, Message: added[i].Message})
+		}
+		err = tx.Exec("UPDATE job_log_seqs SET last_seq = ? WHERE job_id = ?", last+int64(len(added)), jobID).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.CreateInBatches(&rows, 500).Error; err != nil {
+			return err
+		}
+		return notifyEvent(tx, JobEvent{TaskID: jobID, Logs: true})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return added, nil
+}
+
+// JobLogs returns part of a job's log.
+func (s *PostgresStore) JobLogs(jobID string, after int64, limit int) ([]models.LogLine, error) {
+	q := s.db.Where("job_id = ? AND seq > ?", jobID, after).Order("seq")
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	var rows []JobLog
+	if err := q.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	lines := make([]models.LogLine, 0, len(rows))
+	for _, r := range rows {
+		lines = append(lines, models.LogLine{Seq: r.Seq, At: r.At, Message: r.Message})
+	}
+	return lines, nil
+}
+
+// DeleteLogs removes the start of a job's log.
+func (s *PostgresStore) DeleteLogs(jobID string, through int64) error {
+	return s.db.Where("job_id = ? AND seq <= ?", jobID, through).Delete(&JobLog{}).Error
+}
+
+// Ping checks that the database is reachable.
+func (s *PostgresStore) Ping(ctx context.Context) error {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// Close closes the underlying connection pool.
+func (s *PostgresStore) Close() error {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
+func orderBySeq(db *gorm.DB) *gorm.DB {
+	return db.Order("seq")
+}
+
+// saveTransitions inserts history entries that are not stored yet. Entries
+// are keyed by (job_id, seq) so rewriting existing ones is a no-op.
+func saveTransitions(tx *gorm.DB, task *models.Task) error {
+	if len(task.History) == 0 {
+		return nil
+	}
+	rows := make([]JobTransition, 0, len(task.History))
+	for i, t := range task.History {
+		rows = append(rows, JobTransition{
+			JobID:      task.ID,
+			Seq:        i,
+			FromStatus: string(t.From),
+			ToStatus:   string(t.To),
+			Actor:      t.Actor,
+			Reason:     t.Reason,
+			At:         t.At,
+		})
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+}
+
+func jobFromTask(t *models.Task) *Job {
+	return &Job{
+		ID:               t.ID,
+		Task:             t.Type,
+		Args:             t.Args,
+		Payload:          t.Payload,
+		Inputs:           jsonColumn(t.Inputs),
+		Outputs:          jsonColumn(t.Outputs),
+		Status:           string(t.Status),
+		Priority:         t.Priority,
+		Queue:            t.Queue,
+		Tenant:           t.Tenant,
+		CPU:              t.Resources.CPU,
+		MemoryMB:         t.Resources.MemoryMB,
+		Timeout:          t.Timeout,
+		Result:           t.Result,
+		Output:           t.Output,
+		Error:            t.Error,
+		ExitCode:         t.ExitCode,
+		Attempts:         t.Attempts,
+		WorkerID:         t.WorkerID,
+		QueuedAt:         t.QueuedAt,
+		StartedAt:        t.StartedAt,
+		CompletedAt:      t.CompletedAt,
+		LeaseExpiresAt:   t.LeaseExpiresAt,
+		RetryAt:          t.RetryAt,
+		CacheKey:         t.CacheKey,
+		CachedFrom:       t.CachedFrom,
+		ConcurrencyKey:   t.ConcurrencyKey,
+		ConcurrencyLimit: t.ConcurrencyLimit,
+		UniqueKey:        t.UniqueKey,
+		Version:          t.Version,
+		CreatedAt:        t.CreatedAt,
+	}
+}
+
+func (j *Job) toTask() *models.Task {
+	t := &models.Task{
+		ID:               j.ID,
+		Type:             j.Task,
+		Args:             j.Args,
+		Payload:          j.Payload,
+		Status:           models.TaskStatus(j.Status),
+		Priority:         j.Priority,
+		Queue:            j.Queue,
+		Tenant:           j.Tenant,
+		Resources:        models.Resources{CPU: j.CPU, MemoryMB: j.MemoryMB},
+		Timeout:          j.Timeout,
+		Result:           j.Result,
+		Output:           j.Output,
+		Error:            j.Error,
+		ExitCode:         j.ExitCode,
+		Attempts:         j.Attempts,
+		WorkerID:         j.WorkerID,
+		CreatedAt:        j.CreatedAt,
+		QueuedAt:         j.QueuedAt,
+		StartedAt:        j.StartedAt,
+		CompletedAt:      j.CompletedAt,
+		LeaseExpiresAt:   j.LeaseExpiresAt,
+		RetryAt:          j.RetryAt,
+		CacheKey:         j.CacheKey,
+		CachedFrom:       j.CachedFrom,
+		ConcurrencyKey:   j.ConcurrencyKey,
+		ConcurrencyLimit: j.ConcurrencyLimit,
+		UniqueKey:        j.UniqueKey,
+		Version:          j.Version,
+	}
+	// The columns only ever hold what jsonColumn wrote.
+	json.Unmarshal(j.Inputs, &t.Inputs)
+	json.Unmarshal(j.Outputs, &t.Outputs)
+	for _, tr := range j.Transitions {
+		t.History = append(t.History, models.Transition{
+			From:   models.TaskStatus(tr.FromStatus),
+			To:     models.TaskStatus(tr.ToStatus),
+			At:     tr.At,
+			Actor:  tr.Actor,
+			Reason: tr.Reason,
+		})
+	}
+	return t
+}
+
+// jsonColumn encodes a slice for a JSONB column, leaving it NULL when empty.
+func jsonColumn[T any](v []T) []byte {
+	if len(v) == 0 {
+		return nil
+	}
+	b, _ := json.Marshal(v)
+	return b
+}
@@ -0,0 +1,67 @@
+package storage_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/storage"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/storage/storagetest"
+)
+
+// TestPostgresStore runs against the database in TEST_POSTGRES_DSN, a
+// keyword/value connection string such as
+// "host=localhost user=postgres password=postgres dbname=orchestrator".
+// Every scenario gets a schema of its own, dropped when it ends.
+func TestPostgresStore(t *testing.T) {
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+	gormConfig := &gorm.Config{Logger: logger.Discard}
+	admin, err := gorm.Open(postgres.Open(dsn), gormConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sqlDB, err := admin.DB(); err == nil {
+		t.Cleanup(func() { sqlDB.Close() })
+	}
+
+	n := 0
+	storagetest.Run(t, func(t *testing.T) storage.JobStore {
+		n++
+		schema := fmt.Sprintf("storagetest_%d_%d", os.Getpid(), n)
+		if err := admin.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			if err := admin.Exec("DROP SCHEMA " + schema + " CASCADE").Error; err != nil {
+				t.Errorf("drop schema %s: %v", schema, err)
+			}
+		})
+
+		db, err := gorm.Open(postgres.Open(dsn+" search_path="+schema), gormConfig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sqlDB, err := db.DB()
+		if err != nil {
+			t.Fatal(err)
+		}
+		migrator, err := storage.NewMigrator(sqlDB, zap.NewNop())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := migrator.Up(context.Background()); err != nil {
+			sqlDB.Close()
+			t.Fatalf("migrate: %v", err)
+		}
+		return storage.NewPostgresStore(db, zap.NewNop())
+	})
+}
@@ -0,0 +1,975 @@
+// Package storagetest is a conformance suite for storage.JobStore
+// implementations. Each backend runs the same scenarios from its own test:
+//
+//	func TestBoltStore(t *testing.T) {
+//		storagetest.Run(t, func(t *testing.T) storage.JobStore {
+//			s, err := storage.OpenBoltStore(filepath.Join(t.TempDir(), "test.db"))
+//			if err != nil {
+//				t.Fatal(err)
+//			}
+//			return s
+//		})
+//	}
+package storagetest
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/models"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/storage"
+)
+
+// Run runs every scenario against a fresh store from newStore. The suite
+// closes each store when its scenario ends.
+func Run(t *testing.T, newStore func(t *testing.T) storage.JobStore) {
+	scenarios := []struct {
+		name string
+		fn   func(t *testing.T, s storage.JobStore)
+	}{
+		{"SaveAndGet", testSaveAndGet},
+		{"NotFound", testNotFound},
+		{"VersionConflict", testVersionConflict},
+		{"ListOrder", testListOrder},
+		{"ListFilter", testListFilter},
+		{"ClaimOrder", testClaimOrder},
+		{"ClaimFilter", testClaimFilter},
+		{"PeekAndPick", testPeekAndPick},
+		{"ConcurrencyKeys", testConcurrencyKeys},
+		{"ClaimLimits", testClaimLimits},
+		{"ClaimRates", testClaimRates},
+		{"ClaimAbort", testClaimAbort},
+		{"ConcurrentClaims", testConcurrentClaims},
+		{"UniqueTasks", testUniqueTasks},
+		{"CachedTasks", testCachedTasks},
+		{"Leases", testLeases},
+		{"Workers", testWorkers},
+		{"Logs", testLogs},
+		{"ShippedLogs", testShippedLogs},
+	}
+	for _, sc := range scenarios {
+		t.Run(sc.name, func(t *testing.T) {
+			s := newStore(t)
+			defer s.Close()
+			sc.fn(t, s)
+		})
+	}
+}
+
+var epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func newTask(id string, status models.TaskStatus, priority int, created time.Duration) *models.Task {
+	return &models.Task{
+		ID:        id,
+		Type:      "echo",
+		Args:      []string{"hello"},
+		Status:    status,
+		Priority:  priority,
+		CreatedAt: epoch.Add(created),
+		History: []models.Transition{
+			{To: models.TaskStatusPending, At: epoch.Add(created), Actor: models.ActorClient, Reason: "submitted"},
+		},
+	}
+}
+
+func save(t *testing.T, s storage.JobStore, tasks ...*models.Task) {
+	t.Helper()
+	for _, task := range tasks {
+		if err := s.SaveTask(task); err != nil {
+			t.Fatalf("save %s: %v", task.ID, err)
+		}
+	}
+}
+
+// claim marks the first queued task leased by workerID.
+func claim(s storage.JobStore, workerID string, until time.Time) (*models.Task, error) {
+	return claimFiltered(s, storage.ClaimFilter{}, workerID, until)
+}
+
+// claimFiltered is claim restricted to the tasks filter allows.
+func claimFiltered(s storage.JobStore, filter storage.ClaimFilter, workerID string, until time.Time) (*models.Task, error) {
+	return s.ClaimTask(filter, func(task *models.Task) error {
+		task.Status = models.TaskStatusLeased
+		task.WorkerID = workerID
+		task.Attempts++
+		task.LeaseExpiresAt = &until
+		return nil
+	})
+}
+
+func testSaveAndGet(t *testing.T, s storage.JobStore) {
+	task := newTask("00000000-0000-0000-0000-000000000001", models.TaskStatusPending, 2, 0)
+	task.Resources = models.Resources{CPU: 0.5, MemoryMB: 512}
+	retryAt := epoch.Add(time.Minute)
+	task.RetryAt = &retryAt
+	save(t, s, task)
+	got, err := s.GetTask("00000000-0000-0000-0000-000000000001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Type != "echo" || len(got.Args) != 1 || got.Args[0] != "hello" || got.Priority != 2 || got.Resources != task.Resources {
+		t.Fatalf("round trip lost fields: %+v", got)
+	}
+	if got.RetryAt == nil || !got.RetryAt.Equal(retryAt) {
+		t.Fatalf("retry at = %v, want %v", got.RetryAt, retryAt)
+	}
+	if got.Version != 1 {
+		t.Fatalf("version = %d, want 1", got.Version)
+	}
+	if len(got.History) != 1 || got.History[0].Reason != "submitted" {
+		t.Fatalf("history = %+v", got.History)
+	}
+	if err := s.SaveTask(newTask(got.ID, models.TaskStatusPending, 0, 0)); err == nil {
+		t.Fatal("saving a duplicate ID succeeded")
+	}
+}
+
+func testNotFound(t *testing.T, s storage.JobStore) {
+	const id = "00000000-0000-0000-0000-00000000ffff"
+	if _, err := s.GetTask(id); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("get: err = %v, want ErrNotFound", err)
+	}
+	task := newTask(id, models.TaskStatusQueued, 0, 0)
+	task.Version = 1
+	if err := s.UpdateTask(task); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("update: err = %v, want ErrNotFound", err)
+	}
+}
+
+func testVersionConflict(t *testing.T, s storage.JobStore) {
+	task := newTask("00000000-0000-0000-0000-000000000001", models.TaskStatusPending, 0, 0)
+	save(t, s, task)
+	a, _ := s.GetTask(task.ID)
+	b, _ := s.GetTask(task.ID)
+
+	a.Status = models.TaskStatusQueued
+	a.History = append(a.History, models.Transition{From: models.TaskStatusPending, To: models.TaskStatusQueued, At: epoch, Actor: models.ActorScheduler})
+	if err := s.UpdateTask(a); err != nil {
+		t.Fatal(err)
+	}
+	if a.Version != 2 {
+		t.Fatalf("version after update = %d, want 2", a.Version)
+	}
+	b.Status = models.TaskStatusCancelled
+	if err := s.UpdateTask(b); !errors.Is(err, storage.ErrConflict) {
+		t.Fatalf("stale update: err = %v, want ErrConflict", err)
+	}
+	got, _ := s.GetTask(task.ID)
+	if got.Status != models.TaskStatusQueued || len(got.History) != 2 {
+		t.Fatalf("stored task = %s with %d transitions", got.Status, len(got.History))
+	}
+}
+
+func testListOrder(t *testing.T, s storage.JobStore) {
+	save(t, s,
+		newTask("00000000-0000-0000-0000-000000000003", models.TaskStatusPending, 0, 3*time.Second),
+		newTask("00000000-0000-0000-0000-000000000001", models.TaskStatusPending, 0, time.Second),
+		newTask("00000000-0000-0000-0000-000000000002", models.TaskStatusPending, 0, 2*time.Second),
+	)
+	tasks, err := s.ListTasks(storage.TaskFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 3 {
+		t.Fatalf("listed %d tasks, want 3", len(tasks))
+	}
+	for i, task := range tasks {
+		if want := fmt.Sprintf("00000000-0000-0000-0000-%012d", i+1); task.ID != want {
+			t.Fatalf("tasks[%d] = %s, want %s", i, task.ID, want)
+		}
+	}
+
+	// Tasks created at the same time come in ID order, so that paging
+	// neither skips nor repeats any.
+	for i := 9; i >= 4; i-- {
+		save(t, s, newTask(fmt.Sprintf("00000000-0000-0000-0000-%012d", i), models.TaskStatusPending, 0, 4*time.Second))
+	}
+	for offset := 3; offset < 9; offset += 2 {
+		page, err := s.ListTasks(storage.TaskFilter{Offset: offset, Limit: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, task := range page {
+			if want := fmt.Sprintf("00000000-0000-0000-0000-%012d", offset+i+1); task.ID != want {
+				t.Fatalf("offset %d: page[%d] = %s, want %s", offset, i, task.ID, want)
+			}
+		}
+	}
+}
+
+func testListFilter(t *testing.T, s storage.JobStore) {
+	for i := 0; i < 6; i++ {
+		task := newTask(fmt.Sprintf("00000000-0000-0000-0000-%012d", i+1), models.TaskStatusQueued, 0, time.Duration(i)*time.Second)
+		if i%2 == 1 {
+			task.Status = models.TaskStatusSucceeded
+			task.WorkerID = "w1"
+		}
+		if i >= 4 {
+			task.Type = "sleep"
+		}
+		if i%3 == 0 {
+			task.Tenant = "web"
+		}
+		save(t, s, task)
+	}
+
+	cases := []struct {
+		name   string
+		filter storage.TaskFilter
+		want   []int
+	}{
+		{"status", storage.TaskFilter{Statuses: []models.TaskStatus{models.TaskStatusSucceeded}}, []int{2, 4, 6}},
+		{"statuses", storage.TaskFilter{Statuses: []models.TaskStatus{models.TaskStatusQueued, models.TaskStatusSucceeded}}, []int{1, 2, 3, 4, 5, 6}},
+		{"type", storage.TaskFilter{Type: "sleep"}, []int{5, 6}},
+		{"worker", storage.TaskFilter{WorkerID: "w1", Type: "echo"}, []int{2, 4}},
+		{"tenant", storage.TaskFilter{Tenant: "web"}, []int{1, 4}},
+		{"limit", storage.TaskFilter{Limit: 2}, []int{1, 2}},
+		{"offset", storage.TaskFilter{Offset: 2, Limit: 3}, []int{3, 4, 5}},
+		{"last page", storage.TaskFilter{Statuses: []models.TaskStatus{models.TaskStatusQueued}, Offset: 2, Limit: 2}, []int{5}},
+		{"past the end", storage.TaskFilter{Offset: 6}, nil},
+		{"created since", storage.TaskFilter{CreatedSince: epoch.Add(4 * time.Second)}, []int{5, 6}},
+	}
+	for _, c := range cases {
+		tasks, err := s.ListTasks(c.filter)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		var got []int
+		for _, task := range tasks {
+			var n int
+			fmt.Sscanf(task.ID[len(task.ID)-12:], "%d", &n)
+			got = append(got, n)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(c.want) {
+			t.Errorf("%s: got tasks %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func testClaimOrder(t *testing.T, s storage.JobStore) {
+	save(t, s,
+		newTask("00000000-0000-0000-0000-000000000001", models.TaskStatusQueued, 0, time.Second),
+		newTask("00000000-0000-0000-0000-000000000002", models.TaskStatusQueued, 5, 3*time.Second),
+		newTask("00000000-0000-0000-0000-000000000003", models.TaskStatusQueued, 5, 2*time.Second),
+		newTask("00000000-0000-0000-0000-000000000004", models.TaskStatusQueued, -1, 0),
+		newTask("00000000-0000-0000-0000-000000000005", models.TaskStatusPending, 9, 0),
+	)
+	want := []string{
+		"00000000-0000-0000-0000-000000000003",
+		"00000000-0000-0000-0000-000000000002",
+		"00000000-0000-0000-0000-000000000001",
+		"00000000-0000-0000-0000-000000000004",
+	}
+	until := epoch.Add(time.Minute)
+	for _, id := range want {
+		task, err := claim(s, "w1", until)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if task == nil || task.ID != id {
+			t.Fatalf("claimed %v, want %s", task, id)
+		}
+		if task.Status != models.TaskStatusLeased || task.Attempts != 1 || task.Version != 2 {
+			t.Fatalf("claimed task = %s attempts %d version %d", task.Status, task.Attempts, task.Version)
+		}
+		stored, _ := s.GetTask(id)
+		if stored.Status != models.TaskStatusLeased || stored.WorkerID != "w1" {
+			t.Fatalf("claim not persisted: %s by %q", stored.Status, stored.WorkerID)
+		}
+	}
+	if task, err := claim(s, "w1", until); err != nil || task != nil {
+		t.Fatalf("claim on empty queue = %v, %v", task, err)
+	}
+}
+
+func testClaimFilter(t *testing.T, s storage.JobStore) {
+	build := newTask("00000000-0000-0000-0000-000000000001", models.TaskStatusQueued, 5, 0)
+	build.Type = "build"
+	build.Queue = "slow"
+	build.Tenant = "ci"
+	echo := newTask("00000000-0000-0000-0000-000000000002", models.TaskStatusQueued, 0, time.Second)
+	echo.Tenant = "default"
+	web := newTask("00000000-0000-0000-0000-000000000003", models.TaskStatusQueued, -1, 2*time.Second)
+	web.Tenant = "web"
+	save(t, s, build, echo, web)
+	if got, err := s.QueuedTenants(); err != nil || fmt.Sprint(got) != "map[ci:1 default:1 web:1]" {
+		t.Fatalf("queued tenants = %v, %v", got, err)
+	}
+	until := epoch.Add(time.Minute)
+	for _, c := range []struct {
+		name   string
+		filter storage.ClaimFilter
+		want   string
+	}{
+		{"none allowed", storage.ClaimFilter{Types: []string{}}, ""},
+		{"other type", storage.ClaimFilter{Types: []string{"deploy"}}, ""},
+		{"excluded type", storage.ClaimFilter{ExcludeTypes: []string{"build", "echo"}}, ""},
+		{"excluded queue", storage.ClaimFilter{Types: []string{"build"}, ExcludeQueues: []string{"slow"}}, ""},
+		{"no tenants", storage.ClaimFilter{Tenants: []string{}}, ""},
+		{"other tenant", storage.ClaimFilter{Types: []string{"build"}, Tenants: []string{"web"}}, ""},
+		{"lower priority type", storage.ClaimFilter{Types: []string{"echo", "deploy"}}, "00000000-0000-0000-0000-000000000002"},
+		{"any type", storage.ClaimFilter{}, "00000000-0000-0000-0000-000000000001"},
+		{"tenant", storage.ClaimFilter{Tenants: []string{"ci", "web"}}, "00000000-0000-0000-0000-000000000003"},
+	} {
+		task, err := claimFiltered(s, c.filter, "w1", until)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		var got string
+		if task != nil {
+			got = task.ID
+		}
+		if got != c.want {
+			t.Fatalf("%s: claimed %q, want %q", c.name, got, c.want)
+		}
+	}
+	if got, err := s.QueuedTenants(); err != nil || len(got) != 0 {
+		t.Fatalf("queued tenants after claiming all = %v, %v", got, err)
+	}
+	if got, err := s.HeldTenants(); err != nil || fmt.Sprint(got) != "map[ci:1 default:1 web:1]" {
+		t.Fatalf("held tenants after claiming all = %v, %v", got, err)
+	}
+}
+
+func testPeekAndPick(t *testing.T, s storage.JobStore) {
+	held := newTask("00000000-0000-0000-0000-000000000006", models.TaskStatusLeased, 0, 0)
+	held.ConcurrencyKey = "customer-1"
+	waiting := newTask("00000000-0000-0000-0000-000000000007", models.TaskStatusQueued, 9, 0)
+	waiting.ConcurrencyKey = "customer-1"
+	save(t, s,
+		newTask("00000000-0000-0000-0000-000000000001", models.TaskStatusQueued, 0, time.Second),
+		newTask("00000000-0000-0000-0000-000000000002", models.TaskStatusQueued, 5, 3*time.Second),
+		newTask("00000000-0000-0000-0000-000000000003", models.TaskStatusQueued, 5, 2*time.Second),
+		newTask("00000000-0000-0000-0000-000000000004", models.TaskStatusQueued, -1, 0),
+		newTask("00000000-0000-0000-0000-000000000005", models.TaskStatusPending, 9, 0),
+		held,
+		waiting,
+	)
+	ids := func(tasks []*models.Task) string {
+		var got []string
+		for _, task := range tasks {
+			got = append(got, task.ID[len(task.ID)-1:])
+		}
+		return strings.Join(got, ",")
+	}
+	for _, c := range []struct {
+		name   string
+		filter storage.ClaimFilter
+		limit  int
+		want   string
+	}{
+		{"by priority", storage.ClaimFilter{}, 3, "3,2,1"},
+		{"by age", storage.ClaimFilter{Order: storage.ByAge}, 10, "4,1,3,2"},
+		{"by age limited", storage.ClaimFilter{Order: storage.ByAge}, 1, "4"},
+		{"ids", storage.ClaimFilter{IDs: []string{"00000000-0000-0000-0000-000000000001", "00000000-0000-0000-0000-000000000005"}}, 10, "1"},
+		{"no ids", storage.ClaimFilter{IDs: []string{}}, 10, ""},
+	} {
+		tasks, err := s.PeekTasks(c.filter, c.limit)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if got := ids(tasks); got != c.want {
+			t.Errorf("%s: peeked %q, want %q", c.name, got, c.want)
+		}
+	}
+	if stored, _ := s.GetTask("00000000-0000-0000-0000-000000000003"); stored.Status != models.TaskStatusQueued {
+		t.Fatalf("peeked task is %s", stored.Status)
+	}
+
+	until := epoch.Add(time.Minute)
+	for _, c := range []struct {
+		name   string
+		filter storage.ClaimFilter
+		want   string
+	}{
+		{"by age", storage.ClaimFilter{Order: storage.ByAge}, "00000000-0000-0000-0000-000000000004"},
+		{"picked", storage.ClaimFilter{IDs: []string{"00000000-0000-0000-0000-000000000001"}}, "00000000-0000-0000-0000-000000000001"},
+		{"picked twice", storage.ClaimFilter{IDs: []string{"00000000-0000-0000-0000-000000000001"}}, ""},
+		{"picked key held", storage.ClaimFilter{IDs: []string{"00000000-0000-0000-0000-000000000007"}}, ""},
+	} {
+		task, err := claimFiltered(s, c.filter, "w1", until)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		var got string
+		if task != nil {
+			got = task.ID
+		}
+		if got != c.want {
+			t.Fatalf("%s: claimed %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func testConcurrencyKeys(t *testing.T, s storage.JobStore) {
+	keyed := func(task *models.Task, key string, limit int) *models.Task {
+		task.ConcurrencyKey = key
+		task.ConcurrencyLimit = limit
+		return task
+	}
+	holder := keyed(newTask("00000000-0000-0000-0000-000000000001", models.TaskStatusLeased, 0, 0), "customer-1", 0)
+	save(t, s,
+		holder,
+		keyed(newTask("00000000-0000-0000-0000-000000000002", models.TaskStatusQueued, 5, time.Second), "customer-1", 0),
+		keyed(newTask("00000000-0000-0000-0000-000000000003", models.TaskStatusQueued, 3, time.Second), "customer-1", 2),
+		newTask("00000000-0000-0000-0000-000000000004", models.TaskStatusQueued, 0, time.Second),
+	)
+	until := epoch.Add(time.Minute)
+	expect := func(want string) *models.Task {
+		t.Helper()
+		task, err := claim(s, "w1", until)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got string
+		if task != nil {
+			got = task.ID
+		}
+		if got != want {
+			t.Fatalf("claimed %q, want %q", got, want)
+		}
+		return task
+	}
+	// The key is held once: only the job that allows two holders may start.
+	second := expect("00000000-0000-0000-0000-000000000003")
+	expect("00000000-0000-0000-0000-000000000004")
+	expect("")
+
+	// Finishing both holders releases the key.
+	for _, task := range []*models.Task{holder, second} {
+		task.Status = models.TaskStatusSucceeded
+		if err := s.UpdateTask(task); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expect("00000000-0000-0000-0000-000000000002")
+}
+
+func testClaimLimits(t *testing.T, s storage.JobStore) {
+	typed := func(task *models.Task, typ, queue string) *models.Task {
+		task.Type = typ
+		task.Queue = queue
+		return task
+	}
+	save(t, s,
+		typed(newTask("00000000-0000-0000-0000-000000000001", models.TaskStatusRunning, 0, 0), "build", "ci"),
+		typed(newTask("00000000-0000-0000-0000-000000000002", models.TaskStatusQueued, 5, time.Second), "build", "ci"),
+		typed(newTask("00000000-0000-0000-0000-000000000003", models.TaskStatusQueued, 3, time.Second), "echo", "ci"),
+		typed(newTask("00000000-0000-0000-0000-000000000004", models.TaskStatusQueued, 1, time.Second), "echo", "default"),
+		typed(newTask("00000000-0000-0000-0000-000000000005", models.TaskStatusSucceeded, 0, 0), "echo", "default"),
+	)
+	byType, byQueue, err := s.HeldCounts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(byType) != 1 || byType["build"] != 1 || len(byQueue) != 1 || byQueue["ci"] != 1 {
+		t.Fatalf("held counts = %v, %v, want build and ci held once", byType, byQueue)
+	}
+
+	filter := storage.ClaimFilter{
+		TypeLimits:  map[string]int{"build": 1},
+		QueueLimits: map[string]int{"ci": 2},
+	}
+	until := epoch.Add(time.Minute)
+	expect := func(want string) {
+		t.Helper()
+		task, err := claimFiltered(s, filter, "w1", until)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got string
+		if task != nil {
+			got = task.ID
+		}
+		if got != want {
+			t.Fatalf("claimed %q, want %q", got, want)
+		}
+	}
+	// The build type is at its cap, and taking the echo job fills the ci
+	// queue, so only the job in the default queue is left to claim.
+	expect("00000000-0000-0000-0000-000000000003")
+	expect("00000000-0000-0000-0000-000000000004")
+	expect("")
+
+	byType, byQueue, err = s.HeldCounts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if byType["build"] != 1 || byType["echo"] != 2 || byQueue["ci"] != 2 || byQueue["default"] != 1 {
+		t.Fatalf("held counts = %v, %v after claiming", byType, byQueue)
+	}
+}
+
+func testClaimRates(t *testing.T, s storage.JobStore) {
+	typed := func(task *models.Task, typ, queue string) *models.Task {
+		task.Type = typ
+		task.Queue = queue
+		return task
+	}
+	save(t, s,
+		typed(newTask("00000000-0000-0000-0000-000000000001", models.TaskStatusQueued, 5, 0), "echo", "ci"),
+		typed(newTask("00000000-0000-0000-0000-000000000002", models.TaskStatusQueued, 4, 0), "echo", "ci"),
+		typed(newTask("00000000-0000-0000-0000-000000000003", models.TaskStatusQueued, 3, 0), "build", "ci"),
+		typed(newTask("00000000-0000-0000-0000-000000000004", models.TaskStatusQueued, 2, 0), "build", "ci"),
+	)
+	// One echo start per thousand seconds, and two starts at once in ci.
+	filter := storage.ClaimFilter{
+		TypeRates:  map[string]storage.StartRate{"echo": {Rate: 0.001, Burst: 1}},
+		QueueRates: map[string]storage.StartRate{"ci": {Rate: 0.001, Burst: 2}},
+	}
+	until := epoch.Add(time.Minute)
+	expect := func(want string) {
+		t.Helper()
+		task, err := claimFiltered(s, filter, "w1", until)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got string
+		if task != nil {
+			got = task.ID
+		}
+		if got != want {
+			t.Fatalf("claimed %q, want %q", got, want)
+		}
+	}
+	expect("00000000-0000-0000-0000-000000000001")
+	expect("00000000-0000-0000-0000-000000000003")
+	expect("")
+
+	now := time.Now()
+	byType, byQueue, err := s.LimitStarts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tokens := filter.TypeRates["echo"].Tokens(byType["echo"], now); tokens >= 1 {
+		t.Fatalf("echo has %v starts left", tokens)
+	}
+	if tokens := filter.QueueRates["ci"].Tokens(byQueue["ci"], now); tokens >= 1 {
+		t.Fatalf("ci has %v starts left", tokens)
+	}
+	if _, ok := byType["build"]; ok {
+		t.Fatal("build has starts without a rate")
+	}
+
+	// The counted starts only hold back claims under the same rates.
+	free, err := claim(s, "w1", until)
+	if err != nil || free == nil || free.ID != "00000000-0000-0000-0000-000000000002" {
+		t.Fatalf("claim without rates = %v, %v", free, err)
+	}
+}
+
+func testClaimAbort(t *testing.T, s storage.JobStore) {
+	save(t, s, newTask("00000000-0000-0000-0000-000000000001", models.TaskStatusQueued, 0, 0))
+	boom := errors.New("boom")
+	if _, err := s.ClaimTask(storage.ClaimFilter{}, func(*models.Task) error { return boom }); !errors.Is(err, boom) {
+		t.Fatalf("err = %v, want the apply error", err)
+	}
+	stored, _ := s.GetTask("00000000-0000-0000-0000-000000000001")
+	if stored.Status != models.TaskStatusQueued || stored.Version != 1 {
+		t.Fatalf("aborted claim changed the task: %s version %d", stored.Status, stored.Version)
+	}
+}
+
+func testConcurrentClaims(t *testing.T, s storage.JobStore) {
+	const tasks, workers = 20, 5
+	for i := 0; i < tasks; i++ {
+		save(t, s, newTask(fmt.Sprintf("00000000-0000-0000-0000-%012d", i+1), models.TaskStatusQueued, 0, time.Duration(i)))
+	}
+
+	var (
+		mu      sync.Mutex
+		claimed = map[string]string{}
+		wg      sync.WaitGroup
+	)
+	for w := 0; w < workers; w++ {
+		workerID := fmt.Sprintf("w%d", w)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				task, err := claim(s, workerID, epoch.Add(time.Minute))
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if task == nil {
+					return
+				}
+				mu.Lock()
+				if other, ok := claimed[task.ID]; ok {
+					t.Errorf("%s claimed by %s and %s", task.ID, other, workerID)
+				}
+				claimed[task.ID] = workerID
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if len(claimed) != tasks {
+		t.Fatalf("claimed %d tasks, want %d", len(claimed), tasks)
+	}
+}
+
+func testUniqueTasks(t *testing.T, s storage.JobStore) {
+	const submitters = 10
+	dup := storage.TaskFilter{
+		Statuses:  []models.TaskStatus{models.TaskStatusPending, models.TaskStatusQueued},
+		Type:      "echo",
+		UniqueKey: "nightly",
+	}
+	var (
+		mu      sync.Mutex
+		created []string
+		seen    = map[string]bool{}
+		wg      sync.WaitGroup
+	)
+	for i := 0; i < submitters; i++ {
+		task := newTask(fmt.Sprintf("00000000-0000-0000-0000-%012d", i+1), models.TaskStatusPending, 0, time.Duration(i))
+		task.UniqueKey = "nightly"
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			existing, err := s.SaveUniqueTask(task, dup)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if existing == nil {
+				created = append(created, task.ID)
+			} else {
+				seen[existing.ID] = true
+			}
+		}()
+	}
+	wg.Wait()
+	if len(created) != 1 {
+		t.Fatalf("created %v, want exactly one task", created)
+	}
+	for id := range seen {
+		if id != created[0] {
+			t.Fatalf("duplicate answered with %s, want %s", id, created[0])
+		}
+	}
+
+	// Once the task no longer matches, the key is free again.
+	first, _ := s.GetTask(created[0])
+	first.Status = models.TaskStatusSucceeded
+	if err := s.UpdateTask(first); err != nil {
+		t.Fatal(err)
+	}
+	next := newTask("00000000-0000-0000-0000-000000000100", models.TaskStatusPending, 0, time.Minute)
+	next.UniqueKey = "nightly"
+	if existing, err := s.SaveUniqueTask(next, dup); err != nil || existing != nil {
+		t.Fatalf("SaveUniqueTask after the duplicate finished = %v, %v", existing, err)
+	}
+	if _, err := s.GetTask(next.ID); err != nil {
+		t.Fatalf("unique task not saved: %v", err)
+	}
+}
+
+func testCachedTasks(t *testing.T, s storage.JobStore) {
+	succeeded := func(id, key string, completed time.Duration) *models.Task {
+		task := newTask(id, models.TaskStatusSucceeded, 0, 0)
+		task.CacheKey = key
+		at := epoch.Add(completed)
+		task.CompletedAt = &at
+		return task
+	}
+	older := succeeded("00000000-0000-0000-0000-000000000001", "k1", time.Minute)
+	newer := succeeded("00000000-0000-0000-0000-000000000002", "k1", 2*time.Minute)
+	reused := succeeded("00000000-0000-0000-0000-000000000003", "k1", 3*time.Minute)
+	reused.CachedFrom = newer.ID
+	queued := newTask("00000000-0000-0000-0000-000000000004", models.TaskStatusQueued, 0, 0)
+	queued.CacheKey = "k1"
+	other := succeeded("00000000-0000-0000-0000-000000000005", "k2", 4*time.Minute)
+	save(t, s, older, newer, reused, queued, other)
+
+	want := func(key, id string) {
+		t.Helper()
+		got, err := s.CachedTask(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var gotID string
+		if got != nil {
+			gotID = got.ID
+		}
+		if gotID != id {
+			t.Fatalf("CachedTask(%s) = %q, want %q", key, gotID, id)
+		}
+	}
+	want("k1", newer.ID)
+	want("k2", other.ID)
+	want("k3", "")
+
+	// Dropping the key of the newest task falls back to the older one.
+	stored, _ := s.GetTask(newer.ID)
+	stored.CacheKey = ""
+	if err := s.UpdateTask(stored); err != nil {
+		t.Fatal(err)
+	}
+	want("k1", older.ID)
+
+	// A task that succeeds later takes over.
+	stored, _ = s.GetTask(queued.ID)
+	stored.Status = models.TaskStatusSucceeded
+	at := epoch.Add(5 * time.Minute)
+	stored.CompletedAt = &at
+	if err := s.UpdateTask(stored); err != nil {
+		t.Fatal(err)
+	}
+	want("k1", queued.ID)
+}
+
+func testLeases(t *testing.T, s storage.JobStore) {
+	save(t, s,
+		newTask("00000000-0000-0000-0000-000000000001", models.TaskStatusQueued, 0, 0),
+		newTask("00000000-0000-0000-0000-000000000002", models.TaskStatusQueued, 0, time.Second),
+	)
+	first, _ := claim(s, "w1", epoch.Add(time.Minute))
+	second, _ := claim(s, "w2", epoch.Add(time.Minute))
+
+	expired, err := s.ExpiredLeases(epoch.Add(30 * time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(expired) != 0 {
+		t.Fatalf("%d leases expired early", len(expired))
+	}
+
+	if err := s.RenewLeases("w1", epoch.Add(5*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	renewed, _ := s.GetTask(first.ID)
+	if !renewed.LeaseExpiresAt.Equal(epoch.Add(5*time.Minute)) || renewed.Version != first.Version+1 {
+		t.Fatalf("renewed lease = %v version %d", renewed.LeaseExpiresAt, renewed.Version)
+	}
+
+	expired, err = s.ExpiredLeases(epoch.Add(2 * time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(expired) != 1 || expired[0].ID != second.ID {
+		t.Fatalf("expired = %v, want only %s", expired, second.ID)
+	}
+
+	// Running tasks keep their lease; terminal ones drop out of the check.
+	renewed.Status = models.TaskStatusRunning
+	if err := s.UpdateTask(renewed); err != nil {
+		t.Fatal(err)
+	}
+	stale, _ := s.GetTask(second.ID)
+	stale.Status = models.TaskStatusCancelled
+	stale.LeaseExpiresAt = nil
+	if err := s.UpdateTask(stale); err != nil {
+		t.Fatal(err)
+	}
+	expired, err = s.ExpiredLeases(epoch.Add(10 * time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(expired) != 1 || expired[0].ID != first.ID {
+		t.Fatalf("expired = %v, want only %s", expired, first.ID)
+	}
+}
+
+func testWorkers(t *testing.T, s storage.JobStore) {
+	w := &models.Worker{
+		ID:       "w1",
+		Host:     "host-a",
+		LastSeen: epoch,
+		Tasks:    []models.TaskCapability{{Task: "echo", Version: "1.2.0"}, {Task: "build"}},
+		Capacity: models.Resources{CPU: 4, MemoryMB: 8192},
+	}
+	if err := s.SaveWorker(w); err != nil {
+		t.Fatal(err)
+	}
+	w.LastSeen = epoch.Add(time.Minute)
+	if err := s.SaveWorker(w); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveWorker(&models.Worker{ID: "w2", Host: "host-b", LastSeen: epoch, AnyTask: true}); err != nil {
+		t.Fatal(err)
+	}
+	workers, err := s.ListWorkers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(workers) != 2 {
+		t.Fatalf("listed %d workers, want 2", len(workers))
+	}
+	for _, got := range workers {
+		switch got.ID {
+		case "w1":
+			if !got.LastSeen.Equal(epoch.Add(time.Minute)) {
+				t.Fatalf("w1 last seen = %v", got.LastSeen)
+			}
+			if fmt.Sprint(got.Tasks) != "[{echo 1.2.0} {build }]" || got.AnyTask || !got.Runs("build", "") || got.Runs("deploy", "") {
+				t.Fatalf("w1 capabilities = %v any %v", got.Tasks, got.AnyTask)
+			}
+			if got.Capacity != w.Capacity {
+				t.Fatalf("w1 capacity = %+v", got.Capacity)
+			}
+		case "w2":
+			if !got.AnyTask || !got.Runs("deploy", "") {
+				t.Fatalf("w2 capabilities = %v any %v", got.Tasks, got.AnyTask)
+			}
+		}
+	}
+
+	// A heartbeat only moves LastSeen, keeping a registration saved since.
+	if err := s.SaveWorker(&models.Worker{ID: "w2", Host: "host-c", LastSeen: epoch, Tasks: []models.TaskCapability{{Task: "build"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.TouchWorker("w2", epoch.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.GetWorker("w2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.LastSeen.Equal(epoch.Add(time.Hour)) || got.Host != "host-c" || got.AnyTask || fmt.Sprint(got.Tasks) != "[{build }]" {
+		t.Fatalf("touched w2 = %+v", got)
+	}
+	if _, err := s.GetWorker("w3"); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("get unknown worker: err = %v, want ErrNotFound", err)
+	}
+	if err := s.TouchWorker("w3", epoch); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("touch unknown worker: err = %v, want ErrNotFound", err)
+	}
+}
+
+func testLogs(t *testing.T, s storage.JobStore) {
+	const id = "00000000-0000-0000-0000-000000000001"
+	save(t, s, newTask(id, models.TaskStatusRunning, 0, 0))
+
+	if lines, err := s.JobLogs(id, 0, 0); err != nil || len(lines) != 0 {
+		t.Fatalf("empty log = %v, %v", lines, err)
+	}
+	for batch := 0; batch < 3; batch++ {
+		lines := []models.LogLine{
+			{At: epoch.Add(time.Duration(batch) * time.Second), Message: fmt.Sprintf("batch %d line 1", batch)},
+			{At: epoch.Add(time.Duration(batch) * time.Second), Message: fmt.Sprintf("batch %d line 2", batch)},
+		}
+		added, err := s.AppendLogs(id, lines)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := int64(batch*2 + 2); len(added) != 2 || added[1].Seq != want {
+			t.Fatalf("appended %+v, want seq %d last", added, want)
+		}
+	}
+
+	all, err := s.JobLogs(id, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 6 {
+		t.Fatalf("read %d lines, want 6", len(all))
+	}
+	for i, line := range all {
+		if line.Seq != int64(i+1) {
+			t.Fatalf("lines[%d].Seq = %d", i, line.Seq)
+		}
+	}
+	if all[2].Message != "batch 1 line 1" || !all[2].At.Equal(epoch.Add(time.Second)) {
+		t.Fatalf("lines[2] = %+v", all[2])
+	}
+
+	page, err := s.JobLogs(id, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 3 || page[0].Seq != 3 || page[2].Seq != 5 {
+		t.Fatalf("page after 2 = %+v", page)
+	}
+	if rest, err := s.JobLogs(id, 6, 0); err != nil || len(rest) != 0 {
+		t.Fatalf("read past the end = %v, %v", rest, err)
+	}
+	if other, err := s.JobLogs("00000000-0000-0000-0000-000000000002", 0, 0); err != nil || len(other) != 0 {
+		t.Fatalf("unrelated job's log = %v, %v", other, err)
+	}
+
+	// Trimming keeps the numbering, even once the whole log is gone.
+	if err := s.DeleteLogs(id, 4); err != nil {
+		t.Fatal(err)
+	}
+	if rest, err := s.JobLogs(id, 0, 0); err != nil || len(rest) != 2 || rest[0].Seq != 5 {
+		t.Fatalf("after trimming through 4 = %+v, %v", rest, err)
+	}
+	if err := s.DeleteLogs(id, math.MaxInt64); err != nil {
+		t.Fatal(err)
+	}
+	if rest, err := s.JobLogs(id, 0, 0); err != nil || len(rest) != 0 {
+		t.Fatalf("after deleting everything = %+v, %v", rest, err)
+	}
+	more, err := s.AppendLogs(id, []models.LogLine{{At: epoch, Message: "after trim"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(more) != 1 || more[0].Seq != 7 {
+		t.Fatalf("appended after deleting the log = %+v, want seq 7", more)
+	}
+}
+
+func testShippedLogs(t *testing.T, s storage.JobStore) {
+	const id = "00000000-0000-0000-0000-000000000001"
+	save(t, s, newTask(id, models.TaskStatusRunning, 0, 0))
+	shipped := func(attempt int, seq int64) models.LogLine {
+		return models.LogLine{At: epoch, Message: fmt.Sprintf("attempt %d line %d", attempt, seq), Attempt: attempt, ShipSeq: seq}
+	}
+	steps := []struct {
+		name  string
+		lines []models.LogLine
+		want  []string
+	}{
+		{"first batch", []models.LogLine{shipped(1, 1), shipped(1, 2), shipped(1, 3)},
+			[]string{"attempt 1 line 1", "attempt 1 line 2", "attempt 1 line 3"}},
+		{"resent with more", []models.LogLine{shipped(1, 2), shipped(1, 3), shipped(1, 4), {At: epoch, Message: "unshipped"}},
+			[]string{"attempt 1 line 4", "unshipped"}},
+		{"next attempt", []models.LogLine{shipped(2, 1), shipped(2, 2), shipped(2, 2)},
+			[]string{"attempt 2 line 1", "attempt 2 line 2"}},
+		{"resent entirely", []models.LogLine{shipped(1, 4), shipped(2, 2)}, nil},
+	}
+	for _, step := range steps {
+		added, err := s.AppendLogs(id, step.lines)
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		var got []string
+		for _, l := range added {
+			got = append(got, l.Message)
+		}
+		if strings.Join(got, "|") != strings.Join(step.want, "|") {
+			t.Fatalf("%s: appended %q, want %q", step.name, got, step.want)
+		}
+	}
+
+	all, err := s.JobLogs(id, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 7 || all[6].Seq != 7 || all[6].Message != "attempt 2 line 2" {
+		t.Fatalf("log = %+v", all)
+	}
+	for _, l := range all {
+		if l.Attempt != 0 || l.ShipSeq != 0 {
+			t.Fatalf("stored line kept its shipping position: %+v", l)
+		}
+	}
+
+	// Deleting the log does not forget what was shipped.
+	if err := s.DeleteLogs(id, math.MaxInt64); err != nil {
+		t.Fatal(err)
+	}
+	if added, err := s.AppendLogs(id, []models.LogLine{shipped(1, 4)}); err != nil || len(added) != 0 {
+		t.Fatalf("resent after deleting the log: appended %+v, %v", added, err)
+	}
+}
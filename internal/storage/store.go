@@ -1,23 +1,503 @@
 package storage
 
-import "github.com/dheeraj-sn/distributed-orchestrator/internal/models"
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/models"
+)
+
+var (
+	// ErrNotFound is returned when a task or worker does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrConflict is returned by UpdateTask when the stored task has been
+	// modified since it was read.
+	ErrConflict = errors.New("task was modified concurrently")
+)
 
 // Store defines the interface for task storage
 type Store interface {
 	SaveTask(task *models.Task) error
 	GetTask(id string) (*models.Task, error)
 	UpdateTask(task *models.Task) error
-	ListTasks() ([]*models.Task, error)
+	ListTasks(filter TaskFilter) ([]*models.Task, error)
+}
+
+// JobStore is the scheduler's persistence layer: tasks plus worker
+// registrations. UpdateTask only succeeds if task.Version matches the stored
+// version, and bumps it on success.
+type JobStore interface {
+	Store
+
+	// ClaimTask atomically picks the next queued task the filter allows, in
+	// the filter's order, applies the caller's changes to it and persists
+	// them. Concurrent callers, including other
+	// scheduler replicas, never receive the same task. A task is skipped
+	// while as many tasks with its concurrency key as its limit allows, or
+	// as the filter's cap on its type or queue, are leased or running, and
+	// while the filter's rate on its type or queue allows no start,
+	// counting claims made at the same time by other replicas. It returns
+	// nil if no such task is queued.
+	ClaimTask(filter ClaimFilter, apply func(task *models.Task) error) (*models.Task, error)
+	// HeldCounts returns the number of leased and running tasks of each
+	// task type and in each queue that has any.
+	HeldCounts() (byType, byQueue map[string]int, err error)
+	// LimitStarts returns, for each task type and queue that ClaimTask
+	// counted starts of under a StartRate, when those starts are paid off.
+	LimitStarts() (byType, byQueue map[string]time.Time, err error)
+	// PeekTasks returns up to limit of the queued tasks ClaimTask could
+	// pick under the filter, in the order it would pick them, without
+	// claiming any.
+	PeekTasks(filter ClaimFilter, limit int) ([]*models.Task, error)
+	// SaveUniqueTask inserts task unless a task matching dup exists, in
+	// which case it returns the oldest such task and stores nothing. Calls
+	// for the same task type and UniqueKey are serialized, including across
+	// scheduler replicas.
+	SaveUniqueTask(task *models.Task, dup TaskFilter) (*models.Task, error)
+	// CachedTask returns the most recently completed succeeded task with
+	// the cache key that did not itself reuse a result, or nil if there is
+	// none. It is answered from an index on the key.
+	CachedTask(key string) (*models.Task, error)
+	// QueuedTenants returns the number of queued tasks of each tenant that
+	// has any.
+	QueuedTenants() (map[string]int, error)
+	// HeldTenants returns the number of leased and running tasks of each
+	// tenant that has any.
+	HeldTenants() (map[string]int, error)
+	// RenewLeases extends the lease of every task held by a worker.
+	RenewLeases(workerID string, until time.Time) error
+	// ExpiredLeases returns held tasks whose lease ran out before now.
+	ExpiredLeases(now time.Time) ([]*models.Task, error)
+
+	SaveWorker(worker *models.Worker) error
+	// GetWorker returns a worker's registration, or ErrNotFound.
+	GetWorker(id string) (*models.Worker, error)
+	// TouchWorker sets only a worker's LastSeen, leaving the rest of its
+	// registration as the store has it, or returns ErrNotFound.
+	TouchWorker(id string, seen time.Time) error
+	ListWorkers() ([]*models.Worker, error)
+
+	// AppendLogs adds lines to the end of a job's log, setting their Seq,
+	// and returns the lines it added. Shipped lines at or below the highest
+	// ShipSeq already stored for their job attempt belong to a resent
+	// batch and are left out; that highest ShipSeq is kept in the same
+	// transaction as the lines.
+	AppendLogs(jobID string, lines []models.LogLine) ([]models.LogLine, error)
+	// JobLogs returns up to limit lines with Seq greater than after, oldest
+	// first; limit 0 means no limit.
+	JobLogs(jobID string, after int64, limit int) ([]models.LogLine, error)
+	// DeleteLogs removes a job's lines with Seq up to and including
+	// through. Later appends keep counting from the last assigned Seq.
+	DeleteLogs(jobID string, through int64) error
+
+	Close() error
+}
+
+// HealthChecker is implemented by stores that depend on an external
+// service, so the scheduler can report whether it is reachable.
+type HealthChecker interface {
+	Ping(ctx context.Context) error
 }
 
 // MemoryStore implements Store interface with in-memory storage
 type MemoryStore struct {
-	tasks map[string]*models.Task
+	mu      sync.RWMutex
+	tasks   map[string]*models.Task
+	queued  map[string]struct{}            // IDs of queued tasks
+	held    map[string]struct{}            // IDs of leased and running tasks
+	cached  map[string]map[string]struct{} // cache key -> IDs of tasks CachedTask may return
+	workers map[string]*models.Worker
+	logs    map[string][]models.LogLine
+	logSeq  map[string]int64         // last Seq assigned per job
+	shipped map[string]map[int]int64 // highest ShipSeq stored per job attempt
+	starts  starts
 }
 
 // NewMemoryStore creates a new in-memory store
 func NewMemoryStore() *MemoryStore {
 	return &MemoryStore{
-		tasks: make(map[string]*models.Task),
+		tasks:   make(map[string]*models.Task),
+		queued:  make(map[string]struct{}),
+		held:    make(map[string]struct{}),
+		cached:  make(map[string]map[string]struct{}),
+		workers: make(map[string]*models.Worker),
+		logs:    make(map[string][]models.LogLine),
+		logSeq:  make(map[string]int64),
+		shipped: make(map[string]map[int]int64),
+		starts:  newStarts(),
+	}
+}
+
+// put stores a copy of task and keeps the status indexes in sync.
+func (s *MemoryStore) put(task *models.Task) {
+	if prev, ok := s.tasks[task.ID]; ok && cacheResult(prev) {
+		delete(s.cached[prev.CacheKey], prev.ID)
+		if len(s.cached[prev.CacheKey]) == 0 {
+			delete(s.cached, prev.CacheKey)
+		}
+	}
+	s.tasks[task.ID] = task.Clone()
+	delete(s.queued, task.ID)
+	delete(s.held, task.ID)
+	if cacheResult(task) {
+		if s.cached[task.CacheKey] == nil {
+			s.cached[task.CacheKey] = make(map[string]struct{})
+		}
+		s.cached[task.CacheKey][task.ID] = struct{}{}
+	}
+	switch task.Status {
+	case models.TaskStatusQueued:
+		s.queued[task.ID] = struct{}{}
+	case models.TaskStatusLeased, models.TaskStatusRunning:
+		s.held[task.ID] = struct{}{}
+	}
+}
+
+// Restore stores task as-is, keeping its version. It is used to rebuild
+// the store from a snapshot or log rather than for new tasks.
+func (s *MemoryStore) Restore(task *models.Task) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.put(task)
+}
+
+// SaveTask inserts a new task.
+func (s *MemoryStore) SaveTask(task *models.Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.insert(task)
+}
+
+func (s *MemoryStore) insert(task *models.Task) error {
+	if _, ok := s.tasks[task.ID]; ok {
+		return errors.New("task already exists: " + task.ID)
+	}
+	task.Version = 1
+	s.put(task)
+	return nil
+}
+
+// SaveUniqueTask checks for duplicates and inserts under the store lock.
+func (s *MemoryStore) SaveUniqueTask(task *models.Task, dup TaskFilter) (*models.Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var existing *models.Task
+	for _, t := range s.tasks {
+		if dup.Match(t) && (existing == nil || t.CreatedAt.Before(existing.CreatedAt)) {
+			existing = t
+		}
+	}
+	if existing != nil {
+		return existing.Clone(), nil
 	}
+	return nil, s.insert(task)
+}
+
+// CachedTask returns a copy of the newest task indexed under key.
+func (s *MemoryStore) CachedTask(key string) (*models.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var latest *models.Task
+	for id := range s.cached[key] {
+		if t := s.tasks[id]; latest == nil || newerResult(t, latest) {
+			latest = t
+		}
+	}
+	if latest == nil {
+		return nil, nil
+	}
+	return latest.Clone(), nil
+}
+
+// GetTask returns a copy of the stored task.
+func (s *MemoryStore) GetTask(id string) (*models.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	task, ok := s.tasks[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return task.Clone(), nil
+}
+
+// UpdateTask replaces a stored task if its version is current.
+func (s *MemoryStore) UpdateTask(task *models.Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.tasks[task.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	if stored.Version != task.Version {
+		return ErrConflict
+	}
+	task.Version++
+	s.put(task)
+	return nil
+}
+
+// ClaimTask picks the best queued task under the store lock.
+func (s *MemoryStore) ClaimTask(filter ClaimFilter, apply func(task *models.Task) error) (*models.Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	holds := s.holds()
+	var next *models.Task
+	for id := range s.queued {
+		t := s.tasks[id]
+		if !filter.Allows(t) || !holds.admits(t, filter) {
+			continue
+		}
+		if next == nil || filter.Order.Before(t, next) {
+			next = t
+		}
+	}
+	if next == nil {
+		return nil, nil
+	}
+
+	task := next.Clone()
+	if err := apply(task); err != nil {
+		return nil, err
+	}
+	task.Version++
+	s.put(task)
+	s.starts.start(task, filter, holds.now)
+	return task, nil
+}
+
+// holds counts the held tasks. The caller must hold s.mu.
+func (s *MemoryStore) holds() *holds {
+	holds := newHolds(s.starts, time.Now())
+	for id := range s.held {
+		holds.add(s.tasks[id])
+	}
+	return holds
+}
+
+// HeldCounts counts the leased and running tasks by type and by queue.
+func (s *MemoryStore) HeldCounts() (byType, byQueue map[string]int, err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	holds := s.holds()
+	return holds.types, holds.queues, nil
+}
+
+// LimitStarts returns copies of the start rate state.
+func (s *MemoryStore) LimitStarts() (byType, byQueue map[string]time.Time, err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return maps.Clone(s.starts.types), maps.Clone(s.starts.queues), nil
+}
+
+// HeldTenants counts the leased and running tasks by tenant.
+func (s *MemoryStore) HeldTenants() (map[string]int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.holds().tenants, nil
+}
+
+// QueuedTenants counts the queued tasks by tenant.
+func (s *MemoryStore) QueuedTenants() (map[string]int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	counts := make(map[string]int)
+	for id := range s.queued {
+		counts[s.tasks[id].Tenant]++
+	}
+	return counts, nil
+}
+
+// PeekTasks sorts the claimable queued tasks in claim order.
+func (s *MemoryStore) PeekTasks(filter ClaimFilter, limit int) ([]*models.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	holds := s.holds()
+	var tasks []*models.Task
+	for id := range s.queued {
+		if t := s.tasks[id]; filter.Allows(t) && holds.admits(t, filter) {
+			tasks = append(tasks, t)
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool { return filter.Order.Before(tasks[i], tasks[j]) })
+	if len(tasks) > limit {
+		tasks = tasks[:limit]
+	}
+	for i, t := range tasks {
+		tasks[i] = t.Clone()
+	}
+	return tasks, nil
+}
+
+// RenewLeases extends the lease of every task held by workerID.
+func (s *MemoryStore) RenewLeases(workerID string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id := range s.held {
+		t := s.tasks[id]
+		if t.WorkerID == workerID {
+			expires := until
+			t.LeaseExpiresAt = &expires
+			t.Version++
+		}
+	}
+	return nil
+}
+
+// ExpiredLeases returns copies of held tasks whose lease ran out.
+func (s *MemoryStore) ExpiredLeases(now time.Time) ([]*models.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var expired []*models.Task
+	for id := range s.held {
+		if t := s.tasks[id]; t.LeaseExpired(now) {
+			expired = append(expired, t.Clone())
+		}
+	}
+	return expired, nil
+}
+
+// ListTasks returns copies of the matching tasks, oldest first.
+func (s *MemoryStore) ListTasks(filter TaskFilter) ([]*models.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var tasks []*models.Task
+	for _, t := range s.tasks {
+		if filter.Match(t) {
+			tasks = append(tasks, t)
+		}
+	}
+	slices.SortFunc(tasks, byCreation)
+	tasks = filter.page(tasks)
+	for i, t := range tasks {
+		tasks[i] = t.Clone()
+	}
+	return tasks, nil
+}
+
+// SaveWorker inserts or replaces a worker registration.
+func (s *MemoryStore) SaveWorker(worker *models.Worker) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.workers[worker.ID] = worker.Clone()
+	return nil
+}
+
+// GetWorker returns a copy of a worker registration.
+func (s *MemoryStore) GetWorker(id string) (*models.Worker, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	w, ok := s.workers[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return w.Clone(), nil
+}
+
+// TouchWorker records that a worker was seen.
+func (s *MemoryStore) TouchWorker(id string, seen time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w, ok := s.workers[id]
+	if !ok {
+		return ErrNotFound
+	}
+	w.LastSeen = seen
+	return nil
+}
+
+// AppendLogs adds lines to a job's log.
+func (s *MemoryStore) AppendLogs(jobID string, lines []models.LogLine) ([]models.LogLine, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	shipped := s.shipped[jobID]
+	if shipped == nil {
+		shipped = make(map[int]int64)
+		s.shipped[jobID] = shipped
+	}
+	added := unshipped(lines, shipped)
+	for i := range added {
+		s.logSeq[jobID]++
+		added[i].Seq = s.logSeq[jobID]
+		s.logs[jobID] = append(s.logs[jobID], added[i].Stored())
+	}
+	return added, nil
+}
+
+// ShippedSeqs returns the highest ShipSeq stored for each attempt of a job.
+func (s *MemoryStore) ShippedSeqs(jobID string) map[int]int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return maps.Clone(s.shipped[jobID])
+}
+
+// RestoreShippedSeqs replaces the highest ShipSeqs stored for a job's
+// attempts. Like RestoreLogs, it is used to rebuild the store from a
+// snapshot.
+func (s *MemoryStore) RestoreShippedSeqs(jobID string, seqs map[int]int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.shipped[jobID] = maps.Clone(seqs)
+}
+
+// RestoreLogs replaces a job's log with lines as-is, keeping their Seq. It
+// is used to rebuild the store from a snapshot rather than for new lines.
+func (s *MemoryStore) RestoreLogs(jobID string, lines []models.LogLine) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logs[jobID] = slices.Clone(lines)
+	if len(lines) > 0 {
+		s.logSeq[jobID] = lines[len(lines)-1].Seq
+	}
+}
+
+// DeleteLogs removes the start of a job's log.
+func (s *MemoryStore) DeleteLogs(jobID string, through int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	log := s.logs[jobID]
+	i := sort.Search(len(log), func(i int) bool { return log[i].Seq > through })
+	if i == len(log) {
+		delete(s.logs, jobID)
+		return nil
+	}
+	s.logs[jobID] = slices.Clone(log[i:])
+	return nil
+}
+
+// JobLogs returns a copy of part of a job's log.
+func (s *MemoryStore) JobLogs(jobID string, after int64, limit int) ([]models.LogLine, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	log := s.logs[jobID]
+	log = log[sort.Search(len(log), func(i int) bool { return log[i].Seq > after }):]
+	if len(log) == 0 {
+		return nil, nil
+	}
+	if limit > 0 && len(log) > limit {
+		log = log[:limit]
+	}
+	return slices.Clone(log), nil
+}
+
+// ListWorkers returns copies of all registered workers.
+func (s *MemoryStore) ListWorkers() ([]*models.Worker, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	workers := make([]*models.Worker, 0, len(s.workers))
+	for _, w := range s.workers {
+		workers = append(workers, w.Clone())
+	}
+	return workers, nil
+}
+
+// Close is a no-op for the in-memory store.
+func (s *MemoryStore) Close() error {
+	return nil
 }
@@ -0,0 +1,14 @@
+package storage_test
+
+import (
+	"testing"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/storage"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/storage/storagetest"
+)
+
+func TestMemoryStore(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storage.JobStore {
+		return storage.NewMemoryStore()
+	})
+}
@@ -7,9 +7,13 @@ import (
 )
 
 type Worker struct {
-	ID            string    `gorm:"primaryKey"`
-	Host          string    `gorm:"not null"`
-	LastHeartbeat time.Time `gorm:"not null"`
+	ID               string    `gorm:"primaryKey"`
+	Host             string    `gorm:"not null"`
+	LastHeartbeat    time.Time `gorm:"not null"`
+	Tasks            []byte    `gorm:"type:jsonb"`
+	AnyTask          bool      `gorm:"not null"`
+	CapacityCPU      float64   `gorm:"not null"`
+	CapacityMemoryMB int64     `gorm:"not null"`
 }
 
 func RegisterWorker(worker *Worker) error {
@@ -0,0 +1,141 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/models"
+	pb "github.com/dheeraj-sn/distributed-orchestrator/proto"
+)
+
+// artifactChunkSize is the most artifact data sent in one message.
+const artifactChunkSize = 256 << 10
+
+// maxInlineResult is the largest result sent in CompleteJob. Larger
+// results are uploaded as the resultArtifact artifact instead, keeping the
+// request under gRPC's message size limit.
+const (
+	maxInlineResult = 64 << 10
+	resultArtifact  = "result"
+)
+
+// UploadArtifact streams an artifact of a job this worker holds to the
+// scheduler.
+func (w *Worker) UploadArtifact(ctx context.Context, jobID, name, contentType string, r io.Reader) (*pb.ArtifactInfo, error) {
+	stream, err := w.Client.UploadArtifact(ctx)
+	if err != nil {
+		return nil, err
+	}
+	msg := &pb.ArtifactChunk{Header: &pb.ArtifactHeader{
+		JobId:       jobID,
+		WorkerId:    w.ID,
+		Name:        name,
+		ContentType: contentType,
+	}}
+	buf := make([]byte, artifactChunkSize)
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 || msg.Header != nil {
+			msg.Data = buf[:n]
+			if err := stream.Send(msg); err != nil {
+				// The scheduler rejected the upload; its reason comes
+				// with CloseAndRecv.
+				break
+			}
+			msg = &pb.ArtifactChunk{}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			stream.CloseSend()
+			return nil, fmt.Errorf("read artifact %s: %w", name, err)
+		}
+	}
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return nil, err
+	}
+	return resp.Artifact, nil
+}
+
+// OpenInput streams the file a job input refers to from the scheduler. It
+// is the remote worker's InputOpener.
+func (w *Worker) OpenInput(ctx context.Context, in models.JobInput) (io.ReadCloser, string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := w.Client.DownloadArtifact(ctx, &pb.DownloadArtifactRequest{
+		JobId:    in.JobID,
+		Name:     in.Artifact,
+		UploadId: in.UploadID,
+	})
+	if err != nil {
+		cancel()
+		return nil, "", err
+	}
+	first, err := stream.Recv()
+	if err != nil {
+		cancel()
+		return nil, "", err
+	}
+	return &downloadReader{stream: stream, buf: first.Data, cancel: cancel}, first.Info.GetSha256(), nil
+}
+
+// downloadReader reads the data of a DownloadArtifact stream.
+type downloadReader struct {
+	stream pb.Orchestrator_DownloadArtifactClient
+	buf    []byte
+	cancel context.CancelFunc
+}
+
+func (r *downloadReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		msg, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		r.buf = msg.Data
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+func (r *downloadReader) Close() error {
+	r.cancel()
+	return nil
+}
+
+// inlineResult returns the result to report in CompleteJob, uploading it
+// as an artifact first if it is too large to send inline.
+func (w *Worker) inlineResult(ctx context.Context, jobID, output string) (string, error) {
+	if len(output) <= maxInlineResult {
+		return output, nil
+	}
+	art, err := w.UploadArtifact(ctx, jobID, resultArtifact, "text/plain; charset=utf-8", strings.NewReader(output))
+	if err != nil {
+		return "", fmt.Errorf("upload result: %w", err)
+	}
+	return fmt.Sprintf("%d-byte result stored as artifact %q", art.Size, art.Name), nil
+}
+
+func jobInputs(inputs []*pb.JobInput) []models.JobInput {
+	var out []models.JobInput
+	for _, in := range inputs {
+		ji := models.JobInput{Path: in.Path, UploadID: in.GetUploadId()}
+		if ref := in.GetArtifact(); ref != nil {
+			ji.JobID, ji.Artifact = ref.JobId, ref.Name
+		}
+		out = append(out, ji)
+	}
+	return out
+}
+
+func jobOutputs(outputs []*pb.JobOutput) []models.JobOutput {
+	var out []models.JobOutput
+	for _, o := range outputs {
+		out = append(out, models.JobOutput{Path: o.Path, Artifact: o.Artifact})
+	}
+	return out
+}
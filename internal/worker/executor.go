@@ -0,0 +1,233 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/models"
+)
+
+// Job is the unit of work handed to an Executor.
+type Job struct {
+	ID   string
+	Task string
+	Args []string
+	// Payload is the job's structured input, a JSON object, or nil if the
+	// job was submitted without one. See DecodePayload.
+	Payload json.RawMessage
+
+	// Inputs are staged into Dir before the job runs, and Outputs
+	// collected from it after it succeeds.
+	Inputs  []models.JobInput
+	Outputs []models.JobOutput
+	// Dir is the job's working directory when it runs through Workspaces.
+	Dir string
+	// Timeout, if set, bounds how long the job may run.
+	Timeout time.Duration
+
+	// Log records a line of job output. It is never nil.
+	Log func(line string)
+	// SaveArtifact stores a named file produced by the job, replacing one
+	// of the same name. It is never nil.
+	SaveArtifact func(name, contentType string, r io.Reader) error
+}
+
+// DecodePayload unmarshals the job's payload into v, leaving v unchanged
+// if there is none.
+func (j *Job) DecodePayload(v any) error {
+	if len(j.Payload) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(j.Payload, v); err != nil {
+		return fmt.Errorf("decode payload: %w", err)
+	}
+	return nil
+}
+
+// ErrArtifactsUnsupported is returned by SaveArtifact where the job's
+// runner has nowhere to store artifacts.
+var ErrArtifactsUnsupported = errors.New("artifacts are not supported here")
+
+// Result is the outcome of a successful execution.
+type Result struct {
+	Output string
+	// Data is an optional structured result. It must encode to a JSON
+	// object.
+	Data any
+}
+
+// DataJSON encodes the structured result, returning nil if there is none.
+func (r *Result) DataJSON() (json.RawMessage, error) {
+	if r.Data == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(r.Data)
+	if err != nil {
+		return nil, fmt.Errorf("encode structured result: %w", err)
+	}
+	if string(raw) == "null" {
+		return nil, nil
+	}
+	if raw[0] != '{' {
+		return nil, errors.New("structured result must be a JSON object")
+	}
+	return raw, nil
+}
+
+// ExitCode derives a process-style exit code from an execution error: 0 for
+// success, the process exit status for *exec.ExitError, and 1 otherwise.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return 1
+}
+
+// Executor runs jobs of a given task type. Remote workers and the scheduler's
+// embedded local executor both run jobs through this interface.
+type Executor interface {
+	Execute(ctx context.Context, job *Job) (*Result, error)
+}
+
+// ExecutorFunc adapts a plain function to the Executor interface.
+type ExecutorFunc func(ctx context.Context, job *Job) (*Result, error)
+
+func (f ExecutorFunc) Execute(ctx context.Context, job *Job) (*Result, error) {
+	return f(ctx, job)
+}
+
+// Registry maps task names to executors. The worker advertises the tasks
+// registered in it to the scheduler, which only hands it jobs of those
+// tasks, or of any task if a fallback is set.
+type Registry struct {
+	mu        sync.RWMutex
+	executors map[string]Executor
+	versions  map[string]string
+	fallback  Executor
+}
+
+// NewRegistry creates an empty registry. Jobs for unknown tasks fail.
+func NewRegistry() *Registry {
+	return &Registry{
+		executors: make(map[string]Executor),
+		versions:  make(map[string]string),
+	}
+}
+
+// NewDefaultRegistry creates a registry with the built-in executors.
+func NewDefaultRegistry() *Registry {
+	r := NewRegistry()
+	r.Register("echo", ExecutorFunc(echo))
+	r.SetFallback(ExecutorFunc(simulate))
+	return r
+}
+
+// Register installs the executor for a task, replacing any previous one.
+func (r *Registry) Register(task string, e Executor) {
+	r.RegisterVersion(task, "", e)
+}
+
+// RegisterVersion is Register for an executor that reports a version,
+// which is advertised to the scheduler along with its task.
+func (r *Registry) RegisterVersion(task, version string, e Executor) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.executors[task] = e
+	r.versions[task] = version
+}
+
+// Capabilities lists the registered tasks by name and reports whether a
+// fallback runs any other task.
+func (r *Registry) Capabilities() ([]models.TaskCapability, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	tasks := make([]models.TaskCapability, 0, len(r.executors))
+	for task := range r.executors {
+		tasks = append(tasks, models.TaskCapability{Task: task, Version: r.versions[task]})
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].Task < tasks[j].Task })
+	return tasks, r.fallback != nil
+}
+
+// SetFallback sets the executor used for tasks without a registered executor.
+func (r *Registry) SetFallback(e Executor) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fallback = e
+}
+
+// Lookup returns the executor for a task.
+func (r *Registry) Lookup(task string) (Executor, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if e, ok := r.executors[task]; ok {
+		return e, true
+	}
+	return r.fallback, r.fallback != nil
+}
+
+// ErrTimedOut is returned for jobs that ran past their timeout.
+var ErrTimedOut = errors.New("job timed out")
+
+// Execute runs the job with the executor registered for its task, cancelling
+// its context once its timeout passes.
+func (r *Registry) Execute(ctx context.Context, job *Job) (*Result, error) {
+	e, ok := r.Lookup(job.Task)
+	if !ok {
+		return nil, fmt.Errorf("no executor registered for task %q", job.Task)
+	}
+	if job.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, job.Timeout)
+		defer cancel()
+	}
+	if job.Log == nil {
+		job.Log = func(string) {}
+	}
+	if job.SaveArtifact == nil {
+		job.SaveArtifact = func(string, string, io.Reader) error { return ErrArtifactsUnsupported }
+	}
+	res, err := e.Execute(ctx, job)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("%w after %s: %v", ErrTimedOut, job.Timeout, err)
+	}
+	return res, err
+}
+
+func echo(ctx context.Context, job *Job) (*Result, error) {
+	out := strings.Join(job.Args, " ")
+	job.Log(out)
+	res := &Result{Output: out}
+	if job.Payload != nil {
+		res.Data = job.Payload
+	}
+	return res, nil
+}
+
+// simulate mimics a multi-step task for demonstration purposes.
+func simulate(ctx context.Context, job *Job) (*Result, error) {
+	for _, line := range []string{
+		"Starting task",
+		"Executing step 1",
+		"Executing step 2",
+		"Task completed successfully",
+	} {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		job.Log(line)
+	}
+	return &Result{Output: "Job completed successfully"}, nil
+}
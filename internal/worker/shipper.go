@@ -0,0 +1,468 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/config"
+	pb "github.com/dheeraj-sn/distributed-orchestrator/proto"
+)
+
+// maxBatchBytes keeps a batch well under gRPC's default 4 MiB message limit.
+const maxBatchBytes = 1 << 20
+
+// Delay before reopening the log stream after it fails without getting a
+// batch through, doubling per consecutive failure.
+const (
+	minShipBackoff = time.Second
+	maxShipBackoff = 30 * time.Second
+)
+
+// LogShipper sends job output to the scheduler over one long-lived ShipLogs
+// stream, in batches with one batch in flight at a time.
+//
+// Lines are buffered in memory up to MaxBufferBytes. When the buffer is
+// full, or while the scheduler is unreachable, they are moved to segment
+// files in the spool directory and sent from there, oldest first, once the
+// stream is back; segments left by a previous run are sent too. Lines logged
+// while the spool is at MaxSpoolBytes are dropped.
+//
+// Every line carries its job attempt's sequence number, so the scheduler
+// can drop lines it already stored when a batch is resent because its ack
+// was lost.
+type LogShipper struct {
+	client   pb.OrchestratorClient
+	workerID string
+	cfg      config.LogShippingConfig
+	logger   *zap.Logger
+
+	mu         sync.Mutex
+	next       int64         // N of the next record
+	buf        []spoolRecord // newest records, all after the spool's
+	bufBytes   int64
+	segments   []*spoolSegment // oldest first
+	spoolBytes int64
+	inflight   []spoolRecord
+	inflightOf *spoolSegment // segment the inflight batch came from, nil for buf
+	acked      int64         // N of the last acked record
+	dropped    int64         // lines dropped since the last report
+	wake       chan struct{}
+	progress   chan struct{} // closed and replaced when acked advances
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewLogShipper creates a shipper and picks up any spool left by a previous
+// run. Call Start to begin shipping.
+func NewLogShipper(client pb.OrchestratorClient, workerID string, cfg config.LogShippingConfig, logger *zap.Logger) (*LogShipper, error) {
+	if cfg.BatchSize < 1 {
+		cfg.BatchSize = 1
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = 200 * time.Millisecond
+	}
+	if cfg.MaxBufferBytes <= 0 {
+		cfg.MaxBufferBytes = 4 << 20
+	}
+	if err := os.MkdirAll(cfg.SpoolDir, 0o755); err != nil {
+		return nil, fmt.Errorf("create log spool: %w", err)
+	}
+	// Segments still being written when the last run stopped.
+	tmps, _ := filepath.Glob(filepath.Join(cfg.SpoolDir, "*"+spoolExt+".tmp"))
+	for _, tmp := range tmps {
+		os.Remove(tmp)
+	}
+	segments, err := listSegments(cfg.SpoolDir)
+	if err != nil {
+		return nil, fmt.Errorf("read log spool: %w", err)
+	}
+
+	s := &LogShipper{
+		client:   client,
+		workerID: workerID,
+		cfg:      cfg,
+		logger:   logger,
+		next:     1,
+		segments: segments,
+		wake:     make(chan struct{}, 1),
+		progress: make(chan struct{}),
+	}
+	for _, seg := range segments {
+		s.spoolBytes += seg.bytes
+	}
+	if len(segments) > 0 {
+		last := segments[len(segments)-1]
+		records, err := readSegment(last.path)
+		if err != nil {
+			logger.Warn("Log spool segment is damaged", zap.Error(err))
+		}
+		s.next = last.first + 1
+		if len(records) > 0 {
+			s.next = records[len(records)-1].N + 1
+		}
+		logger.Info("Found spooled job logs", zap.Int("segments", len(segments)), zap.Int64("bytes", s.spoolBytes))
+	}
+	return s, nil
+}
+
+// JobLogger returns the Log function for one attempt of a job. It never
+// blocks on the network.
+func (s *LogShipper) JobLogger(jobID string, attempt int) func(line string) {
+	var seq int64
+	return func(line string) {
+		if s.cfg.MaxLineBytes > 0 && len(line) > s.cfg.MaxLineBytes {
+			line = line[:s.cfg.MaxLineBytes]
+		}
+		// Protobuf strings must be valid UTF-8, and a line cut above may
+		// end mid-rune.
+		line = strings.ToValidUTF8(line, "�")
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		r := spoolRecord{
+			N:       s.next,
+			JobID:   jobID,
+			Attempt: attempt,
+			Seq:     seq + 1,
+			At:      time.Now(),
+			Message: line,
+		}
+		if s.bufBytes+r.size() > s.cfg.MaxBufferBytes && len(s.buf) > 0 {
+			if err := s.spill(); err != nil {
+				s.drop(1, err)
+				return
+			}
+		}
+		seq++
+		s.next++
+		s.buf = append(s.buf, r)
+		s.bufBytes += r.size()
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Start ships lines in the background until Close.
+func (s *LogShipper) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+	go func() {
+		defer close(s.done)
+		s.run(ctx)
+	}()
+}
+
+// Flush waits until every line logged before the call has been acked by
+// the scheduler, or ctx is done.
+func (s *LogShipper) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	target := s.next - 1
+	s.mu.Unlock()
+	for {
+		s.mu.Lock()
+		done := s.acked >= target
+		progress := s.progress
+		s.mu.Unlock()
+		if done {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-progress:
+		}
+	}
+}
+
+// Close ships what it can until ctx is done, then stops and spools any
+// lines still in memory for the next run to send.
+func (s *LogShipper) Close(ctx context.Context) error {
+	if s.cancel != nil {
+		s.Flush(ctx)
+		s.cancel()
+		<-s.done
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := len(s.buf)
+	if err := s.spill(); err != nil {
+		return fmt.Errorf("spool %d log lines: %w", n, err)
+	}
+	return nil
+}
+
+func (s *LogShipper) run(ctx context.Context) {
+	backoff := minShipBackoff
+	for {
+		shipped, err := s.ship(ctx)
+		s.mu.Lock()
+		s.requeue()
+		s.mu.Unlock()
+		if ctx.Err() != nil {
+			return
+		}
+		// A stream that got batches through is reopened at once; the
+		// backoff applies to consecutive failures.
+		if shipped {
+			s.logger.Info("Log stream closed; reopening", zap.Error(err))
+			backoff = minShipBackoff
+			continue
+		}
+		s.logger.Warn("Log shipping interrupted", zap.Error(err), zap.Duration("retry_in", backoff))
+		if !s.spoolUntil(ctx, time.Now().Add(backoff)) {
+			return
+		}
+		backoff = min(backoff*2, maxShipBackoff)
+	}
+}
+
+// spoolUntil moves buffered lines to the spool every flush interval while
+// the scheduler is unreachable, so they survive a worker restart. It
+// returns false if ctx is done first.
+func (s *LogShipper) spoolUntil(ctx context.Context, deadline time.Time) bool {
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+	ticker := time.NewTicker(s.cfg.FlushInterval)
+	defer ticker.Stop()
+	for {
+		// If the spool is full the lines stay buffered, and JobLogger
+		// drops new ones once the buffer is full too.
+		s.mu.Lock()
+		s.spill()
+		s.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-timer.C:
+			return true
+		case <-ticker.C:
+		}
+	}
+}
+
+// ship sends batches over one stream until it fails, reporting whether any
+// batch was acked.
+func (s *LogShipper) ship(ctx context.Context) (bool, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := s.client.ShipLogs(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	shipped := false
+	for id := int64(1); ; id++ {
+		batch, err := s.nextBatch(ctx)
+		if err != nil {
+			return shipped, err
+		}
+		req := &pb.LogBatch{BatchId: id, Entries: make([]*pb.LogEntry, 0, len(batch))}
+		for _, r := range batch {
+			req.Entries = append(req.Entries, &pb.LogEntry{
+				JobId:     r.JobID,
+				WorkerId:  s.workerID,
+				Timestamp: r.At.Format(time.RFC3339Nano),
+				Message:   r.Message,
+				Seq:       r.Seq,
+				Attempt:   int32(r.Attempt),
+			})
+		}
+		if err := stream.Send(req); err != nil {
+			return shipped, err
+		}
+		ack, err := stream.Recv()
+		if err != nil {
+			return shipped, err
+		}
+		if ack.BatchId != id {
+			return shipped, fmt.Errorf("scheduler acked log batch %d, want %d", ack.BatchId, id)
+		}
+		s.ack()
+		shipped = true
+	}
+}
+
+// nextBatch waits for lines to send and marks them in flight. The spool is
+// always older than the buffer, so it is drained first; buffered lines wait
+// for a full batch or the flush interval.
+func (s *LogShipper) nextBatch(ctx context.Context) ([]spoolRecord, error) {
+	var flush <-chan time.Time
+	flushDue := false
+	for {
+		s.mu.Lock()
+		if len(s.segments) > 0 {
+			seg := s.segments[0]
+			if !seg.loaded {
+				records, err := readSegment(seg.path)
+				if err != nil {
+					s.logger.Warn("Log spool segment is damaged", zap.Error(err))
+				}
+				seg.records, seg.loaded = records, true
+			}
+			if len(seg.records) == 0 {
+				s.removeSegment(seg)
+				s.mu.Unlock()
+				continue
+			}
+			s.inflight, s.inflightOf = s.take(seg.records), seg
+			s.mu.Unlock()
+			return s.inflight, nil
+		}
+		if len(s.buf) >= s.cfg.BatchSize || (len(s.buf) > 0 && flushDue) {
+			s.inflight, s.inflightOf = s.take(s.buf), nil
+			s.buf = s.buf[len(s.inflight):]
+			for _, r := range s.inflight {
+				s.bufBytes -= r.size()
+			}
+			s.mu.Unlock()
+			return s.inflight, nil
+		}
+		if len(s.buf) > 0 && flush == nil {
+			timer := time.NewTimer(s.cfg.FlushInterval)
+			defer timer.Stop()
+			flush = timer.C
+		}
+		s.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-s.wake:
+		case <-flush:
+			flushDue = true
+		}
+	}
+}
+
+// take returns the head of records that fits in one batch.
+func (s *LogShipper) take(records []spoolRecord) []spoolRecord {
+	n, bytes := 0, int64(0)
+	for n < len(records) && n < s.cfg.BatchSize {
+		bytes += records[n].size()
+		if n > 0 && bytes > maxBatchBytes {
+			break
+		}
+		n++
+	}
+	return records[:n:n]
+}
+
+// ack records that the inflight batch was stored by the scheduler.
+func (s *LogShipper) ack() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if seg := s.inflightOf; seg != nil {
+		seg.records = seg.records[len(s.inflight):]
+		if len(seg.records) == 0 {
+			s.removeSegment(seg)
+		}
+	}
+	s.acked = s.inflight[len(s.inflight)-1].N
+	s.inflight, s.inflightOf = nil, nil
+	close(s.progress)
+	s.progress = make(chan struct{})
+	if s.dropped > 0 {
+		s.logger.Warn("Dropped job log lines while the log spool was full", zap.Int64("lines", s.dropped))
+		s.dropped = 0
+	}
+}
+
+// requeue returns an unacked batch to the front of the queue after the
+// stream failed. A batch from a segment is still there; one from the buffer
+// goes back to it unless newer lines have been spooled meanwhile, in which
+// case it gets a segment of its own that sorts before theirs.
+func (s *LogShipper) requeue() {
+	batch, seg := s.inflight, s.inflightOf
+	s.inflight, s.inflightOf = nil, nil
+	if len(batch) == 0 || seg != nil {
+		return
+	}
+	if len(s.segments) == 0 {
+		s.buf = append(append([]spoolRecord(nil), batch...), s.buf...)
+		for _, r := range batch {
+			s.bufBytes += r.size()
+		}
+		return
+	}
+	if err := s.writeSegment(batch, false); err != nil {
+		s.drop(len(batch), err)
+	}
+}
+
+// spill moves the buffer to the spool.
+func (s *LogShipper) spill() error {
+	if len(s.buf) == 0 {
+		return nil
+	}
+	if err := s.writeSegment(s.buf, true); err != nil {
+		return err
+	}
+	s.buf, s.bufBytes = nil, 0
+	return nil
+}
+
+// writeSegment spools records, appending them to the newest segment when
+// they follow it and it is neither being sent nor already buffer-sized.
+func (s *LogShipper) writeSegment(records []spoolRecord, mayAppend bool) error {
+	data, err := encodeRecords(records)
+	if err != nil {
+		return err
+	}
+	if s.spoolBytes+int64(len(data)) > s.cfg.MaxSpoolBytes {
+		return errSpoolFull
+	}
+	if n := len(s.segments); mayAppend && n > 0 {
+		last := s.segments[n-1]
+		if !last.loaded && last.bytes+int64(len(data)) <= s.cfg.MaxBufferBytes {
+			if err := appendSegment(last, data); err != nil {
+				return err
+			}
+			s.spoolBytes += int64(len(data))
+			return nil
+		}
+	}
+	seg, err := writeSegment(s.cfg.SpoolDir, records, data)
+	if err != nil {
+		return err
+	}
+	s.spoolBytes += seg.bytes
+	i := sort.Search(len(s.segments), func(i int) bool { return s.segments[i].first > seg.first })
+	s.segments = append(s.segments, nil)
+	copy(s.segments[i+1:], s.segments[i:])
+	s.segments[i] = seg
+	return nil
+}
+
+func (s *LogShipper) removeSegment(seg *spoolSegment) {
+	for i, other := range s.segments {
+		if other == seg {
+			s.segments = append(s.segments[:i], s.segments[i+1:]...)
+			break
+		}
+	}
+	s.spoolBytes -= seg.bytes
+	if err := os.Remove(seg.path); err != nil && !os.IsNotExist(err) {
+		s.logger.Warn("Failed to remove log spool segment", zap.String("path", seg.path), zap.Error(err))
+	}
+}
+
+// drop counts lines that could not be kept, warning when dropping starts.
+func (s *LogShipper) drop(n int, err error) {
+	if s.dropped == 0 {
+		s.logger.Warn("Dropping job log lines", zap.Error(err))
+	}
+	s.dropped += int64(n)
+}
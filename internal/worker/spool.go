@@ -0,0 +1,157 @@
+package worker
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// errSpoolFull is returned when writing would grow the spool past its limit.
+var errSpoolFull = errors.New("log spool is full")
+
+const spoolExt = ".spool"
+
+// spoolRecord is one buffered log line. N orders records across the memory
+// buffer and the spool, and across worker restarts.
+type spoolRecord struct {
+	N       int64     `json:"n"`
+	JobID   string    `json:"job_id"`
+	Attempt int       `json:"attempt"`
+	Seq     int64     `json:"seq"`
+	At      time.Time `json:"at"`
+	Message string    `json:"message"`
+}
+
+// size approximates the memory a record holds.
+func (r *spoolRecord) size() int64 {
+	return int64(len(r.JobID)+len(r.Message)) + 64
+}
+
+// spoolSegment is a file of JSON-encoded records in N order, named after the
+// N of its first record so that a directory listing sorts segments oldest
+// first.
+type spoolSegment struct {
+	path  string
+	first int64
+	bytes int64
+
+	// records holds the segment's unacked records once it is being sent.
+	records []spoolRecord
+	loaded  bool
+}
+
+func segmentPath(dir string, first int64) string {
+	return filepath.Join(dir, fmt.Sprintf("%020d%s", first, spoolExt))
+}
+
+// listSegments returns the segments in dir, oldest first.
+func listSegments(dir string) ([]*spoolSegment, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var segments []*spoolSegment
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, spoolExt) {
+			continue
+		}
+		first, err := strconv.ParseInt(strings.TrimSuffix(name, spoolExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, &spoolSegment{
+			path:  filepath.Join(dir, name),
+			first: first,
+			bytes: info.Size(),
+		})
+	}
+	sort.Slice(segments, func(i, j int) bool { return segments[i].first < segments[j].first })
+	return segments, nil
+}
+
+// readSegment decodes a segment. A record cut short by a crash ends the
+// segment; the records before it are returned along with the error.
+func readSegment(path string) ([]spoolRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []spoolRecord
+	dec := json.NewDecoder(bufio.NewReader(f))
+	for {
+		var r spoolRecord
+		if err := dec.Decode(&r); err == io.EOF {
+			return records, nil
+		} else if err != nil {
+			return records, fmt.Errorf("read %s: %w", path, err)
+		}
+		records = append(records, r)
+	}
+}
+
+func encodeRecords(records []spoolRecord) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for i := range records {
+		if err := enc.Encode(&records[i]); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// writeSegment creates a segment holding records. The file is written under
+// a temporary name and renamed, so a crash never leaves a partial segment
+// that sorts ahead of complete ones.
+func writeSegment(dir string, records []spoolRecord, data []byte) (*spoolSegment, error) {
+	path := segmentPath(dir, records[0].N)
+	tmp := path + ".tmp"
+	if err := writeSynced(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, data); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return nil, err
+	}
+	return &spoolSegment{path: path, first: records[0].N, bytes: int64(len(data))}, nil
+}
+
+// appendSegment adds data to the end of an unloaded segment.
+func appendSegment(seg *spoolSegment, data []byte) error {
+	if err := writeSynced(seg.path, os.O_APPEND|os.O_WRONLY, data); err != nil {
+		return err
+	}
+	seg.bytes += int64(len(data))
+	return nil
+}
+
+func writeSynced(path string, flag int, data []byte) error {
+	f, err := os.OpenFile(path, flag, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -2,6 +2,9 @@ package worker
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"sync"
@@ -11,46 +14,95 @@ import (
 	pb "github.com/dheeraj-sn/distributed-orchestrator/proto"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/config"
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/models"
+)
+
+// Bounds on waiting for job output to reach the scheduler: before reporting
+// a job complete, and before shutting down.
+const (
+	logFlushTimeout = 10 * time.Second
+	logDrainTimeout = 5 * time.Second
 )
 
 type Worker struct {
-	ID          string
-	Host        string
-	Client      pb.OrchestratorClient
-	Logger      *zap.Logger
+	ID         string
+	Host       string
+	Client     pb.OrchestratorClient
+	Executors  *Registry
+	Workspaces *Workspaces
+	Logs       *LogShipper
+	Logger     *zap.Logger
+	// Capacity is advertised on registration, for the bin_packing
+	// scheduling policy; zero fields are unlimited.
+	Capacity    models.Resources
 	stopChan    chan struct{}
 	concurrency int
 	sem         chan struct{}
 	wg          sync.WaitGroup
 }
 
-func NewWorker(id, host string, conn *grpc.ClientConn, logger *zap.Logger, concurrency int) *Worker {
+func NewWorker(id, host string, conn *grpc.ClientConn, logger *zap.Logger, concurrency int, workDir string, shipping config.LogShippingConfig) (*Worker, error) {
 	client := pb.NewOrchestratorClient(conn)
-	return &Worker{
+	logs, err := NewLogShipper(client, id, shipping, logger)
+	if err != nil {
+		return nil, err
+	}
+	w := &Worker{
 		ID:          id,
 		Host:        host,
 		Client:      client,
+		Executors:   NewDefaultRegistry(),
+		Logs:        logs,
 		Logger:      logger,
 		stopChan:    make(chan struct{}),
 		concurrency: concurrency,
 		sem:         make(chan struct{}, concurrency),
 	}
+	w.Workspaces = &Workspaces{Root: workDir, Open: w.OpenInput}
+	return w, nil
 }
 
+// Register announces the worker to the scheduler along with the tasks its
+// executors run; it is only handed jobs of those tasks.
 func (w *Worker) Register() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	_, err := w.Client.RegisterWorker(ctx, &pb.RegisterWorkerRequest{
+	tasks, anyTask := w.Executors.Capabilities()
+	req := &pb.RegisterWorkerRequest{
 		WorkerId: w.ID,
 		Host:     w.Host,
-	})
+		AnyTask:  anyTask,
+	}
+	if !w.Capacity.IsZero() {
+		req.Capacity = &pb.Resources{Cpu: w.Capacity.CPU, MemoryMb: w.Capacity.MemoryMB}
+	}
+	for _, c := range tasks {
+		req.Tasks = append(req.Tasks, &pb.TaskCapability{Task: c.Task, Version: c.Version})
+	}
+	_, err := w.Client.RegisterWorker(ctx, req)
 	if err == nil {
-		w.Logger.Info("Worker registered", zap.String("id", w.ID))
+		w.Logger.Info("Worker registered", zap.String("id", w.ID), zap.Any("tasks", tasks), zap.Bool("any_task", anyTask), zap.Any("capacity", w.Capacity))
 	}
 	return err
 }
 
+// reregister registers again after the scheduler has forgotten the worker,
+// e.g. because it restarted without persistent storage.
+func (w *Worker) reregister() {
+	w.Logger.Warn("Scheduler does not know this worker; registering again")
+	if err := w.Register(); err != nil {
+		w.Logger.Warn("Worker registration failed", zap.Error(err))
+	}
+}
+
 func (w *Worker) StartHeartbeat(interval time.Duration) {
 	go func() {
 		ticker := time.NewTicker(interval)
@@ -58,10 +110,13 @@ func (w *Worker) StartHeartbeat(interval time.Duration) {
 			select {
 			case <-ticker.C:
 				ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-				w.Client.SendHeartbeat(ctx, &pb.HeartbeatRequest{
+				resp, err := w.Client.SendHeartbeat(ctx, &pb.HeartbeatRequest{
 					WorkerId: w.ID,
 				})
 				cancel()
+				if err == nil && !resp.Alive {
+					w.reregister()
+				}
 			case <-w.stopChan:
 				ticker.Stop()
 				return
@@ -74,55 +129,52 @@ func (w *Worker) Stop() {
 	close(w.stopChan)
 	w.Logger.Info("Worker shutting down", zap.String("id", w.ID))
 	w.wg.Wait()
-}
 
-// streamJobLogs streams logs line-by-line over the gRPC stream
-func (w *Worker) streamJobLogs(ctx context.Context, jobID string, lines []string) {
-	stream, err := w.Client.StreamLogs(ctx)
-	if err != nil {
-		w.Logger.Error("Failed to open log stream", zap.Error(err))
-		return
-	}
-	defer stream.CloseSend()
-
-	for _, line := range lines {
-		entry := &pb.LogEntry{
-			JobId:     jobID,
-			WorkerId:  w.ID,
-			Timestamp: time.Now().Format(time.RFC3339),
-			Message:   line,
-		}
-		if err := stream.Send(entry); err != nil {
-			w.Logger.Error("Failed to send log", zap.Error(err))
-			break
-		}
-		_, _ = stream.Recv()               // ignore ack for simplicity
-		time.Sleep(500 * time.Millisecond) // simulate delay for real-time logs
+	ctx, cancel := context.WithTimeout(context.Background(), logDrainTimeout)
+	defer cancel()
+	if err := w.Logs.Close(ctx); err != nil {
+		w.Logger.Error("Failed to spool job logs", zap.Error(err))
 	}
 }
 
-func (w *Worker) StartExecutorLoop(pollInterval time.Duration) {
+// pullWait is how long a PullJob call waits on the scheduler for a job.
+const pullWait = 10 * time.Second
+
+// StartExecutorLoop long-polls the scheduler for jobs, backing off by
+// retryInterval after errors.
+func (w *Worker) StartExecutorLoop(retryInterval time.Duration) {
 	go func() {
 		for {
 			select {
 			case <-w.stopChan:
 				return
 			default:
-				time.Sleep(pollInterval)
+				// Only pull when there is capacity, so a pulled job is never
+				// left waiting on a slot.
+				w.sem <- struct{}{}
 
-				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				ctx, cancel := context.WithTimeout(context.Background(), pullWait+5*time.Second)
 				resp, err := w.Client.PullJob(ctx, &pb.PullJobRequest{
 					WorkerId: w.ID,
+					Wait:     durationpb.New(pullWait),
 				})
 				cancel()
 				if err != nil || !resp.Found {
+					<-w.sem
+					if err != nil {
+						if status.Code(err) == codes.FailedPrecondition {
+							w.reregister()
+						}
+						w.Logger.Warn("Failed to pull job", zap.Error(err))
+						time.Sleep(retryInterval)
+					}
 					continue
 				}
 
-				w.sem <- struct{}{}
 				w.wg.Add(1)
 
-				go func(jobID, task string, args []string) {
+				go func(resp *pb.PullJobResponse) {
+					jobID, task, attempt := resp.JobId, resp.Task, int(resp.Attempt)
 					defer func() {
 						<-w.sem
 						w.wg.Done()
@@ -130,23 +182,59 @@ func (w *Worker) StartExecutorLoop(pollInterval time.Duration) {
 
 					w.Logger.Info("Pulled job", zap.String("id", jobID), zap.String("task", task))
 
-					// Simulated log lines for demonstration
-					logLines := []string{
-						"Starting task",
-						"Executing step 1",
-						"Executing step 2",
-						"Task completed successfully",
+					startCtx, cancelStart := context.WithTimeout(context.Background(), 5*time.Second)
+					started, err := w.Client.StartJob(startCtx, &pb.StartJobRequest{
+						JobId:    jobID,
+						WorkerId: w.ID,
+					})
+					cancelStart()
+					if err != nil || !started.Success {
+						w.Logger.Warn("Scheduler rejected job start", zap.String("job_id", jobID), zap.Error(err))
+						return
+					}
+
+					job := &Job{
+						ID:      jobID,
+						Task:    task,
+						Args:    resp.Args,
+						Payload: payloadJSON(resp.Payload),
+						Timeout: resp.Timeout.AsDuration(),
+						Inputs:  jobInputs(resp.Inputs),
+						Outputs: jobOutputs(resp.Outputs),
+						Log:     w.Logs.JobLogger(jobID, attempt),
+						SaveArtifact: func(name, contentType string, r io.Reader) error {
+							_, err := w.UploadArtifact(context.Background(), jobID, name, contentType, r)
+							return err
+						},
+					}
+					complete := &pb.CompleteJobRequest{
+						JobId:    jobID,
+						WorkerId: w.ID,
+					}
+					result, err := w.Workspaces.Execute(context.Background(), w.Executors, job, attempt)
+					if err == nil {
+						complete.Output, err = outputStruct(result)
+					}
+					if err != nil {
+						w.Logger.Error("Job failed", zap.String("job_id", jobID), zap.Error(err))
+						complete.Error = err.Error()
+						complete.ExitCode = int32(ExitCode(err))
+					} else if complete.Result, err = w.inlineResult(context.Background(), jobID, result.Output); err != nil {
+						w.Logger.Error("Failed to store job result", zap.String("job_id", jobID), zap.Error(err))
+						complete.Error = err.Error()
+						complete.ExitCode = 1
 					}
 
-					streamCtx, cancelStream := context.WithTimeout(context.Background(), 30*time.Second)
-					w.streamJobLogs(streamCtx, jobID, logLines)
-					cancelStream()
+					// Ship the job's output before reporting completion, so
+					// a client tailing the log sees all of it.
+					flushCtx, cancelFlush := context.WithTimeout(context.Background(), logFlushTimeout)
+					if err := w.Logs.Flush(flushCtx); err != nil {
+						w.Logger.Warn("Job logs not yet shipped", zap.String("job_id", jobID), zap.Error(err))
+					}
+					cancelFlush()
 
 					ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-					_, err := w.Client.CompleteJob(ctx, &pb.CompleteJobRequest{
-						JobId:  jobID,
-						Result: "Job completed successfully",
-					})
+					_, err = w.Client.CompleteJob(ctx, complete)
 					cancel()
 
 					if err != nil {
@@ -154,12 +242,40 @@ func (w *Worker) StartExecutorLoop(pollInterval time.Duration) {
 					} else {
 						w.Logger.Info("Reported job completion", zap.String("job_id", jobID))
 					}
-				}(resp.JobId, resp.Task, resp.Args)
+				}(resp)
 			}
 		}
 	}()
 }
 
+// maxInlineOutput bounds the structured result sent in CompleteJob.
+const maxInlineOutput = 1 << 20
+
+// payloadJSON encodes a pulled job's payload for its executor.
+func payloadJSON(s *structpb.Struct) json.RawMessage {
+	if s == nil {
+		return nil
+	}
+	raw, _ := json.Marshal(s.AsMap())
+	return raw
+}
+
+// outputStruct converts a result's structured data for CompleteJob.
+func outputStruct(res *Result) (*structpb.Struct, error) {
+	raw, err := res.DataJSON()
+	if err != nil || raw == nil {
+		return nil, err
+	}
+	if len(raw) > maxInlineOutput {
+		return nil, fmt.Errorf("structured result of %d bytes exceeds the %d-byte limit", len(raw), maxInlineOutput)
+	}
+	s := &structpb.Struct{}
+	if err := protojson.Unmarshal(raw, s); err != nil {
+		return nil, fmt.Errorf("encode structured result: %w", err)
+	}
+	return s, nil
+}
+
 func WaitForShutdown(w *Worker) {
 	sigs := make(chan os.Signal, 1)
 	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
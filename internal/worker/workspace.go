@@ -0,0 +1,131 @@
+package worker
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+
+	"github.com/dheeraj-sn/distributed-orchestrator/internal/models"
+)
+
+// InputOpener opens the file a job input refers to, returning its content
+// and the hex SHA-256 it is expected to have.
+type InputOpener func(ctx context.Context, in models.JobInput) (io.ReadCloser, string, error)
+
+// Workspaces runs jobs in per-job working directories under Root. Before a
+// job runs its inputs are staged into the directory; after it succeeds its
+// declared outputs are saved as artifacts. The directory is removed once
+// the job finishes.
+type Workspaces struct {
+	Root string
+	Open InputOpener
+}
+
+// Execute runs job through e in a fresh working directory, setting job.Dir.
+// A declared output the job did not produce fails it.
+func (ws *Workspaces) Execute(ctx context.Context, e Executor, job *Job, attempt int) (*Result, error) {
+	dir := filepath.Join(ws.Root, job.ID+"-"+strconv.Itoa(attempt))
+	// A directory left by a crashed run of this attempt holds nothing the
+	// job can rely on.
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, fmt.Errorf("clear working directory: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create working directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, in := range job.Inputs {
+		if err := ws.stage(ctx, dir, in); err != nil {
+			return nil, fmt.Errorf("stage input %q: %w", in.Path, err)
+		}
+	}
+	job.Dir = dir
+	res, err := e.Execute(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+	if err := collect(dir, job); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// stage copies an input into dir, checking it against its checksum.
+func (ws *Workspaces) stage(ctx context.Context, dir string, in models.JobInput) error {
+	body, sum, err := ws.Open(ctx, in)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	dst := filepath.Join(dir, filepath.FromSlash(in.Path))
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(f, hash), body); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if got := hex.EncodeToString(hash.Sum(nil)); sum != "" && got != sum {
+		return fmt.Errorf("checksum mismatch: got %s, want %s", got, sum)
+	}
+	return nil
+}
+
+// collect saves the job's declared outputs as artifacts. Outputs are opened
+// through an os.Root so that a symlink left by the job cannot expose files
+// outside dir.
+func collect(dir string, job *Job) error {
+	if len(job.Outputs) == 0 {
+		return nil
+	}
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return err
+	}
+	defer root.Close()
+
+	for _, out := range job.Outputs {
+		if err := saveOutput(root, job, out); err != nil {
+			return fmt.Errorf("collect output %q: %w", out.Path, err)
+		}
+	}
+	return nil
+}
+
+func saveOutput(root *os.Root, job *Job, out models.JobOutput) error {
+	f, err := root.Open(filepath.FromSlash(out.Path))
+	if errors.Is(err, fs.ErrNotExist) {
+		return errors.New("the job did not produce it")
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return errors.New("not a regular file")
+	}
+	return job.SaveArtifact(out.ArtifactName(), mime.TypeByExtension(path.Ext(out.Path)), f)
+}
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS jobs_status_idx;
+
+ALTER TABLE jobs
+    DROP COLUMN IF EXISTS error,
+    DROP COLUMN IF EXISTS exit_code,
+    DROP COLUMN IF EXISTS attempts,
+    DROP COLUMN IF EXISTS worker_id,
+    DROP COLUMN IF EXISTS queued_at,
+    DROP COLUMN IF EXISTS started_at,
+    DROP COLUMN IF EXISTS completed_at,
+    DROP COLUMN IF EXISTS version;
\ No newline at end of file
@@ -0,0 +1,11 @@
+ALTER TABLE jobs
+    ADD COLUMN error TEXT,
+    ADD COLUMN exit_code INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN attempts INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN worker_id TEXT,
+    ADD COLUMN queued_at TIMESTAMP,
+    ADD COLUMN started_at TIMESTAMP,
+    ADD COLUMN completed_at TIMESTAMP,
+    ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+CREATE INDEX jobs_status_idx ON jobs (status);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS job_transitions;
\ No newline at end of file
@@ -0,0 +1,10 @@
+CREATE TABLE job_transitions (
+    job_id UUID NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    seq INTEGER NOT NULL,
+    from_status TEXT,
+    to_status TEXT NOT NULL,
+    actor TEXT NOT NULL,
+    reason TEXT,
+    at TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (job_id, seq)
+);
\ No newline at end of file
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS jobs_lease_idx;
+DROP INDEX IF EXISTS jobs_claim_idx;
+
+ALTER TABLE jobs
+    DROP COLUMN IF EXISTS lease_expires_at,
+    DROP COLUMN IF EXISTS priority;
\ No newline at end of file
@@ -0,0 +1,9 @@
+ALTER TABLE jobs
+    ADD COLUMN priority INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN lease_expires_at TIMESTAMP;
+
+-- Claim order for FOR UPDATE SKIP LOCKED dispatch
+CREATE INDEX jobs_claim_idx ON jobs (priority DESC, created_at) WHERE status = 'queued';
+
+-- Lease reaper scan
+CREATE INDEX jobs_lease_idx ON jobs (lease_expires_at) WHERE status IN ('leased', 'running');
\ No newline at end of file
@@ -0,0 +1,7 @@
+CREATE TABLE job_logs (
+    job_id UUID NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    seq BIGINT NOT NULL,
+    at TIMESTAMP NOT NULL DEFAULT now(),
+    message TEXT NOT NULL,
+    PRIMARY KEY (job_id, seq)
+);
\ No newline at end of file
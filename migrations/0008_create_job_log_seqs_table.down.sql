@@ -0,0 +1 @@
+DROP TABLE IF EXISTS job_log_seqs;
\ No newline at end of file
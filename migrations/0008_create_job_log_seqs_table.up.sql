@@ -0,0 +1,8 @@
+CREATE TABLE job_log_seqs (
+    job_id UUID PRIMARY KEY REFERENCES jobs(id) ON DELETE CASCADE,
+    last_seq BIGINT NOT NULL
+);
+
+-- Continue numbering from lines already stored
+INSERT INTO job_log_seqs (job_id, last_seq)
+SELECT job_id, MAX(seq) FROM job_logs GROUP BY job_id;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE jobs
+    DROP COLUMN IF EXISTS outputs,
+    DROP COLUMN IF EXISTS inputs;
\ No newline at end of file
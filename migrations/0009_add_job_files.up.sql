@@ -0,0 +1,3 @@
+ALTER TABLE jobs
+    ADD COLUMN inputs JSONB,
+    ADD COLUMN outputs JSONB;
\ No newline at end of file
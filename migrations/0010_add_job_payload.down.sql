@@ -0,0 +1,3 @@
+ALTER TABLE jobs
+    DROP COLUMN IF EXISTS output,
+    DROP COLUMN IF EXISTS payload;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE jobs
+    ADD COLUMN payload JSONB,
+    ADD COLUMN output JSONB;
\ No newline at end of file
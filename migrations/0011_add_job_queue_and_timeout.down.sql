@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS jobs_queue_idx;
+
+ALTER TABLE jobs
+    DROP COLUMN IF EXISTS timeout,
+    DROP COLUMN IF EXISTS queue;
\ No newline at end of file
@@ -0,0 +1,5 @@
+ALTER TABLE jobs
+    ADD COLUMN queue TEXT NOT NULL DEFAULT 'default',
+    ADD COLUMN timeout BIGINT NOT NULL DEFAULT 0;
+
+CREATE INDEX jobs_queue_idx ON jobs (queue);
\ No newline at end of file
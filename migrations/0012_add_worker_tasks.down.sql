@@ -0,0 +1,3 @@
+ALTER TABLE workers
+    DROP COLUMN IF EXISTS any_task,
+    DROP COLUMN IF EXISTS tasks;
\ No newline at end of file
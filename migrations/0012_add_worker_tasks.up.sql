@@ -0,0 +1,4 @@
+ALTER TABLE workers
+    ADD COLUMN tasks JSONB,
+    -- Workers registered before capabilities existed run any task.
+    ADD COLUMN any_task BOOLEAN NOT NULL DEFAULT true;
\ No newline at end of file
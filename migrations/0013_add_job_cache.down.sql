@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS jobs_cache_key_idx;
+
+ALTER TABLE jobs
+    DROP COLUMN IF EXISTS cached_from,
+    DROP COLUMN IF EXISTS cache_key;
\ No newline at end of file
@@ -0,0 +1,5 @@
+ALTER TABLE jobs
+    ADD COLUMN cache_key TEXT NOT NULL DEFAULT '',
+    ADD COLUMN cached_from TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX jobs_cache_key_idx ON jobs (cache_key) WHERE cache_key <> '';
\ No newline at end of file
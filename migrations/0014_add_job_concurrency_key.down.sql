@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS jobs_concurrency_key_idx;
+
+ALTER TABLE jobs
+    DROP COLUMN IF EXISTS concurrency_limit,
+    DROP COLUMN IF EXISTS concurrency_key;
\ No newline at end of file
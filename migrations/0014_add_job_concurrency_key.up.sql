@@ -0,0 +1,5 @@
+ALTER TABLE jobs
+    ADD COLUMN concurrency_key TEXT NOT NULL DEFAULT '',
+    ADD COLUMN concurrency_limit INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX jobs_concurrency_key_idx ON jobs (concurrency_key, status) WHERE concurrency_key <> '';
\ No newline at end of file
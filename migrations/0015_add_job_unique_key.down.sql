@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS jobs_unique_key_idx;
+
+ALTER TABLE jobs
+    DROP COLUMN IF EXISTS unique_key;
\ No newline at end of file
@@ -0,0 +1,4 @@
+ALTER TABLE jobs
+    ADD COLUMN unique_key TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX jobs_unique_key_idx ON jobs (task, unique_key) WHERE unique_key <> '';
\ No newline at end of file
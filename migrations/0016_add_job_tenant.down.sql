@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS jobs_tenant_idx;
+
+ALTER TABLE jobs
+    DROP COLUMN IF EXISTS tenant;
\ No newline at end of file
@@ -0,0 +1,4 @@
+ALTER TABLE jobs
+    ADD COLUMN tenant TEXT NOT NULL DEFAULT 'default';
+
+CREATE INDEX jobs_tenant_idx ON jobs (status, tenant);
\ No newline at end of file
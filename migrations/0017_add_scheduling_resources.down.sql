@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS jobs_claim_age_idx;
+
+ALTER TABLE workers
+    DROP COLUMN IF EXISTS capacity_memory_mb,
+    DROP COLUMN IF EXISTS capacity_cpu;
+
+ALTER TABLE jobs
+    DROP COLUMN IF EXISTS memory_mb,
+    DROP COLUMN IF EXISTS cpu;
\ No newline at end of file
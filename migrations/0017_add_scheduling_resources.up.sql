@@ -0,0 +1,10 @@
+ALTER TABLE jobs
+    ADD COLUMN cpu DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN memory_mb BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE workers
+    ADD COLUMN capacity_cpu DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN capacity_memory_mb BIGINT NOT NULL DEFAULT 0;
+
+-- Claim order of the fifo scheduling policy.
+CREATE INDEX jobs_claim_age_idx ON jobs (created_at) WHERE status = 'queued';
\ No newline at end of file
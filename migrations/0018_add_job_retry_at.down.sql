@@ -0,0 +1,2 @@
+ALTER TABLE jobs
+    DROP COLUMN IF EXISTS retry_at;
\ No newline at end of file
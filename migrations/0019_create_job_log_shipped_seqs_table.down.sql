@@ -0,0 +1 @@
+DROP TABLE IF EXISTS job_log_shipped_seqs;
\ No newline at end of file
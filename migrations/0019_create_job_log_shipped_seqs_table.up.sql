@@ -0,0 +1,6 @@
+CREATE TABLE job_log_shipped_seqs (
+    job_id UUID NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    attempt INT NOT NULL,
+    ship_seq BIGINT NOT NULL,
+    PRIMARY KEY (job_id, attempt)
+);
\ No newline at end of file
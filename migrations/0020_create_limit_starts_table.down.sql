@@ -0,0 +1 @@
+DROP TABLE IF EXISTS limit_starts;
\ No newline at end of file
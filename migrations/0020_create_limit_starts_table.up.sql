@@ -0,0 +1,6 @@
+CREATE TABLE limit_starts (
+    kind TEXT NOT NULL,
+    name TEXT NOT NULL,
+    paid_off TIMESTAMP NOT NULL,
+    PRIMARY KEY (kind, name)
+);
\ No newline at end of file
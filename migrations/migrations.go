@@ -0,0 +1,11 @@
+// Package migrations embeds the SQL schema migrations so the scheduler can
+// apply them itself. Files are named <version>_<name>.up.sql and
+// <version>_<name>.down.sql.
+package migrations
+
+import "embed"
+
+// FS holds every migration file.
+//
+//go:embed *.sql
+var FS embed.FS
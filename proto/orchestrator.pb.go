@@ -9,6 +9,9 @@ package proto
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -21,11 +24,81 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type UniqueScope int32
+
+const (
+	UniqueScope_UNIQUE_SCOPE_QUEUED UniqueScope = 0 // a matching job is waiting to run
+	UniqueScope_UNIQUE_SCOPE_ACTIVE UniqueScope = 1 // a matching job is waiting to run or running
+	UniqueScope_UNIQUE_SCOPE_WINDOW UniqueScope = 2 // a matching job was submitted within the window
+)
+
+// Enum value maps for UniqueScope.
+var (
+	UniqueScope_name = map[int32]string{
+		0: "UNIQUE_SCOPE_QUEUED",
+		1: "UNIQUE_SCOPE_ACTIVE",
+		2: "UNIQUE_SCOPE_WINDOW",
+	}
+	UniqueScope_value = map[string]int32{
+		"UNIQUE_SCOPE_QUEUED": 0,
+		"UNIQUE_SCOPE_ACTIVE": 1,
+		"UNIQUE_SCOPE_WINDOW": 2,
+	}
+)
+
+func (x UniqueScope) Enum() *UniqueScope {
+	p := new(UniqueScope)
+	*p = x
+	return p
+}
+
+func (x UniqueScope) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (UniqueScope) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_orchestrator_proto_enumTypes[0].Descriptor()
+}
+
+func (UniqueScope) Type() protoreflect.EnumType {
+	return &file_proto_orchestrator_proto_enumTypes[0]
+}
+
+func (x UniqueScope) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use UniqueScope.Descriptor instead.
+func (UniqueScope) EnumDescriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{0}
+}
+
 // A job submitted by a client
 type JobRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Task          string                 `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
-	Args          []string               `protobuf:"bytes,2,rep,name=args,proto3" json:"args,omitempty"`
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Task     string                 `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	Args     []string               `protobuf:"bytes,2,rep,name=args,proto3" json:"args,omitempty"`
+	Priority int32                  `protobuf:"varint,3,opt,name=priority,proto3" json:"priority,omitempty"` // higher runs first; default 0
+	Inputs   []*JobInput            `protobuf:"bytes,4,rep,name=inputs,proto3" json:"inputs,omitempty"`
+	Outputs  []*JobOutput           `protobuf:"bytes,5,rep,name=outputs,proto3" json:"outputs,omitempty"`
+	Payload  *structpb.Struct       `protobuf:"bytes,6,opt,name=payload,proto3" json:"payload,omitempty"` // structured input, passed to the executor
+	Timeout  *durationpb.Duration   `protobuf:"bytes,7,opt,name=timeout,proto3" json:"timeout,omitempty"` // overrides the task type's default
+	// Reuse the result of an earlier job with the same task, task version,
+	// args and payload; defaults to the task type's setting.
+	Cacheable *bool `protobuf:"varint,8,opt,name=cacheable,proto3,oneof" json:"cacheable,omitempty"`
+	// At most concurrency_limit jobs (default 1) with the same
+	// concurrency_key run at once, e.g. one job per customer.
+	ConcurrencyKey   string `protobuf:"bytes,9,opt,name=concurrency_key,json=concurrencyKey,proto3" json:"concurrency_key,omitempty"`
+	ConcurrencyLimit int32  `protobuf:"varint,10,opt,name=concurrency_limit,json=concurrencyLimit,proto3" json:"concurrency_limit,omitempty"`
+	// Submit the job only if no matching job exists; otherwise the existing
+	// job's ID is returned.
+	Unique *UniquePolicy `protobuf:"bytes,11,opt,name=unique,proto3" json:"unique,omitempty"`
+	// Who the job is run for; with fair share enabled, tenants are served in
+	// proportion to their weights. Defaults to "default".
+	Tenant string `protobuf:"bytes,12,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	// What the job needs of a worker; the bin_packing scheduling policy
+	// places jobs by it.
+	Resources     *Resources `protobuf:"bytes,13,opt,name=resources,proto3" json:"resources,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -74,28 +147,107 @@ func (x *JobRequest) GetArgs() []string {
 	return nil
 }
 
-// Job ID response
-type JobResponse struct {
+func (x *JobRequest) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *JobRequest) GetInputs() []*JobInput {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
+
+func (x *JobRequest) GetOutputs() []*JobOutput {
+	if x != nil {
+		return x.Outputs
+	}
+	return nil
+}
+
+func (x *JobRequest) GetPayload() *structpb.Struct {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *JobRequest) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+func (x *JobRequest) GetCacheable() bool {
+	if x != nil && x.Cacheable != nil {
+		return *x.Cacheable
+	}
+	return false
+}
+
+func (x *JobRequest) GetConcurrencyKey() string {
+	if x != nil {
+		return x.ConcurrencyKey
+	}
+	return ""
+}
+
+func (x *JobRequest) GetConcurrencyLimit() int32 {
+	if x != nil {
+		return x.ConcurrencyLimit
+	}
+	return 0
+}
+
+func (x *JobRequest) GetUnique() *UniquePolicy {
+	if x != nil {
+		return x.Unique
+	}
+	return nil
+}
+
+func (x *JobRequest) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+func (x *JobRequest) GetResources() *Resources {
+	if x != nil {
+		return x.Resources
+	}
+	return nil
+}
+
+// An amount of compute a job requests or a worker offers. Zero fields are
+// unset: nothing is requested, or there is no limit.
+type Resources struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Cpu           float64                `protobuf:"fixed64,1,opt,name=cpu,proto3" json:"cpu,omitempty"` // cores
+	MemoryMb      int64                  `protobuf:"varint,2,opt,name=memory_mb,json=memoryMb,proto3" json:"memory_mb,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *JobResponse) Reset() {
-	*x = JobResponse{}
+func (x *Resources) Reset() {
+	*x = Resources{}
 	mi := &file_proto_orchestrator_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *JobResponse) String() string {
+func (x *Resources) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*JobResponse) ProtoMessage() {}
+func (*Resources) ProtoMessage() {}
 
-func (x *JobResponse) ProtoReflect() protoreflect.Message {
+func (x *Resources) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_orchestrator_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -107,40 +259,50 @@ func (x *JobResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use JobResponse.ProtoReflect.Descriptor instead.
-func (*JobResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use Resources.ProtoReflect.Descriptor instead.
+func (*Resources) Descriptor() ([]byte, []int) {
 	return file_proto_orchestrator_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *JobResponse) GetJobId() string {
+func (x *Resources) GetCpu() float64 {
 	if x != nil {
-		return x.JobId
+		return x.Cpu
 	}
-	return ""
+	return 0
 }
 
-// Job status query
-type JobStatusRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+func (x *Resources) GetMemoryMb() int64 {
+	if x != nil {
+		return x.MemoryMb
+	}
+	return 0
+}
+
+type UniquePolicy struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Jobs of the same task with the same key match; defaults to a key
+	// derived from the args and payload.
+	Key           string               `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Scope         UniqueScope          `protobuf:"varint,2,opt,name=scope,proto3,enum=orchestrator.UniqueScope" json:"scope,omitempty"`
+	Window        *durationpb.Duration `protobuf:"bytes,3,opt,name=window,proto3" json:"window,omitempty"` // for UNIQUE_SCOPE_WINDOW
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *JobStatusRequest) Reset() {
-	*x = JobStatusRequest{}
+func (x *UniquePolicy) Reset() {
+	*x = UniquePolicy{}
 	mi := &file_proto_orchestrator_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *JobStatusRequest) String() string {
+func (x *UniquePolicy) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*JobStatusRequest) ProtoMessage() {}
+func (*UniquePolicy) ProtoMessage() {}
 
-func (x *JobStatusRequest) ProtoReflect() protoreflect.Message {
+func (x *UniquePolicy) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_orchestrator_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -152,40 +314,59 @@ func (x *JobStatusRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use JobStatusRequest.ProtoReflect.Descriptor instead.
-func (*JobStatusRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use UniquePolicy.ProtoReflect.Descriptor instead.
+func (*UniquePolicy) Descriptor() ([]byte, []int) {
 	return file_proto_orchestrator_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *JobStatusRequest) GetJobId() string {
+func (x *UniquePolicy) GetKey() string {
 	if x != nil {
-		return x.JobId
+		return x.Key
 	}
 	return ""
 }
 
-type JobStatusResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
-	Result        string                 `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"` // new: optional job result
+func (x *UniquePolicy) GetScope() UniqueScope {
+	if x != nil {
+		return x.Scope
+	}
+	return UniqueScope_UNIQUE_SCOPE_QUEUED
+}
+
+func (x *UniquePolicy) GetWindow() *durationpb.Duration {
+	if x != nil {
+		return x.Window
+	}
+	return nil
+}
+
+// A file staged into the job's working directory before it runs
+type JobInput struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Path  string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"` // relative to the working directory
+	// Types that are valid to be assigned to Source:
+	//
+	//	*JobInput_UploadId
+	//	*JobInput_Artifact
+	Source        isJobInput_Source `protobuf_oneof:"source"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *JobStatusResponse) Reset() {
-	*x = JobStatusResponse{}
+func (x *JobInput) Reset() {
+	*x = JobInput{}
 	mi := &file_proto_orchestrator_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *JobStatusResponse) String() string {
+func (x *JobInput) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*JobStatusResponse) ProtoMessage() {}
+func (*JobInput) ProtoMessage() {}
 
-func (x *JobStatusResponse) ProtoReflect() protoreflect.Message {
+func (x *JobInput) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_orchestrator_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -197,48 +378,81 @@ func (x *JobStatusResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use JobStatusResponse.ProtoReflect.Descriptor instead.
-func (*JobStatusResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use JobInput.ProtoReflect.Descriptor instead.
+func (*JobInput) Descriptor() ([]byte, []int) {
 	return file_proto_orchestrator_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *JobStatusResponse) GetStatus() string {
+func (x *JobInput) GetPath() string {
 	if x != nil {
-		return x.Status
+		return x.Path
 	}
 	return ""
 }
 
-func (x *JobStatusResponse) GetResult() string {
+func (x *JobInput) GetSource() isJobInput_Source {
 	if x != nil {
-		return x.Result
+		return x.Source
+	}
+	return nil
+}
+
+func (x *JobInput) GetUploadId() string {
+	if x != nil {
+		if x, ok := x.Source.(*JobInput_UploadId); ok {
+			return x.UploadId
+		}
 	}
 	return ""
 }
 
-// Worker registration
-type RegisterWorkerRequest struct {
+func (x *JobInput) GetArtifact() *ArtifactRef {
+	if x != nil {
+		if x, ok := x.Source.(*JobInput_Artifact); ok {
+			return x.Artifact
+		}
+	}
+	return nil
+}
+
+type isJobInput_Source interface {
+	isJobInput_Source()
+}
+
+type JobInput_UploadId struct {
+	UploadId string `protobuf:"bytes,2,opt,name=upload_id,json=uploadId,proto3,oneof"` // from UploadInput
+}
+
+type JobInput_Artifact struct {
+	Artifact *ArtifactRef `protobuf:"bytes,3,opt,name=artifact,proto3,oneof"`
+}
+
+func (*JobInput_UploadId) isJobInput_Source() {}
+
+func (*JobInput_Artifact) isJobInput_Source() {}
+
+type ArtifactRef struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	WorkerId      string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
-	Host          string                 `protobuf:"bytes,2,opt,name=host,proto3" json:"host,omitempty"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RegisterWorkerRequest) Reset() {
-	*x = RegisterWorkerRequest{}
+func (x *ArtifactRef) Reset() {
+	*x = ArtifactRef{}
 	mi := &file_proto_orchestrator_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RegisterWorkerRequest) String() string {
+func (x *ArtifactRef) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RegisterWorkerRequest) ProtoMessage() {}
+func (*ArtifactRef) ProtoMessage() {}
 
-func (x *RegisterWorkerRequest) ProtoReflect() protoreflect.Message {
+func (x *ArtifactRef) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_orchestrator_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -250,46 +464,49 @@ func (x *RegisterWorkerRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RegisterWorkerRequest.ProtoReflect.Descriptor instead.
-func (*RegisterWorkerRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ArtifactRef.ProtoReflect.Descriptor instead.
+func (*ArtifactRef) Descriptor() ([]byte, []int) {
 	return file_proto_orchestrator_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *RegisterWorkerRequest) GetWorkerId() string {
+func (x *ArtifactRef) GetJobId() string {
 	if x != nil {
-		return x.WorkerId
+		return x.JobId
 	}
 	return ""
 }
 
-func (x *RegisterWorkerRequest) GetHost() string {
+func (x *ArtifactRef) GetName() string {
 	if x != nil {
-		return x.Host
+		return x.Name
 	}
 	return ""
 }
 
-type RegisterWorkerResponse struct {
+// A file collected from the job's working directory as an artifact once
+// the job succeeds
+type JobOutput struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Artifact      string                 `protobuf:"bytes,2,opt,name=artifact,proto3" json:"artifact,omitempty"` // defaults to the path's base name
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RegisterWorkerResponse) Reset() {
-	*x = RegisterWorkerResponse{}
+func (x *JobOutput) Reset() {
+	*x = JobOutput{}
 	mi := &file_proto_orchestrator_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RegisterWorkerResponse) String() string {
+func (x *JobOutput) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RegisterWorkerResponse) ProtoMessage() {}
+func (*JobOutput) ProtoMessage() {}
 
-func (x *RegisterWorkerResponse) ProtoReflect() protoreflect.Message {
+func (x *JobOutput) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_orchestrator_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -301,40 +518,49 @@ func (x *RegisterWorkerResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RegisterWorkerResponse.ProtoReflect.Descriptor instead.
-func (*RegisterWorkerResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use JobOutput.ProtoReflect.Descriptor instead.
+func (*JobOutput) Descriptor() ([]byte, []int) {
 	return file_proto_orchestrator_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *RegisterWorkerResponse) GetSuccess() bool {
+func (x *JobOutput) GetPath() string {
 	if x != nil {
-		return x.Success
+		return x.Path
 	}
-	return false
+	return ""
 }
 
-// Heartbeat
-type HeartbeatRequest struct {
+func (x *JobOutput) GetArtifact() string {
+	if x != nil {
+		return x.Artifact
+	}
+	return ""
+}
+
+// Job ID response
+type JobResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	WorkerId      string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	CacheHit      bool                   `protobuf:"varint,2,opt,name=cache_hit,json=cacheHit,proto3" json:"cache_hit,omitempty"` // the job finished at once with a cached result
+	Duplicate     bool                   `protobuf:"varint,3,opt,name=duplicate,proto3" json:"duplicate,omitempty"`               // job_id is an existing job that matched the unique policy
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *HeartbeatRequest) Reset() {
-	*x = HeartbeatRequest{}
+func (x *JobResponse) Reset() {
+	*x = JobResponse{}
 	mi := &file_proto_orchestrator_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *HeartbeatRequest) String() string {
+func (x *JobResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HeartbeatRequest) ProtoMessage() {}
+func (*JobResponse) ProtoMessage() {}
 
-func (x *HeartbeatRequest) ProtoReflect() protoreflect.Message {
+func (x *JobResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_orchestrator_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -346,39 +572,54 @@ func (x *HeartbeatRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HeartbeatRequest.ProtoReflect.Descriptor instead.
-func (*HeartbeatRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use JobResponse.ProtoReflect.Descriptor instead.
+func (*JobResponse) Descriptor() ([]byte, []int) {
 	return file_proto_orchestrator_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *HeartbeatRequest) GetWorkerId() string {
+func (x *JobResponse) GetJobId() string {
 	if x != nil {
-		return x.WorkerId
+		return x.JobId
 	}
 	return ""
 }
 
-type HeartbeatResponse struct {
+func (x *JobResponse) GetCacheHit() bool {
+	if x != nil {
+		return x.CacheHit
+	}
+	return false
+}
+
+func (x *JobResponse) GetDuplicate() bool {
+	if x != nil {
+		return x.Duplicate
+	}
+	return false
+}
+
+// Job status query
+type JobStatusRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Alive         bool                   `protobuf:"varint,1,opt,name=alive,proto3" json:"alive,omitempty"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *HeartbeatResponse) Reset() {
-	*x = HeartbeatResponse{}
+func (x *JobStatusRequest) Reset() {
+	*x = JobStatusRequest{}
 	mi := &file_proto_orchestrator_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *HeartbeatResponse) String() string {
+func (x *JobStatusRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HeartbeatResponse) ProtoMessage() {}
+func (*JobStatusRequest) ProtoMessage() {}
 
-func (x *HeartbeatResponse) ProtoReflect() protoreflect.Message {
+func (x *JobStatusRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_orchestrator_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -390,41 +631,2558 @@ func (x *HeartbeatResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HeartbeatResponse.ProtoReflect.Descriptor instead.
-func (*HeartbeatResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use JobStatusRequest.ProtoReflect.Descriptor instead.
+func (*JobStatusRequest) Descriptor() ([]byte, []int) {
 	return file_proto_orchestrator_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *HeartbeatResponse) GetAlive() bool {
+func (x *JobStatusRequest) GetJobId() string {
 	if x != nil {
-		return x.Alive
+		return x.JobId
 	}
-	return false
+	return ""
 }
 
-// New: Job pulling
-type PullJobRequest struct {
+type JobStatusResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	WorkerId      string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Result        string                 `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"` // new: optional job result
+	Details       *JobDetails            `protobuf:"bytes,3,opt,name=details,proto3" json:"details,omitempty"`
+	Output        *structpb.Struct       `protobuf:"bytes,4,opt,name=output,proto3" json:"output,omitempty"` // structured result, if the executor returned one
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PullJobRequest) Reset() {
-	*x = PullJobRequest{}
+func (x *JobStatusResponse) Reset() {
+	*x = JobStatusResponse{}
 	mi := &file_proto_orchestrator_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PullJobRequest) String() string {
+func (x *JobStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobStatusResponse) ProtoMessage() {}
+
+func (x *JobStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobStatusResponse.ProtoReflect.Descriptor instead.
+func (*JobStatusResponse) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *JobStatusResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *JobStatusResponse) GetResult() string {
+	if x != nil {
+		return x.Result
+	}
+	return ""
+}
+
+func (x *JobStatusResponse) GetDetails() *JobDetails {
+	if x != nil {
+		return x.Details
+	}
+	return nil
+}
+
+func (x *JobStatusResponse) GetOutput() *structpb.Struct {
+	if x != nil {
+		return x.Output
+	}
+	return nil
+}
+
+// Execution metadata for a job. Timestamps and durations describe the
+// current (or last) attempt and are unset until reached.
+type JobDetails struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Task             string                 `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	Args             []string               `protobuf:"bytes,2,rep,name=args,proto3" json:"args,omitempty"`
+	CreatedAt        *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	QueuedAt         *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=queued_at,json=queuedAt,proto3" json:"queued_at,omitempty"`
+	StartedAt        *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	FinishedAt       *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	WorkerId         string                 `protobuf:"bytes,7,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Attempt          int32                  `protobuf:"varint,8,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	ExitCode         int32                  `protobuf:"varint,9,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
+	Error            string                 `protobuf:"bytes,10,opt,name=error,proto3" json:"error,omitempty"`
+	QueueDuration    *durationpb.Duration   `protobuf:"bytes,11,opt,name=queue_duration,json=queueDuration,proto3" json:"queue_duration,omitempty"`
+	RunDuration      *durationpb.Duration   `protobuf:"bytes,12,opt,name=run_duration,json=runDuration,proto3" json:"run_duration,omitempty"`
+	Priority         int32                  `protobuf:"varint,13,opt,name=priority,proto3" json:"priority,omitempty"`
+	LeaseExpiresAt   *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=lease_expires_at,json=leaseExpiresAt,proto3" json:"lease_expires_at,omitempty"`
+	Inputs           []*JobInput            `protobuf:"bytes,15,rep,name=inputs,proto3" json:"inputs,omitempty"`
+	Outputs          []*JobOutput           `protobuf:"bytes,16,rep,name=outputs,proto3" json:"outputs,omitempty"`
+	Payload          *structpb.Struct       `protobuf:"bytes,17,opt,name=payload,proto3" json:"payload,omitempty"`
+	Queue            string                 `protobuf:"bytes,18,opt,name=queue,proto3" json:"queue,omitempty"`
+	Timeout          *durationpb.Duration   `protobuf:"bytes,19,opt,name=timeout,proto3" json:"timeout,omitempty"`                                  // unset if the job may run indefinitely
+	WaitingReason    string                 `protobuf:"bytes,20,opt,name=waiting_reason,json=waitingReason,proto3" json:"waiting_reason,omitempty"` // why a queued job has not been dispatched, if known
+	CacheKey         string                 `protobuf:"bytes,21,opt,name=cache_key,json=cacheKey,proto3" json:"cache_key,omitempty"`                // set for cacheable jobs
+	CacheHit         bool                   `protobuf:"varint,22,opt,name=cache_hit,json=cacheHit,proto3" json:"cache_hit,omitempty"`               // the job reused a cached result instead of running
+	CachedFrom       string                 `protobuf:"bytes,23,opt,name=cached_from,json=cachedFrom,proto3" json:"cached_from,omitempty"`          // the job whose result was reused
+	ConcurrencyKey   string                 `protobuf:"bytes,24,opt,name=concurrency_key,json=concurrencyKey,proto3" json:"concurrency_key,omitempty"`
+	ConcurrencyLimit int32                  `protobuf:"varint,25,opt,name=concurrency_limit,json=concurrencyLimit,proto3" json:"concurrency_limit,omitempty"` // 0 means 1
+	UniqueKey        string                 `protobuf:"bytes,26,opt,name=unique_key,json=uniqueKey,proto3" json:"unique_key,omitempty"`                       // set for jobs submitted as unique
+	Tenant           string                 `protobuf:"bytes,27,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	Resources        *Resources             `protobuf:"bytes,28,opt,name=resources,proto3" json:"resources,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *JobDetails) Reset() {
+	*x = JobDetails{}
+	mi := &file_proto_orchestrator_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobDetails) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobDetails) ProtoMessage() {}
+
+func (x *JobDetails) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobDetails.ProtoReflect.Descriptor instead.
+func (*JobDetails) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *JobDetails) GetTask() string {
+	if x != nil {
+		return x.Task
+	}
+	return ""
+}
+
+func (x *JobDetails) GetArgs() []string {
+	if x != nil {
+		return x.Args
+	}
+	return nil
+}
+
+func (x *JobDetails) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *JobDetails) GetQueuedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.QueuedAt
+	}
+	return nil
+}
+
+func (x *JobDetails) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *JobDetails) GetFinishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return nil
+}
+
+func (x *JobDetails) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *JobDetails) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *JobDetails) GetExitCode() int32 {
+	if x != nil {
+		return x.ExitCode
+	}
+	return 0
+}
+
+func (x *JobDetails) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *JobDetails) GetQueueDuration() *durationpb.Duration {
+	if x != nil {
+		return x.QueueDuration
+	}
+	return nil
+}
+
+func (x *JobDetails) GetRunDuration() *durationpb.Duration {
+	if x != nil {
+		return x.RunDuration
+	}
+	return nil
+}
+
+func (x *JobDetails) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *JobDetails) GetLeaseExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LeaseExpiresAt
+	}
+	return nil
+}
+
+func (x *JobDetails) GetInputs() []*JobInput {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
+
+func (x *JobDetails) GetOutputs() []*JobOutput {
+	if x != nil {
+		return x.Outputs
+	}
+	return nil
+}
+
+func (x *JobDetails) GetPayload() *structpb.Struct {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *JobDetails) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+func (x *JobDetails) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+func (x *JobDetails) GetWaitingReason() string {
+	if x != nil {
+		return x.WaitingReason
+	}
+	return ""
+}
+
+func (x *JobDetails) GetCacheKey() string {
+	if x != nil {
+		return x.CacheKey
+	}
+	return ""
+}
+
+func (x *JobDetails) GetCacheHit() bool {
+	if x != nil {
+		return x.CacheHit
+	}
+	return false
+}
+
+func (x *JobDetails) GetCachedFrom() string {
+	if x != nil {
+		return x.CachedFrom
+	}
+	return ""
+}
+
+func (x *JobDetails) GetConcurrencyKey() string {
+	if x != nil {
+		return x.ConcurrencyKey
+	}
+	return ""
+}
+
+func (x *JobDetails) GetConcurrencyLimit() int32 {
+	if x != nil {
+		return x.ConcurrencyLimit
+	}
+	return 0
+}
+
+func (x *JobDetails) GetUniqueKey() string {
+	if x != nil {
+		return x.UniqueKey
+	}
+	return ""
+}
+
+func (x *JobDetails) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+func (x *JobDetails) GetResources() *Resources {
+	if x != nil {
+		return x.Resources
+	}
+	return nil
+}
+
+// Worker registration
+type RegisterWorkerRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	WorkerId string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Host     string                 `protobuf:"bytes,2,opt,name=host,proto3" json:"host,omitempty"`
+	// The task types the worker has executors for. Only jobs of these types
+	// are handed to it, unless any_task is set because it also runs tasks it
+	// does not list. A worker advertising nothing is assumed to run any task.
+	Tasks   []*TaskCapability `protobuf:"bytes,3,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	AnyTask bool              `protobuf:"varint,4,opt,name=any_task,json=anyTask,proto3" json:"any_task,omitempty"`
+	// What the worker offers to the jobs it runs at once, for the
+	// bin_packing scheduling policy.
+	Capacity      *Resources `protobuf:"bytes,5,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterWorkerRequest) Reset() {
+	*x = RegisterWorkerRequest{}
+	mi := &file_proto_orchestrator_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterWorkerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterWorkerRequest) ProtoMessage() {}
+
+func (x *RegisterWorkerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterWorkerRequest.ProtoReflect.Descriptor instead.
+func (*RegisterWorkerRequest) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *RegisterWorkerRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *RegisterWorkerRequest) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *RegisterWorkerRequest) GetTasks() []*TaskCapability {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+func (x *RegisterWorkerRequest) GetAnyTask() bool {
+	if x != nil {
+		return x.AnyTask
+	}
+	return false
+}
+
+func (x *RegisterWorkerRequest) GetCapacity() *Resources {
+	if x != nil {
+		return x.Capacity
+	}
+	return nil
+}
+
+type TaskCapability struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Task          string                 `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"` // executor version, if it reports one
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskCapability) Reset() {
+	*x = TaskCapability{}
+	mi := &file_proto_orchestrator_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskCapability) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskCapability) ProtoMessage() {}
+
+func (x *TaskCapability) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskCapability.ProtoReflect.Descriptor instead.
+func (*TaskCapability) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *TaskCapability) GetTask() string {
+	if x != nil {
+		return x.Task
+	}
+	return ""
+}
+
+func (x *TaskCapability) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+type RegisterWorkerResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterWorkerResponse) Reset() {
+	*x = RegisterWorkerResponse{}
+	mi := &file_proto_orchestrator_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterWorkerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterWorkerResponse) ProtoMessage() {}
+
+func (x *RegisterWorkerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterWorkerResponse.ProtoReflect.Descriptor instead.
+func (*RegisterWorkerResponse) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RegisterWorkerResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+// Heartbeat
+type HeartbeatRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkerId      string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeartbeatRequest) Reset() {
+	*x = HeartbeatRequest{}
+	mi := &file_proto_orchestrator_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeartbeatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeartbeatRequest) ProtoMessage() {}
+
+func (x *HeartbeatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeartbeatRequest.ProtoReflect.Descriptor instead.
+func (*HeartbeatRequest) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *HeartbeatRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+type HeartbeatResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Alive         bool                   `protobuf:"varint,1,opt,name=alive,proto3" json:"alive,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeartbeatResponse) Reset() {
+	*x = HeartbeatResponse{}
+	mi := &file_proto_orchestrator_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeartbeatResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeartbeatResponse) ProtoMessage() {}
+
+func (x *HeartbeatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeartbeatResponse.ProtoReflect.Descriptor instead.
+func (*HeartbeatResponse) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *HeartbeatResponse) GetAlive() bool {
+	if x != nil {
+		return x.Alive
+	}
+	return false
+}
+
+// New: Job pulling
+type PullJobRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	WorkerId string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	// How long to wait for a job when none is ready; zero returns at once.
+	// Capped by the scheduler's max_pull_wait.
+	Wait          *durationpb.Duration `protobuf:"bytes,2,opt,name=wait,proto3" json:"wait,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PullJobRequest) Reset() {
+	*x = PullJobRequest{}
+	mi := &file_proto_orchestrator_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PullJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PullJobRequest) ProtoMessage() {}
+
+func (x *PullJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PullJobRequest.ProtoReflect.Descriptor instead.
+func (*PullJobRequest) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *PullJobRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *PullJobRequest) GetWait() *durationpb.Duration {
+	if x != nil {
+		return x.Wait
+	}
+	return nil
+}
+
+type PullJobResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Task          string                 `protobuf:"bytes,2,opt,name=task,proto3" json:"task,omitempty"`
+	Args          []string               `protobuf:"bytes,3,rep,name=args,proto3" json:"args,omitempty"`
+	Found         bool                   `protobuf:"varint,4,opt,name=found,proto3" json:"found,omitempty"`
+	Attempt       int32                  `protobuf:"varint,5,opt,name=attempt,proto3" json:"attempt,omitempty"` // 1 for the first run of the job
+	Inputs        []*JobInput            `protobuf:"bytes,6,rep,name=inputs,proto3" json:"inputs,omitempty"`
+	Outputs       []*JobOutput           `protobuf:"bytes,7,rep,name=outputs,proto3" json:"outputs,omitempty"`
+	Payload       *structpb.Struct       `protobuf:"bytes,8,opt,name=payload,proto3" json:"payload,omitempty"`
+	Timeout       *durationpb.Duration   `protobuf:"bytes,9,opt,name=timeout,proto3" json:"timeout,omitempty"` // unset if the job may run indefinitely
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PullJobResponse) Reset() {
+	*x = PullJobResponse{}
+	mi := &file_proto_orchestrator_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PullJobResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PullJobResponse) ProtoMessage() {}
+
+func (x *PullJobResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PullJobResponse.ProtoReflect.Descriptor instead.
+func (*PullJobResponse) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *PullJobResponse) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *PullJobResponse) GetTask() string {
+	if x != nil {
+		return x.Task
+	}
+	return ""
+}
+
+func (x *PullJobResponse) GetArgs() []string {
+	if x != nil {
+		return x.Args
+	}
+	return nil
+}
+
+func (x *PullJobResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+func (x *PullJobResponse) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *PullJobResponse) GetInputs() []*JobInput {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
+
+func (x *PullJobResponse) GetOutputs() []*JobOutput {
+	if x != nil {
+		return x.Outputs
+	}
+	return nil
+}
+
+func (x *PullJobResponse) GetPayload() *structpb.Struct {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *PullJobResponse) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+// Job start, reported by the worker before it executes a pulled job
+type StartJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	WorkerId      string                 `protobuf:"bytes,2,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartJobRequest) Reset() {
+	*x = StartJobRequest{}
+	mi := &file_proto_orchestrator_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartJobRequest) ProtoMessage() {}
+
+func (x *StartJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartJobRequest.ProtoReflect.Descriptor instead.
+func (*StartJobRequest) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *StartJobRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *StartJobRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+type StartJobResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartJobResponse) Reset() {
+	*x = StartJobResponse{}
+	mi := &file_proto_orchestrator_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartJobResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartJobResponse) ProtoMessage() {}
+
+func (x *StartJobResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartJobResponse.ProtoReflect.Descriptor instead.
+func (*StartJobResponse) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *StartJobResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+// New: Job completion
+type CompleteJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Result        string                 `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"`
+	WorkerId      string                 `protobuf:"bytes,3,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"` // non-empty marks the attempt as failed
+	ExitCode      int32                  `protobuf:"varint,5,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
+	Output        *structpb.Struct       `protobuf:"bytes,6,opt,name=output,proto3" json:"output,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompleteJobRequest) Reset() {
+	*x = CompleteJobRequest{}
+	mi := &file_proto_orchestrator_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompleteJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompleteJobRequest) ProtoMessage() {}
+
+func (x *CompleteJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompleteJobRequest.ProtoReflect.Descriptor instead.
+func (*CompleteJobRequest) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *CompleteJobRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *CompleteJobRequest) GetResult() string {
+	if x != nil {
+		return x.Result
+	}
+	return ""
+}
+
+func (x *CompleteJobRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *CompleteJobRequest) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *CompleteJobRequest) GetExitCode() int32 {
+	if x != nil {
+		return x.ExitCode
+	}
+	return 0
+}
+
+func (x *CompleteJobRequest) GetOutput() *structpb.Struct {
+	if x != nil {
+		return x.Output
+	}
+	return nil
+}
+
+type CompleteJobResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompleteJobResponse) Reset() {
+	*x = CompleteJobResponse{}
+	mi := &file_proto_orchestrator_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompleteJobResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompleteJobResponse) ProtoMessage() {}
+
+func (x *CompleteJobResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompleteJobResponse.ProtoReflect.Descriptor instead.
+func (*CompleteJobResponse) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *CompleteJobResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+// Job cancellation
+type CancelJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelJobRequest) Reset() {
+	*x = CancelJobRequest{}
+	mi := &file_proto_orchestrator_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelJobRequest) ProtoMessage() {}
+
+func (x *CancelJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelJobRequest.ProtoReflect.Descriptor instead.
+func (*CancelJobRequest) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *CancelJobRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *CancelJobRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type CancelJobResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelJobResponse) Reset() {
+	*x = CancelJobResponse{}
+	mi := &file_proto_orchestrator_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelJobResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelJobResponse) ProtoMessage() {}
+
+func (x *CancelJobResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelJobResponse.ProtoReflect.Descriptor instead.
+func (*CancelJobResponse) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *CancelJobResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+// Job state history
+type JobHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobHistoryRequest) Reset() {
+	*x = JobHistoryRequest{}
+	mi := &file_proto_orchestrator_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobHistoryRequest) ProtoMessage() {}
+
+func (x *JobHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobHistoryRequest.ProtoReflect.Descriptor instead.
+func (*JobHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *JobHistoryRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type JobTransition struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          string                 `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            string                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Actor         string                 `protobuf:"bytes,4,opt,name=actor,proto3" json:"actor,omitempty"` // "client", "scheduler" or "worker:<id>"
+	Reason        string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobTransition) Reset() {
+	*x = JobTransition{}
+	mi := &file_proto_orchestrator_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobTransition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobTransition) ProtoMessage() {}
+
+func (x *JobTransition) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobTransition.ProtoReflect.Descriptor instead.
+func (*JobTransition) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *JobTransition) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *JobTransition) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *JobTransition) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *JobTransition) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *JobTransition) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type JobHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Transitions   []*JobTransition       `protobuf:"bytes,1,rep,name=transitions,proto3" json:"transitions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobHistoryResponse) Reset() {
+	*x = JobHistoryResponse{}
+	mi := &file_proto_orchestrator_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobHistoryResponse) ProtoMessage() {}
+
+func (x *JobHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobHistoryResponse.ProtoReflect.Descriptor instead.
+func (*JobHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *JobHistoryResponse) GetTransitions() []*JobTransition {
+	if x != nil {
+		return x.Transitions
+	}
+	return nil
+}
+
+// New: Job listing
+type ListJobsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Statuses      []string               `protobuf:"bytes,1,rep,name=statuses,proto3" json:"statuses,omitempty"` // match any of these states; empty matches all
+	Task          string                 `protobuf:"bytes,2,opt,name=task,proto3" json:"task,omitempty"`
+	WorkerId      string                 `protobuf:"bytes,3,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"` // 0 returns every matching job
+	Offset        int32                  `protobuf:"varint,5,opt,name=offset,proto3" json:"offset,omitempty"`
+	Queue         string                 `protobuf:"bytes,6,opt,name=queue,proto3" json:"queue,omitempty"`
+	Tenant        string                 `protobuf:"bytes,7,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListJobsRequest) Reset() {
+	*x = ListJobsRequest{}
+	mi := &file_proto_orchestrator_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListJobsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobsRequest) ProtoMessage() {}
+
+func (x *ListJobsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobsRequest.ProtoReflect.Descriptor instead.
+func (*ListJobsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ListJobsRequest) GetStatuses() []string {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *ListJobsRequest) GetTask() string {
+	if x != nil {
+		return x.Task
+	}
+	return ""
+}
+
+func (x *ListJobsRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *ListJobsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListJobsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ListJobsRequest) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+func (x *ListJobsRequest) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+type ListJobsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Jobs          []*JobStatus           `protobuf:"bytes,1,rep,name=jobs,proto3" json:"jobs,omitempty"`
+	NextOffset    int32                  `protobuf:"varint,2,opt,name=next_offset,json=nextOffset,proto3" json:"next_offset,omitempty"` // offset of the next page, or 0 if this was the last
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListJobsResponse) Reset() {
+	*x = ListJobsResponse{}
+	mi := &file_proto_orchestrator_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListJobsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobsResponse) ProtoMessage() {}
+
+func (x *ListJobsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobsResponse.ProtoReflect.Descriptor instead.
+func (*ListJobsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ListJobsResponse) GetJobs() []*JobStatus {
+	if x != nil {
+		return x.Jobs
+	}
+	return nil
+}
+
+func (x *ListJobsResponse) GetNextOffset() int32 {
+	if x != nil {
+		return x.NextOffset
+	}
+	return 0
+}
+
+type JobStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Result        string                 `protobuf:"bytes,3,opt,name=result,proto3" json:"result,omitempty"`
+	Details       *JobDetails            `protobuf:"bytes,4,opt,name=details,proto3" json:"details,omitempty"`
+	Output        *structpb.Struct       `protobuf:"bytes,5,opt,name=output,proto3" json:"output,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobStatus) Reset() {
+	*x = JobStatus{}
+	mi := &file_proto_orchestrator_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobStatus) ProtoMessage() {}
+
+func (x *JobStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobStatus.ProtoReflect.Descriptor instead.
+func (*JobStatus) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *JobStatus) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *JobStatus) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *JobStatus) GetResult() string {
+	if x != nil {
+		return x.Result
+	}
+	return ""
+}
+
+func (x *JobStatus) GetDetails() *JobDetails {
+	if x != nil {
+		return x.Details
+	}
+	return nil
+}
+
+func (x *JobStatus) GetOutput() *structpb.Struct {
+	if x != nil {
+		return x.Output
+	}
+	return nil
+}
+
+// Job log streaming
+type LogEntry struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	JobId     string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	WorkerId  string                 `protobuf:"bytes,2,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Timestamp string                 `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Message   string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	// Worker-assigned, 1-based and contiguous per job attempt. The scheduler
+	// drops lines it has already stored; 0 disables de-duplication.
+	Seq           int64 `protobuf:"varint,5,opt,name=seq,proto3" json:"seq,omitempty"`
+	Attempt       int32 `protobuf:"varint,6,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogEntry) Reset() {
+	*x = LogEntry{}
+	mi := &file_proto_orchestrator_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogEntry) ProtoMessage() {}
+
+func (x *LogEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogEntry.ProtoReflect.Descriptor instead.
+func (*LogEntry) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *LogEntry) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *LogEntry) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *LogEntry) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+func (x *LogEntry) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *LogEntry) GetSeq() int64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *LogEntry) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+// A group of log lines shipped together; acked as a whole by batch_id.
+type LogBatch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BatchId       int64                  `protobuf:"varint,1,opt,name=batch_id,json=batchId,proto3" json:"batch_id,omitempty"`
+	Entries       []*LogEntry            `protobuf:"bytes,2,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogBatch) Reset() {
+	*x = LogBatch{}
+	mi := &file_proto_orchestrator_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogBatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogBatch) ProtoMessage() {}
+
+func (x *LogBatch) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogBatch.ProtoReflect.Descriptor instead.
+func (*LogBatch) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *LogBatch) GetBatchId() int64 {
+	if x != nil {
+		return x.BatchId
+	}
+	return 0
+}
+
+func (x *LogBatch) GetEntries() []*LogEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type LogAck struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Received      bool                   `protobuf:"varint,1,opt,name=received,proto3" json:"received,omitempty"`
+	BatchId       int64                  `protobuf:"varint,2,opt,name=batch_id,json=batchId,proto3" json:"batch_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogAck) Reset() {
+	*x = LogAck{}
+	mi := &file_proto_orchestrator_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogAck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogAck) ProtoMessage() {}
+
+func (x *LogAck) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogAck.ProtoReflect.Descriptor instead.
+func (*LogAck) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *LogAck) GetReceived() bool {
+	if x != nil {
+		return x.Received
+	}
+	return false
+}
+
+func (x *LogAck) GetBatchId() int64 {
+	if x != nil {
+		return x.BatchId
+	}
+	return 0
+}
+
+// Persisted job logs
+type LogLine struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Seq           int64                  `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"` // 1-based, contiguous per job
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogLine) Reset() {
+	*x = LogLine{}
+	mi := &file_proto_orchestrator_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogLine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogLine) ProtoMessage() {}
+
+func (x *LogLine) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogLine.ProtoReflect.Descriptor instead.
+func (*LogLine) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *LogLine) GetSeq() int64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *LogLine) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *LogLine) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type JobLogsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Offset        int64                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"` // return lines with seq greater than this
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`   // 0 returns the server's maximum page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobLogsRequest) Reset() {
+	*x = JobLogsRequest{}
+	mi := &file_proto_orchestrator_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobLogsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobLogsRequest) ProtoMessage() {}
+
+func (x *JobLogsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobLogsRequest.ProtoReflect.Descriptor instead.
+func (*JobLogsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *JobLogsRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *JobLogsRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *JobLogsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type JobLogsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Lines         []*LogLine             `protobuf:"bytes,1,rep,name=lines,proto3" json:"lines,omitempty"`
+	NextOffset    int64                  `protobuf:"varint,2,opt,name=next_offset,json=nextOffset,proto3" json:"next_offset,omitempty"` // seq of the last line returned, to pass as the next offset
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobLogsResponse) Reset() {
+	*x = JobLogsResponse{}
+	mi := &file_proto_orchestrator_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobLogsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobLogsResponse) ProtoMessage() {}
+
+func (x *JobLogsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobLogsResponse.ProtoReflect.Descriptor instead.
+func (*JobLogsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *JobLogsResponse) GetLines() []*LogLine {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+func (x *JobLogsResponse) GetNextOffset() int64 {
+	if x != nil {
+		return x.NextOffset
+	}
+	return 0
+}
+
+// Job artifacts: named files produced by a job, kept outside the job row
+type ArtifactInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Size          int64                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	Sha256        string                 `protobuf:"bytes,4,opt,name=sha256,proto3" json:"sha256,omitempty"` // hex
+	ContentType   string                 `protobuf:"bytes,5,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UploadId      string                 `protobuf:"bytes,7,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"` // set instead of job_id for uploaded inputs
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArtifactInfo) Reset() {
+	*x = ArtifactInfo{}
+	mi := &file_proto_orchestrator_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArtifactInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArtifactInfo) ProtoMessage() {}
+
+func (x *ArtifactInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArtifactInfo.ProtoReflect.Descriptor instead.
+func (*ArtifactInfo) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ArtifactInfo) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *ArtifactInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ArtifactInfo) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *ArtifactInfo) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *ArtifactInfo) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *ArtifactInfo) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *ArtifactInfo) GetUploadId() string {
+	if x != nil {
+		return x.UploadId
+	}
+	return ""
+}
+
+type ArtifactHeader struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	WorkerId      string                 `protobuf:"bytes,2,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"` // must hold the job
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`                         // one path segment; replaces an artifact of the same name
+	ContentType   string                 `protobuf:"bytes,4,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArtifactHeader) Reset() {
+	*x = ArtifactHeader{}
+	mi := &file_proto_orchestrator_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArtifactHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArtifactHeader) ProtoMessage() {}
+
+func (x *ArtifactHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArtifactHeader.ProtoReflect.Descriptor instead.
+func (*ArtifactHeader) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ArtifactHeader) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *ArtifactHeader) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *ArtifactHeader) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ArtifactHeader) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+// Upload message: the first carries the header, every one may carry data.
+type ArtifactChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Header        *ArtifactHeader        `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	Data          []byte                 `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArtifactChunk) Reset() {
+	*x = ArtifactChunk{}
+	mi := &file_proto_orchestrator_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArtifactChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArtifactChunk) ProtoMessage() {}
+
+func (x *ArtifactChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArtifactChunk.ProtoReflect.Descriptor instead.
+func (*ArtifactChunk) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ArtifactChunk) GetHeader() *ArtifactHeader {
+	if x != nil {
+		return x.Header
+	}
+	return nil
+}
+
+func (x *ArtifactChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type UploadArtifactResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Artifact      *ArtifactInfo          `protobuf:"bytes,1,opt,name=artifact,proto3" json:"artifact,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadArtifactResponse) Reset() {
+	*x = UploadArtifactResponse{}
+	mi := &file_proto_orchestrator_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadArtifactResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadArtifactResponse) ProtoMessage() {}
+
+func (x *UploadArtifactResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadArtifactResponse.ProtoReflect.Descriptor instead.
+func (*UploadArtifactResponse) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *UploadArtifactResponse) GetArtifact() *ArtifactInfo {
+	if x != nil {
+		return x.Artifact
+	}
+	return nil
+}
+
+// UploadInput takes the same messages as UploadArtifact; only the header's
+// name and content_type are used.
+type UploadInputResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UploadId      string                 `protobuf:"bytes,1,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"`
+	Artifact      *ArtifactInfo          `protobuf:"bytes,2,opt,name=artifact,proto3" json:"artifact,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadInputResponse) Reset() {
+	*x = UploadInputResponse{}
+	mi := &file_proto_orchestrator_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadInputResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadInputResponse) ProtoMessage() {}
+
+func (x *UploadInputResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadInputResponse.ProtoReflect.Descriptor instead.
+func (*UploadInputResponse) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *UploadInputResponse) GetUploadId() string {
+	if x != nil {
+		return x.UploadId
+	}
+	return ""
+}
+
+func (x *UploadInputResponse) GetArtifact() *ArtifactInfo {
+	if x != nil {
+		return x.Artifact
+	}
+	return nil
+}
+
+type ListArtifactsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListArtifactsRequest) Reset() {
+	*x = ListArtifactsRequest{}
+	mi := &file_proto_orchestrator_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListArtifactsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListArtifactsRequest) ProtoMessage() {}
+
+func (x *ListArtifactsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListArtifactsRequest.ProtoReflect.Descriptor instead.
+func (*ListArtifactsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ListArtifactsRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type ListArtifactsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Artifacts     []*ArtifactInfo        `protobuf:"bytes,1,rep,name=artifacts,proto3" json:"artifacts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListArtifactsResponse) Reset() {
+	*x = ListArtifactsResponse{}
+	mi := &file_proto_orchestrator_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListArtifactsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListArtifactsResponse) ProtoMessage() {}
+
+func (x *ListArtifactsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListArtifactsResponse.ProtoReflect.Descriptor instead.
+func (*ListArtifactsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ListArtifactsResponse) GetArtifacts() []*ArtifactInfo {
+	if x != nil {
+		return x.Artifacts
+	}
+	return nil
+}
+
+type DownloadArtifactRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	UploadId      string                 `protobuf:"bytes,3,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"` // download an uploaded input instead
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadArtifactRequest) Reset() {
+	*x = DownloadArtifactRequest{}
+	mi := &file_proto_orchestrator_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadArtifactRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadArtifactRequest) ProtoMessage() {}
+
+func (x *DownloadArtifactRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadArtifactRequest.ProtoReflect.Descriptor instead.
+func (*DownloadArtifactRequest) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *DownloadArtifactRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *DownloadArtifactRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DownloadArtifactRequest) GetUploadId() string {
+	if x != nil {
+		return x.UploadId
+	}
+	return ""
+}
+
+// Download message: the first carries the artifact's info, every one may
+// carry data.
+type ArtifactData struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Info          *ArtifactInfo          `protobuf:"bytes,1,opt,name=info,proto3" json:"info,omitempty"`
+	Data          []byte                 `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArtifactData) Reset() {
+	*x = ArtifactData{}
+	mi := &file_proto_orchestrator_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArtifactData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArtifactData) ProtoMessage() {}
+
+func (x *ArtifactData) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArtifactData.ProtoReflect.Descriptor instead.
+func (*ArtifactData) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *ArtifactData) GetInfo() *ArtifactInfo {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+func (x *ArtifactData) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// Task type catalog
+type ListTaskTypesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTaskTypesRequest) Reset() {
+	*x = ListTaskTypesRequest{}
+	mi := &file_proto_orchestrator_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTaskTypesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTaskTypesRequest) ProtoMessage() {}
+
+func (x *ListTaskTypesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTaskTypesRequest.ProtoReflect.Descriptor instead.
+func (*ListTaskTypesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{44}
+}
+
+type TaskType struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Name           string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description    string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Queue          string                 `protobuf:"bytes,3,opt,name=queue,proto3" json:"queue,omitempty"`
+	Timeout        *durationpb.Duration   `protobuf:"bytes,4,opt,name=timeout,proto3" json:"timeout,omitempty"` // unset if jobs may run indefinitely
+	MaxAttempts    int32                  `protobuf:"varint,5,opt,name=max_attempts,json=maxAttempts,proto3" json:"max_attempts,omitempty"`
+	InitialBackoff *durationpb.Duration   `protobuf:"bytes,6,opt,name=initial_backoff,json=initialBackoff,proto3" json:"initial_backoff,omitempty"`
+	MaxBackoff     *durationpb.Duration   `protobuf:"bytes,7,opt,name=max_backoff,json=maxBackoff,proto3" json:"max_backoff,omitempty"`
+	ArgsSchema     string                 `protobuf:"bytes,8,opt,name=args_schema,json=argsSchema,proto3" json:"args_schema,omitempty"` // JSON Schema as JSON; empty if args are unchecked
+	PayloadSchema  string                 `protobuf:"bytes,9,opt,name=payload_schema,json=payloadSchema,proto3" json:"payload_schema,omitempty"`
+	Version        string                 `protobuf:"bytes,10,opt,name=version,proto3" json:"version,omitempty"`
+	Cacheable      bool                   `protobuf:"varint,11,opt,name=cacheable,proto3" json:"cacheable,omitempty"`
+	CacheTtl       *durationpb.Duration   `protobuf:"bytes,12,opt,name=cache_ttl,json=cacheTtl,proto3" json:"cache_ttl,omitempty"` // unset if results are kept until invalidated
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *TaskType) Reset() {
+	*x = TaskType{}
+	mi := &file_proto_orchestrator_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskType) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskType) ProtoMessage() {}
+
+func (x *TaskType) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskType.ProtoReflect.Descriptor instead.
+func (*TaskType) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *TaskType) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TaskType) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *TaskType) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+func (x *TaskType) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+func (x *TaskType) GetMaxAttempts() int32 {
+	if x != nil {
+		return x.MaxAttempts
+	}
+	return 0
+}
+
+func (x *TaskType) GetInitialBackoff() *durationpb.Duration {
+	if x != nil {
+		return x.InitialBackoff
+	}
+	return nil
+}
+
+func (x *TaskType) GetMaxBackoff() *durationpb.Duration {
+	if x != nil {
+		return x.MaxBackoff
+	}
+	return nil
+}
+
+func (x *TaskType) GetArgsSchema() string {
+	if x != nil {
+		return x.ArgsSchema
+	}
+	return ""
+}
+
+func (x *TaskType) GetPayloadSchema() string {
+	if x != nil {
+		return x.PayloadSchema
+	}
+	return ""
+}
+
+func (x *TaskType) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *TaskType) GetCacheable() bool {
+	if x != nil {
+		return x.Cacheable
+	}
+	return false
+}
+
+func (x *TaskType) GetCacheTtl() *durationpb.Duration {
+	if x != nil {
+		return x.CacheTtl
+	}
+	return nil
+}
+
+type ListTaskTypesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskTypes     []*TaskType            `protobuf:"bytes,1,rep,name=task_types,json=taskTypes,proto3" json:"task_types,omitempty"`
+	Strict        bool                   `protobuf:"varint,2,opt,name=strict,proto3" json:"strict,omitempty"` // undeclared task types are rejected
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTaskTypesResponse) Reset() {
+	*x = ListTaskTypesResponse{}
+	mi := &file_proto_orchestrator_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTaskTypesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PullJobRequest) ProtoMessage() {}
+func (*ListTaskTypesResponse) ProtoMessage() {}
 
-func (x *PullJobRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_orchestrator_proto_msgTypes[8]
+func (x *ListTaskTypesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[46]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -435,43 +3193,55 @@ func (x *PullJobRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PullJobRequest.ProtoReflect.Descriptor instead.
-func (*PullJobRequest) Descriptor() ([]byte, []int) {
-	return file_proto_orchestrator_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use ListTaskTypesResponse.ProtoReflect.Descriptor instead.
+func (*ListTaskTypesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{46}
 }
 
-func (x *PullJobRequest) GetWorkerId() string {
+func (x *ListTaskTypesResponse) GetTaskTypes() []*TaskType {
 	if x != nil {
-		return x.WorkerId
+		return x.TaskTypes
 	}
-	return ""
+	return nil
 }
 
-type PullJobResponse struct {
+func (x *ListTaskTypesResponse) GetStrict() bool {
+	if x != nil {
+		return x.Strict
+	}
+	return false
+}
+
+// Result cache. An entry is the result of the last job with a cache key
+// that ran and succeeded.
+type CacheEntry struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
 	Task          string                 `protobuf:"bytes,2,opt,name=task,proto3" json:"task,omitempty"`
-	Args          []string               `protobuf:"bytes,3,rep,name=args,proto3" json:"args,omitempty"`
-	Found         bool                   `protobuf:"varint,4,opt,name=found,proto3" json:"found,omitempty"`
+	JobId         string                 `protobuf:"bytes,3,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // unset if it never expires
+	Expired       bool                   `protobuf:"varint,6,opt,name=expired,proto3" json:"expired,omitempty"`
+	Hits          int32                  `protobuf:"varint,7,opt,name=hits,proto3" json:"hits,omitempty"` // jobs that reused it
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PullJobResponse) Reset() {
-	*x = PullJobResponse{}
-	mi := &file_proto_orchestrator_proto_msgTypes[9]
+func (x *CacheEntry) Reset() {
+	*x = CacheEntry{}
+	mi := &file_proto_orchestrator_proto_msgTypes[47]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PullJobResponse) String() string {
+func (x *CacheEntry) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PullJobResponse) ProtoMessage() {}
+func (*CacheEntry) ProtoMessage() {}
 
-func (x *PullJobResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_orchestrator_proto_msgTypes[9]
+func (x *CacheEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[47]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -482,63 +3252,84 @@ func (x *PullJobResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PullJobResponse.ProtoReflect.Descriptor instead.
-func (*PullJobResponse) Descriptor() ([]byte, []int) {
-	return file_proto_orchestrator_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use CacheEntry.ProtoReflect.Descriptor instead.
+func (*CacheEntry) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{47}
 }
 
-func (x *PullJobResponse) GetJobId() string {
+func (x *CacheEntry) GetKey() string {
 	if x != nil {
-		return x.JobId
+		return x.Key
 	}
 	return ""
 }
 
-func (x *PullJobResponse) GetTask() string {
+func (x *CacheEntry) GetTask() string {
 	if x != nil {
 		return x.Task
 	}
 	return ""
 }
 
-func (x *PullJobResponse) GetArgs() []string {
+func (x *CacheEntry) GetJobId() string {
 	if x != nil {
-		return x.Args
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *CacheEntry) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
 	}
 	return nil
 }
 
-func (x *PullJobResponse) GetFound() bool {
+func (x *CacheEntry) GetExpiresAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Found
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *CacheEntry) GetExpired() bool {
+	if x != nil {
+		return x.Expired
 	}
 	return false
 }
 
-// New: Job completion
-type CompleteJobRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
-	Result        string                 `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *CacheEntry) GetHits() int32 {
+	if x != nil {
+		return x.Hits
+	}
+	return 0
 }
 
-func (x *CompleteJobRequest) Reset() {
-	*x = CompleteJobRequest{}
-	mi := &file_proto_orchestrator_proto_msgTypes[10]
+type ListCacheEntriesRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Task           string                 `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	Key            string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	IncludeExpired bool                   `protobuf:"varint,3,opt,name=include_expired,json=includeExpired,proto3" json:"include_expired,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListCacheEntriesRequest) Reset() {
+	*x = ListCacheEntriesRequest{}
+	mi := &file_proto_orchestrator_proto_msgTypes[48]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CompleteJobRequest) String() string {
+func (x *ListCacheEntriesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CompleteJobRequest) ProtoMessage() {}
+func (*ListCacheEntriesRequest) ProtoMessage() {}
 
-func (x *CompleteJobRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_orchestrator_proto_msgTypes[10]
+func (x *ListCacheEntriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[48]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -549,47 +3340,54 @@ func (x *CompleteJobRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CompleteJobRequest.ProtoReflect.Descriptor instead.
-func (*CompleteJobRequest) Descriptor() ([]byte, []int) {
-	return file_proto_orchestrator_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use ListCacheEntriesRequest.ProtoReflect.Descriptor instead.
+func (*ListCacheEntriesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{48}
 }
 
-func (x *CompleteJobRequest) GetJobId() string {
+func (x *ListCacheEntriesRequest) GetTask() string {
 	if x != nil {
-		return x.JobId
+		return x.Task
 	}
 	return ""
 }
 
-func (x *CompleteJobRequest) GetResult() string {
+func (x *ListCacheEntriesRequest) GetKey() string {
 	if x != nil {
-		return x.Result
+		return x.Key
 	}
 	return ""
 }
 
-type CompleteJobResponse struct {
+func (x *ListCacheEntriesRequest) GetIncludeExpired() bool {
+	if x != nil {
+		return x.IncludeExpired
+	}
+	return false
+}
+
+type ListCacheEntriesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Entries       []*CacheEntry          `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CompleteJobResponse) Reset() {
-	*x = CompleteJobResponse{}
-	mi := &file_proto_orchestrator_proto_msgTypes[11]
+func (x *ListCacheEntriesResponse) Reset() {
+	*x = ListCacheEntriesResponse{}
+	mi := &file_proto_orchestrator_proto_msgTypes[49]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CompleteJobResponse) String() string {
+func (x *ListCacheEntriesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CompleteJobResponse) ProtoMessage() {}
+func (*ListCacheEntriesResponse) ProtoMessage() {}
 
-func (x *CompleteJobResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_orchestrator_proto_msgTypes[11]
+func (x *ListCacheEntriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[49]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -600,40 +3398,43 @@ func (x *CompleteJobResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CompleteJobResponse.ProtoReflect.Descriptor instead.
-func (*CompleteJobResponse) Descriptor() ([]byte, []int) {
-	return file_proto_orchestrator_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use ListCacheEntriesResponse.ProtoReflect.Descriptor instead.
+func (*ListCacheEntriesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{49}
 }
 
-func (x *CompleteJobResponse) GetSuccess() bool {
+func (x *ListCacheEntriesResponse) GetEntries() []*CacheEntry {
 	if x != nil {
-		return x.Success
+		return x.Entries
 	}
-	return false
+	return nil
 }
 
-// New: Job listing
-type ListJobsRequest struct {
+// Drops the entries matching task and key, or every entry if all is set.
+type InvalidateCacheRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Task          string                 `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	All           bool                   `protobuf:"varint,3,opt,name=all,proto3" json:"all,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListJobsRequest) Reset() {
-	*x = ListJobsRequest{}
-	mi := &file_proto_orchestrator_proto_msgTypes[12]
+func (x *InvalidateCacheRequest) Reset() {
+	*x = InvalidateCacheRequest{}
+	mi := &file_proto_orchestrator_proto_msgTypes[50]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListJobsRequest) String() string {
+func (x *InvalidateCacheRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListJobsRequest) ProtoMessage() {}
+func (*InvalidateCacheRequest) ProtoMessage() {}
 
-func (x *ListJobsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_orchestrator_proto_msgTypes[12]
+func (x *InvalidateCacheRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[50]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -644,33 +3445,54 @@ func (x *ListJobsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListJobsRequest.ProtoReflect.Descriptor instead.
-func (*ListJobsRequest) Descriptor() ([]byte, []int) {
-	return file_proto_orchestrator_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use InvalidateCacheRequest.ProtoReflect.Descriptor instead.
+func (*InvalidateCacheRequest) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{50}
 }
 
-type ListJobsResponse struct {
+func (x *InvalidateCacheRequest) GetTask() string {
+	if x != nil {
+		return x.Task
+	}
+	return ""
+}
+
+func (x *InvalidateCacheRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *InvalidateCacheRequest) GetAll() bool {
+	if x != nil {
+		return x.All
+	}
+	return false
+}
+
+type InvalidateCacheResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Jobs          []*JobStatus           `protobuf:"bytes,1,rep,name=jobs,proto3" json:"jobs,omitempty"`
+	Invalidated   int32                  `protobuf:"varint,1,opt,name=invalidated,proto3" json:"invalidated,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListJobsResponse) Reset() {
-	*x = ListJobsResponse{}
-	mi := &file_proto_orchestrator_proto_msgTypes[13]
+func (x *InvalidateCacheResponse) Reset() {
+	*x = InvalidateCacheResponse{}
+	mi := &file_proto_orchestrator_proto_msgTypes[51]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListJobsResponse) String() string {
+func (x *InvalidateCacheResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListJobsResponse) ProtoMessage() {}
+func (*InvalidateCacheResponse) ProtoMessage() {}
 
-func (x *ListJobsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_orchestrator_proto_msgTypes[13]
+func (x *InvalidateCacheResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[51]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -681,42 +3503,40 @@ func (x *ListJobsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListJobsResponse.ProtoReflect.Descriptor instead.
-func (*ListJobsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_orchestrator_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use InvalidateCacheResponse.ProtoReflect.Descriptor instead.
+func (*InvalidateCacheResponse) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{51}
 }
 
-func (x *ListJobsResponse) GetJobs() []*JobStatus {
+func (x *InvalidateCacheResponse) GetInvalidated() int32 {
 	if x != nil {
-		return x.Jobs
+		return x.Invalidated
 	}
-	return nil
+	return 0
 }
 
-type JobStatus struct {
+// Registered workers
+type ListWorkersRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
-	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
-	Result        string                 `protobuf:"bytes,3,opt,name=result,proto3" json:"result,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *JobStatus) Reset() {
-	*x = JobStatus{}
-	mi := &file_proto_orchestrator_proto_msgTypes[14]
+func (x *ListWorkersRequest) Reset() {
+	*x = ListWorkersRequest{}
+	mi := &file_proto_orchestrator_proto_msgTypes[52]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *JobStatus) String() string {
+func (x *ListWorkersRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*JobStatus) ProtoMessage() {}
+func (*ListWorkersRequest) ProtoMessage() {}
 
-func (x *JobStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_orchestrator_proto_msgTypes[14]
+func (x *ListWorkersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[52]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -727,58 +3547,39 @@ func (x *JobStatus) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use JobStatus.ProtoReflect.Descriptor instead.
-func (*JobStatus) Descriptor() ([]byte, []int) {
-	return file_proto_orchestrator_proto_rawDescGZIP(), []int{14}
-}
-
-func (x *JobStatus) GetJobId() string {
-	if x != nil {
-		return x.JobId
-	}
-	return ""
-}
-
-func (x *JobStatus) GetStatus() string {
-	if x != nil {
-		return x.Status
-	}
-	return ""
-}
-
-func (x *JobStatus) GetResult() string {
-	if x != nil {
-		return x.Result
-	}
-	return ""
+// Deprecated: Use ListWorkersRequest.ProtoReflect.Descriptor instead.
+func (*ListWorkersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{52}
 }
 
-// Job log streaming
-type LogEntry struct {
+type WorkerDetails struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
-	WorkerId      string                 `protobuf:"bytes,2,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
-	Timestamp     string                 `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	Message       string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	WorkerId      string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Host          string                 `protobuf:"bytes,2,opt,name=host,proto3" json:"host,omitempty"`
+	LastSeen      *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`
+	Connected     bool                   `protobuf:"varint,4,opt,name=connected,proto3" json:"connected,omitempty"` // heard from within the scheduler's lease duration
+	Tasks         []*TaskCapability      `protobuf:"bytes,5,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	AnyTask       bool                   `protobuf:"varint,6,opt,name=any_task,json=anyTask,proto3" json:"any_task,omitempty"`
+	Capacity      *Resources             `protobuf:"bytes,7,opt,name=capacity,proto3" json:"capacity,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *LogEntry) Reset() {
-	*x = LogEntry{}
-	mi := &file_proto_orchestrator_proto_msgTypes[15]
+func (x *WorkerDetails) Reset() {
+	*x = WorkerDetails{}
+	mi := &file_proto_orchestrator_proto_msgTypes[53]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *LogEntry) String() string {
+func (x *WorkerDetails) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LogEntry) ProtoMessage() {}
+func (*WorkerDetails) ProtoMessage() {}
 
-func (x *LogEntry) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_orchestrator_proto_msgTypes[15]
+func (x *WorkerDetails) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[53]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -789,61 +3590,82 @@ func (x *LogEntry) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LogEntry.ProtoReflect.Descriptor instead.
-func (*LogEntry) Descriptor() ([]byte, []int) {
-	return file_proto_orchestrator_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use WorkerDetails.ProtoReflect.Descriptor instead.
+func (*WorkerDetails) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{53}
 }
 
-func (x *LogEntry) GetJobId() string {
+func (x *WorkerDetails) GetWorkerId() string {
 	if x != nil {
-		return x.JobId
+		return x.WorkerId
 	}
 	return ""
 }
 
-func (x *LogEntry) GetWorkerId() string {
+func (x *WorkerDetails) GetHost() string {
 	if x != nil {
-		return x.WorkerId
+		return x.Host
 	}
 	return ""
 }
 
-func (x *LogEntry) GetTimestamp() string {
+func (x *WorkerDetails) GetLastSeen() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Timestamp
+		return x.LastSeen
 	}
-	return ""
+	return nil
 }
 
-func (x *LogEntry) GetMessage() string {
+func (x *WorkerDetails) GetConnected() bool {
 	if x != nil {
-		return x.Message
+		return x.Connected
 	}
-	return ""
+	return false
 }
 
-type LogAck struct {
+func (x *WorkerDetails) GetTasks() []*TaskCapability {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+func (x *WorkerDetails) GetAnyTask() bool {
+	if x != nil {
+		return x.AnyTask
+	}
+	return false
+}
+
+func (x *WorkerDetails) GetCapacity() *Resources {
+	if x != nil {
+		return x.Capacity
+	}
+	return nil
+}
+
+type ListWorkersResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Received      bool                   `protobuf:"varint,1,opt,name=received,proto3" json:"received,omitempty"`
+	Workers       []*WorkerDetails       `protobuf:"bytes,1,rep,name=workers,proto3" json:"workers,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *LogAck) Reset() {
-	*x = LogAck{}
-	mi := &file_proto_orchestrator_proto_msgTypes[16]
+func (x *ListWorkersResponse) Reset() {
+	*x = ListWorkersResponse{}
+	mi := &file_proto_orchestrator_proto_msgTypes[54]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *LogAck) String() string {
+func (x *ListWorkersResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LogAck) ProtoMessage() {}
+func (*ListWorkersResponse) ProtoMessage() {}
 
-func (x *LogAck) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_orchestrator_proto_msgTypes[16]
+func (x *ListWorkersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orchestrator_proto_msgTypes[54]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -854,79 +3676,324 @@ func (x *LogAck) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LogAck.ProtoReflect.Descriptor instead.
-func (*LogAck) Descriptor() ([]byte, []int) {
-	return file_proto_orchestrator_proto_rawDescGZIP(), []int{16}
+// Deprecated: Use ListWorkersResponse.ProtoReflect.Descriptor instead.
+func (*ListWorkersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_orchestrator_proto_rawDescGZIP(), []int{54}
 }
 
-func (x *LogAck) GetReceived() bool {
+func (x *ListWorkersResponse) GetWorkers() []*WorkerDetails {
 	if x != nil {
-		return x.Received
+		return x.Workers
 	}
-	return false
+	return nil
 }
 
 var File_proto_orchestrator_proto protoreflect.FileDescriptor
 
 const file_proto_orchestrator_proto_rawDesc = "" +
 	"\n" +
-	"\x18proto/orchestrator.proto\x12\forchestrator\"4\n" +
+	"\x18proto/orchestrator.proto\x12\forchestrator\x1a\x1egoogle/protobuf/duration.proto\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa5\x04\n" +
 	"\n" +
 	"JobRequest\x12\x12\n" +
 	"\x04task\x18\x01 \x01(\tR\x04task\x12\x12\n" +
-	"\x04args\x18\x02 \x03(\tR\x04args\"$\n" +
+	"\x04args\x18\x02 \x03(\tR\x04args\x12\x1a\n" +
+	"\bpriority\x18\x03 \x01(\x05R\bpriority\x12.\n" +
+	"\x06inputs\x18\x04 \x03(\v2\x16.orchestrator.JobInputR\x06inputs\x121\n" +
+	"\aoutputs\x18\x05 \x03(\v2\x17.orchestrator.JobOutputR\aoutputs\x121\n" +
+	"\apayload\x18\x06 \x01(\v2\x17.google.protobuf.StructR\apayload\x123\n" +
+	"\atimeout\x18\a \x01(\v2\x19.google.protobuf.DurationR\atimeout\x12!\n" +
+	"\tcacheable\x18\b \x01(\bH\x00R\tcacheable\x88\x01\x01\x12'\n" +
+	"\x0fconcurrency_key\x18\t \x01(\tR\x0econcurrencyKey\x12+\n" +
+	"\x11concurrency_limit\x18\n" +
+	" \x01(\x05R\x10concurrencyLimit\x122\n" +
+	"\x06unique\x18\v \x01(\v2\x1a.orchestrator.UniquePolicyR\x06unique\x12\x16\n" +
+	"\x06tenant\x18\f \x01(\tR\x06tenant\x125\n" +
+	"\tresources\x18\r \x01(\v2\x17.orchestrator.ResourcesR\tresourcesB\f\n" +
+	"\n" +
+	"_cacheable\":\n" +
+	"\tResources\x12\x10\n" +
+	"\x03cpu\x18\x01 \x01(\x01R\x03cpu\x12\x1b\n" +
+	"\tmemory_mb\x18\x02 \x01(\x03R\bmemoryMb\"\x84\x01\n" +
+	"\fUniquePolicy\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12/\n" +
+	"\x05scope\x18\x02 \x01(\x0e2\x19.orchestrator.UniqueScopeR\x05scope\x121\n" +
+	"\x06window\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\x06window\"\x80\x01\n" +
+	"\bJobInput\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x1d\n" +
+	"\tupload_id\x18\x02 \x01(\tH\x00R\buploadId\x127\n" +
+	"\bartifact\x18\x03 \x01(\v2\x19.orchestrator.ArtifactRefH\x00R\bartifactB\b\n" +
+	"\x06source\"8\n" +
+	"\vArtifactRef\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\";\n" +
+	"\tJobOutput\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x1a\n" +
+	"\bartifact\x18\x02 \x01(\tR\bartifact\"_\n" +
 	"\vJobResponse\x12\x15\n" +
-	"\x06job_id\x18\x01 \x01(\tR\x05jobId\")\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x1b\n" +
+	"\tcache_hit\x18\x02 \x01(\bR\bcacheHit\x12\x1c\n" +
+	"\tduplicate\x18\x03 \x01(\bR\tduplicate\")\n" +
 	"\x10JobStatusRequest\x12\x15\n" +
-	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"C\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"\xa8\x01\n" +
 	"\x11JobStatusResponse\x12\x16\n" +
 	"\x06status\x18\x01 \x01(\tR\x06status\x12\x16\n" +
-	"\x06result\x18\x02 \x01(\tR\x06result\"H\n" +
+	"\x06result\x18\x02 \x01(\tR\x06result\x122\n" +
+	"\adetails\x18\x03 \x01(\v2\x18.orchestrator.JobDetailsR\adetails\x12/\n" +
+	"\x06output\x18\x04 \x01(\v2\x17.google.protobuf.StructR\x06output\"\x93\t\n" +
+	"\n" +
+	"JobDetails\x12\x12\n" +
+	"\x04task\x18\x01 \x01(\tR\x04task\x12\x12\n" +
+	"\x04args\x18\x02 \x03(\tR\x04args\x129\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x127\n" +
+	"\tqueued_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\bqueuedAt\x129\n" +
+	"\n" +
+	"started_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12;\n" +
+	"\vfinished_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"finishedAt\x12\x1b\n" +
+	"\tworker_id\x18\a \x01(\tR\bworkerId\x12\x18\n" +
+	"\aattempt\x18\b \x01(\x05R\aattempt\x12\x1b\n" +
+	"\texit_code\x18\t \x01(\x05R\bexitCode\x12\x14\n" +
+	"\x05error\x18\n" +
+	" \x01(\tR\x05error\x12@\n" +
+	"\x0equeue_duration\x18\v \x01(\v2\x19.google.protobuf.DurationR\rqueueDuration\x12<\n" +
+	"\frun_duration\x18\f \x01(\v2\x19.google.protobuf.DurationR\vrunDuration\x12\x1a\n" +
+	"\bpriority\x18\r \x01(\x05R\bpriority\x12D\n" +
+	"\x10lease_expires_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\x0eleaseExpiresAt\x12.\n" +
+	"\x06inputs\x18\x0f \x03(\v2\x16.orchestrator.JobInputR\x06inputs\x121\n" +
+	"\aoutputs\x18\x10 \x03(\v2\x17.orchestrator.JobOutputR\aoutputs\x121\n" +
+	"\apayload\x18\x11 \x01(\v2\x17.google.protobuf.StructR\apayload\x12\x14\n" +
+	"\x05queue\x18\x12 \x01(\tR\x05queue\x123\n" +
+	"\atimeout\x18\x13 \x01(\v2\x19.google.protobuf.DurationR\atimeout\x12%\n" +
+	"\x0ewaiting_reason\x18\x14 \x01(\tR\rwaitingReason\x12\x1b\n" +
+	"\tcache_key\x18\x15 \x01(\tR\bcacheKey\x12\x1b\n" +
+	"\tcache_hit\x18\x16 \x01(\bR\bcacheHit\x12\x1f\n" +
+	"\vcached_from\x18\x17 \x01(\tR\n" +
+	"cachedFrom\x12'\n" +
+	"\x0fconcurrency_key\x18\x18 \x01(\tR\x0econcurrencyKey\x12+\n" +
+	"\x11concurrency_limit\x18\x19 \x01(\x05R\x10concurrencyLimit\x12\x1d\n" +
+	"\n" +
+	"unique_key\x18\x1a \x01(\tR\tuniqueKey\x12\x16\n" +
+	"\x06tenant\x18\x1b \x01(\tR\x06tenant\x125\n" +
+	"\tresources\x18\x1c \x01(\v2\x17.orchestrator.ResourcesR\tresources\"\xcc\x01\n" +
 	"\x15RegisterWorkerRequest\x12\x1b\n" +
 	"\tworker_id\x18\x01 \x01(\tR\bworkerId\x12\x12\n" +
-	"\x04host\x18\x02 \x01(\tR\x04host\"2\n" +
+	"\x04host\x18\x02 \x01(\tR\x04host\x122\n" +
+	"\x05tasks\x18\x03 \x03(\v2\x1c.orchestrator.TaskCapabilityR\x05tasks\x12\x19\n" +
+	"\bany_task\x18\x04 \x01(\bR\aanyTask\x123\n" +
+	"\bcapacity\x18\x05 \x01(\v2\x17.orchestrator.ResourcesR\bcapacity\">\n" +
+	"\x0eTaskCapability\x12\x12\n" +
+	"\x04task\x18\x01 \x01(\tR\x04task\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\"2\n" +
 	"\x16RegisterWorkerResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\"/\n" +
 	"\x10HeartbeatRequest\x12\x1b\n" +
 	"\tworker_id\x18\x01 \x01(\tR\bworkerId\")\n" +
 	"\x11HeartbeatResponse\x12\x14\n" +
-	"\x05alive\x18\x01 \x01(\bR\x05alive\"-\n" +
+	"\x05alive\x18\x01 \x01(\bR\x05alive\"\\\n" +
 	"\x0ePullJobRequest\x12\x1b\n" +
-	"\tworker_id\x18\x01 \x01(\tR\bworkerId\"f\n" +
+	"\tworker_id\x18\x01 \x01(\tR\bworkerId\x12-\n" +
+	"\x04wait\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\x04wait\"\xcb\x02\n" +
 	"\x0fPullJobResponse\x12\x15\n" +
 	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x12\n" +
 	"\x04task\x18\x02 \x01(\tR\x04task\x12\x12\n" +
 	"\x04args\x18\x03 \x03(\tR\x04args\x12\x14\n" +
-	"\x05found\x18\x04 \x01(\bR\x05found\"C\n" +
+	"\x05found\x18\x04 \x01(\bR\x05found\x12\x18\n" +
+	"\aattempt\x18\x05 \x01(\x05R\aattempt\x12.\n" +
+	"\x06inputs\x18\x06 \x03(\v2\x16.orchestrator.JobInputR\x06inputs\x121\n" +
+	"\aoutputs\x18\a \x03(\v2\x17.orchestrator.JobOutputR\aoutputs\x121\n" +
+	"\apayload\x18\b \x01(\v2\x17.google.protobuf.StructR\apayload\x123\n" +
+	"\atimeout\x18\t \x01(\v2\x19.google.protobuf.DurationR\atimeout\"E\n" +
+	"\x0fStartJobRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x1b\n" +
+	"\tworker_id\x18\x02 \x01(\tR\bworkerId\",\n" +
+	"\x10StartJobResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\xc4\x01\n" +
 	"\x12CompleteJobRequest\x12\x15\n" +
 	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x16\n" +
-	"\x06result\x18\x02 \x01(\tR\x06result\"/\n" +
+	"\x06result\x18\x02 \x01(\tR\x06result\x12\x1b\n" +
+	"\tworker_id\x18\x03 \x01(\tR\bworkerId\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\x12\x1b\n" +
+	"\texit_code\x18\x05 \x01(\x05R\bexitCode\x12/\n" +
+	"\x06output\x18\x06 \x01(\v2\x17.google.protobuf.StructR\x06output\"/\n" +
 	"\x13CompleteJobResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\"\x11\n" +
-	"\x0fListJobsRequest\"?\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"A\n" +
+	"\x10CancelJobRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"-\n" +
+	"\x11CancelJobResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"*\n" +
+	"\x11JobHistoryRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"\x9b\x01\n" +
+	"\rJobTransition\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\tR\x02to\x128\n" +
+	"\ttimestamp\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x14\n" +
+	"\x05actor\x18\x04 \x01(\tR\x05actor\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\"S\n" +
+	"\x12JobHistoryResponse\x12=\n" +
+	"\vtransitions\x18\x01 \x03(\v2\x1b.orchestrator.JobTransitionR\vtransitions\"\xba\x01\n" +
+	"\x0fListJobsRequest\x12\x1a\n" +
+	"\bstatuses\x18\x01 \x03(\tR\bstatuses\x12\x12\n" +
+	"\x04task\x18\x02 \x01(\tR\x04task\x12\x1b\n" +
+	"\tworker_id\x18\x03 \x01(\tR\bworkerId\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x05 \x01(\x05R\x06offset\x12\x14\n" +
+	"\x05queue\x18\x06 \x01(\tR\x05queue\x12\x16\n" +
+	"\x06tenant\x18\a \x01(\tR\x06tenant\"`\n" +
 	"\x10ListJobsResponse\x12+\n" +
-	"\x04jobs\x18\x01 \x03(\v2\x17.orchestrator.JobStatusR\x04jobs\"R\n" +
+	"\x04jobs\x18\x01 \x03(\v2\x17.orchestrator.JobStatusR\x04jobs\x12\x1f\n" +
+	"\vnext_offset\x18\x02 \x01(\x05R\n" +
+	"nextOffset\"\xb7\x01\n" +
 	"\tJobStatus\x12\x15\n" +
 	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x16\n" +
 	"\x06status\x18\x02 \x01(\tR\x06status\x12\x16\n" +
-	"\x06result\x18\x03 \x01(\tR\x06result\"v\n" +
+	"\x06result\x18\x03 \x01(\tR\x06result\x122\n" +
+	"\adetails\x18\x04 \x01(\v2\x18.orchestrator.JobDetailsR\adetails\x12/\n" +
+	"\x06output\x18\x05 \x01(\v2\x17.google.protobuf.StructR\x06output\"\xa2\x01\n" +
 	"\bLogEntry\x12\x15\n" +
 	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x1b\n" +
 	"\tworker_id\x18\x02 \x01(\tR\bworkerId\x12\x1c\n" +
 	"\ttimestamp\x18\x03 \x01(\tR\ttimestamp\x12\x18\n" +
-	"\amessage\x18\x04 \x01(\tR\amessage\"$\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\x12\x10\n" +
+	"\x03seq\x18\x05 \x01(\x03R\x03seq\x12\x18\n" +
+	"\aattempt\x18\x06 \x01(\x05R\aattempt\"W\n" +
+	"\bLogBatch\x12\x19\n" +
+	"\bbatch_id\x18\x01 \x01(\x03R\abatchId\x120\n" +
+	"\aentries\x18\x02 \x03(\v2\x16.orchestrator.LogEntryR\aentries\"?\n" +
 	"\x06LogAck\x12\x1a\n" +
-	"\breceived\x18\x01 \x01(\bR\breceived2\xf7\x04\n" +
+	"\breceived\x18\x01 \x01(\bR\breceived\x12\x19\n" +
+	"\bbatch_id\x18\x02 \x01(\x03R\abatchId\"o\n" +
+	"\aLogLine\x12\x10\n" +
+	"\x03seq\x18\x01 \x01(\x03R\x03seq\x128\n" +
+	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"U\n" +
+	"\x0eJobLogsRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x03R\x06offset\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\"_\n" +
+	"\x0fJobLogsResponse\x12+\n" +
+	"\x05lines\x18\x01 \x03(\v2\x15.orchestrator.LogLineR\x05lines\x12\x1f\n" +
+	"\vnext_offset\x18\x02 \x01(\x03R\n" +
+	"nextOffset\"\xe0\x01\n" +
+	"\fArtifactInfo\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x03R\x04size\x12\x16\n" +
+	"\x06sha256\x18\x04 \x01(\tR\x06sha256\x12!\n" +
+	"\fcontent_type\x18\x05 \x01(\tR\vcontentType\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x1b\n" +
+	"\tupload_id\x18\a \x01(\tR\buploadId\"{\n" +
+	"\x0eArtifactHeader\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x1b\n" +
+	"\tworker_id\x18\x02 \x01(\tR\bworkerId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12!\n" +
+	"\fcontent_type\x18\x04 \x01(\tR\vcontentType\"Y\n" +
+	"\rArtifactChunk\x124\n" +
+	"\x06header\x18\x01 \x01(\v2\x1c.orchestrator.ArtifactHeaderR\x06header\x12\x12\n" +
+	"\x04data\x18\x02 \x01(\fR\x04data\"P\n" +
+	"\x16UploadArtifactResponse\x126\n" +
+	"\bartifact\x18\x01 \x01(\v2\x1a.orchestrator.ArtifactInfoR\bartifact\"j\n" +
+	"\x13UploadInputResponse\x12\x1b\n" +
+	"\tupload_id\x18\x01 \x01(\tR\buploadId\x126\n" +
+	"\bartifact\x18\x02 \x01(\v2\x1a.orchestrator.ArtifactInfoR\bartifact\"-\n" +
+	"\x14ListArtifactsRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"Q\n" +
+	"\x15ListArtifactsResponse\x128\n" +
+	"\tartifacts\x18\x01 \x03(\v2\x1a.orchestrator.ArtifactInfoR\tartifacts\"a\n" +
+	"\x17DownloadArtifactRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1b\n" +
+	"\tupload_id\x18\x03 \x01(\tR\buploadId\"R\n" +
+	"\fArtifactData\x12.\n" +
+	"\x04info\x18\x01 \x01(\v2\x1a.orchestrator.ArtifactInfoR\x04info\x12\x12\n" +
+	"\x04data\x18\x02 \x01(\fR\x04data\"\x16\n" +
+	"\x14ListTaskTypesRequest\"\xe6\x03\n" +
+	"\bTaskType\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x14\n" +
+	"\x05queue\x18\x03 \x01(\tR\x05queue\x123\n" +
+	"\atimeout\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\atimeout\x12!\n" +
+	"\fmax_attempts\x18\x05 \x01(\x05R\vmaxAttempts\x12B\n" +
+	"\x0finitial_backoff\x18\x06 \x01(\v2\x19.google.protobuf.DurationR\x0einitialBackoff\x12:\n" +
+	"\vmax_backoff\x18\a \x01(\v2\x19.google.protobuf.DurationR\n" +
+	"maxBackoff\x12\x1f\n" +
+	"\vargs_schema\x18\b \x01(\tR\n" +
+	"argsSchema\x12%\n" +
+	"\x0epayload_schema\x18\t \x01(\tR\rpayloadSchema\x12\x18\n" +
+	"\aversion\x18\n" +
+	" \x01(\tR\aversion\x12\x1c\n" +
+	"\tcacheable\x18\v \x01(\bR\tcacheable\x126\n" +
+	"\tcache_ttl\x18\f \x01(\v2\x19.google.protobuf.DurationR\bcacheTtl\"f\n" +
+	"\x15ListTaskTypesResponse\x125\n" +
+	"\n" +
+	"task_types\x18\x01 \x03(\v2\x16.orchestrator.TaskTypeR\ttaskTypes\x12\x16\n" +
+	"\x06strict\x18\x02 \x01(\bR\x06strict\"\xed\x01\n" +
+	"\n" +
+	"CacheEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x12\n" +
+	"\x04task\x18\x02 \x01(\tR\x04task\x12\x15\n" +
+	"\x06job_id\x18\x03 \x01(\tR\x05jobId\x129\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"expires_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12\x18\n" +
+	"\aexpired\x18\x06 \x01(\bR\aexpired\x12\x12\n" +
+	"\x04hits\x18\a \x01(\x05R\x04hits\"h\n" +
+	"\x17ListCacheEntriesRequest\x12\x12\n" +
+	"\x04task\x18\x01 \x01(\tR\x04task\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12'\n" +
+	"\x0finclude_expired\x18\x03 \x01(\bR\x0eincludeExpired\"N\n" +
+	"\x18ListCacheEntriesResponse\x122\n" +
+	"\aentries\x18\x01 \x03(\v2\x18.orchestrator.CacheEntryR\aentries\"P\n" +
+	"\x16InvalidateCacheRequest\x12\x12\n" +
+	"\x04task\x18\x01 \x01(\tR\x04task\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12\x10\n" +
+	"\x03all\x18\x03 \x01(\bR\x03all\";\n" +
+	"\x17InvalidateCacheResponse\x12 \n" +
+	"\vinvalidated\x18\x01 \x01(\x05R\vinvalidated\"\x14\n" +
+	"\x12ListWorkersRequest\"\x9b\x02\n" +
+	"\rWorkerDetails\x12\x1b\n" +
+	"\tworker_id\x18\x01 \x01(\tR\bworkerId\x12\x12\n" +
+	"\x04host\x18\x02 \x01(\tR\x04host\x127\n" +
+	"\tlast_seen\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\blastSeen\x12\x1c\n" +
+	"\tconnected\x18\x04 \x01(\bR\tconnected\x122\n" +
+	"\x05tasks\x18\x05 \x03(\v2\x1c.orchestrator.TaskCapabilityR\x05tasks\x12\x19\n" +
+	"\bany_task\x18\x06 \x01(\bR\aanyTask\x123\n" +
+	"\bcapacity\x18\a \x01(\v2\x17.orchestrator.ResourcesR\bcapacity\"L\n" +
+	"\x13ListWorkersResponse\x125\n" +
+	"\aworkers\x18\x01 \x03(\v2\x1b.orchestrator.WorkerDetailsR\aworkers*X\n" +
+	"\vUniqueScope\x12\x17\n" +
+	"\x13UNIQUE_SCOPE_QUEUED\x10\x00\x12\x17\n" +
+	"\x13UNIQUE_SCOPE_ACTIVE\x10\x01\x12\x17\n" +
+	"\x13UNIQUE_SCOPE_WINDOW\x10\x022\xce\x0e\n" +
 	"\fOrchestrator\x12@\n" +
 	"\tSubmitJob\x12\x18.orchestrator.JobRequest\x1a\x19.orchestrator.JobResponse\x12O\n" +
-	"\fGetJobStatus\x12\x1e.orchestrator.JobStatusRequest\x1a\x1f.orchestrator.JobStatusResponse\x12[\n" +
+	"\fGetJobStatus\x12\x1e.orchestrator.JobStatusRequest\x1a\x1f.orchestrator.JobStatusResponse\x12M\n" +
+	"\bWatchJob\x12\x1e.orchestrator.JobStatusRequest\x1a\x1f.orchestrator.JobStatusResponse0\x01\x12[\n" +
 	"\x0eRegisterWorker\x12#.orchestrator.RegisterWorkerRequest\x1a$.orchestrator.RegisterWorkerResponse\x12P\n" +
 	"\rSendHeartbeat\x12\x1e.orchestrator.HeartbeatRequest\x1a\x1f.orchestrator.HeartbeatResponse\x12F\n" +
-	"\aPullJob\x12\x1c.orchestrator.PullJobRequest\x1a\x1d.orchestrator.PullJobResponse\x12R\n" +
-	"\vCompleteJob\x12 .orchestrator.CompleteJobRequest\x1a!.orchestrator.CompleteJobResponse\x12I\n" +
+	"\aPullJob\x12\x1c.orchestrator.PullJobRequest\x1a\x1d.orchestrator.PullJobResponse\x12I\n" +
+	"\bStartJob\x12\x1d.orchestrator.StartJobRequest\x1a\x1e.orchestrator.StartJobResponse\x12R\n" +
+	"\vCompleteJob\x12 .orchestrator.CompleteJobRequest\x1a!.orchestrator.CompleteJobResponse\x12L\n" +
+	"\tCancelJob\x12\x1e.orchestrator.CancelJobRequest\x1a\x1f.orchestrator.CancelJobResponse\x12R\n" +
+	"\rGetJobHistory\x12\x1f.orchestrator.JobHistoryRequest\x1a .orchestrator.JobHistoryResponse\x12I\n" +
 	"\bListJobs\x12\x1d.orchestrator.ListJobsRequest\x1a\x1e.orchestrator.ListJobsResponse\x12>\n" +
 	"\n" +
-	"StreamLogs\x12\x16.orchestrator.LogEntry\x1a\x14.orchestrator.LogAck(\x010\x01B Z\x1edistributed-orchestrator/protob\x06proto3"
+	"StreamLogs\x12\x16.orchestrator.LogEntry\x1a\x14.orchestrator.LogAck(\x010\x01\x12<\n" +
+	"\bShipLogs\x12\x16.orchestrator.LogBatch\x1a\x14.orchestrator.LogAck(\x010\x01\x12I\n" +
+	"\n" +
+	"GetJobLogs\x12\x1c.orchestrator.JobLogsRequest\x1a\x1d.orchestrator.JobLogsResponse\x12D\n" +
+	"\vTailJobLogs\x12\x1c.orchestrator.JobLogsRequest\x1a\x15.orchestrator.LogLine0\x01\x12U\n" +
+	"\x0eUploadArtifact\x12\x1b.orchestrator.ArtifactChunk\x1a$.orchestrator.UploadArtifactResponse(\x01\x12X\n" +
+	"\rListArtifacts\x12\".orchestrator.ListArtifactsRequest\x1a#.orchestrator.ListArtifactsResponse\x12W\n" +
+	"\x10DownloadArtifact\x12%.orchestrator.DownloadArtifactRequest\x1a\x1a.orchestrator.ArtifactData0\x01\x12O\n" +
+	"\vUploadInput\x12\x1b.orchestrator.ArtifactChunk\x1a!.orchestrator.UploadInputResponse(\x01\x12X\n" +
+	"\rListTaskTypes\x12\".orchestrator.ListTaskTypesRequest\x1a#.orchestrator.ListTaskTypesResponse\x12R\n" +
+	"\vListWorkers\x12 .orchestrator.ListWorkersRequest\x1a!.orchestrator.ListWorkersResponse\x12a\n" +
+	"\x10ListCacheEntries\x12%.orchestrator.ListCacheEntriesRequest\x1a&.orchestrator.ListCacheEntriesResponse\x12^\n" +
+	"\x0fInvalidateCache\x12$.orchestrator.InvalidateCacheRequest\x1a%.orchestrator.InvalidateCacheResponseB Z\x1edistributed-orchestrator/protob\x06proto3"
 
 var (
 	file_proto_orchestrator_proto_rawDescOnce sync.Once
@@ -940,49 +4007,178 @@ func file_proto_orchestrator_proto_rawDescGZIP() []byte {
 	return file_proto_orchestrator_proto_rawDescData
 }
 
-var file_proto_orchestrator_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_proto_orchestrator_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_proto_orchestrator_proto_msgTypes = make([]protoimpl.MessageInfo, 55)
 var file_proto_orchestrator_proto_goTypes = []any{
-	(*JobRequest)(nil),             // 0: orchestrator.JobRequest
-	(*JobResponse)(nil),            // 1: orchestrator.JobResponse
-	(*JobStatusRequest)(nil),       // 2: orchestrator.JobStatusRequest
-	(*JobStatusResponse)(nil),      // 3: orchestrator.JobStatusResponse
-	(*RegisterWorkerRequest)(nil),  // 4: orchestrator.RegisterWorkerRequest
-	(*RegisterWorkerResponse)(nil), // 5: orchestrator.RegisterWorkerResponse
-	(*HeartbeatRequest)(nil),       // 6: orchestrator.HeartbeatRequest
-	(*HeartbeatResponse)(nil),      // 7: orchestrator.HeartbeatResponse
-	(*PullJobRequest)(nil),         // 8: orchestrator.PullJobRequest
-	(*PullJobResponse)(nil),        // 9: orchestrator.PullJobResponse
-	(*CompleteJobRequest)(nil),     // 10: orchestrator.CompleteJobRequest
-	(*CompleteJobResponse)(nil),    // 11: orchestrator.CompleteJobResponse
-	(*ListJobsRequest)(nil),        // 12: orchestrator.ListJobsRequest
-	(*ListJobsResponse)(nil),       // 13: orchestrator.ListJobsResponse
-	(*JobStatus)(nil),              // 14: orchestrator.JobStatus
-	(*LogEntry)(nil),               // 15: orchestrator.LogEntry
-	(*LogAck)(nil),                 // 16: orchestrator.LogAck
+	(UniqueScope)(0),                 // 0: orchestrator.UniqueScope
+	(*JobRequest)(nil),               // 1: orchestrator.JobRequest
+	(*Resources)(nil),                // 2: orchestrator.Resources
+	(*UniquePolicy)(nil),             // 3: orchestrator.UniquePolicy
+	(*JobInput)(nil),                 // 4: orchestrator.JobInput
+	(*ArtifactRef)(nil),              // 5: orchestrator.ArtifactRef
+	(*JobOutput)(nil),                // 6: orchestrator.JobOutput
+	(*JobResponse)(nil),              // 7: orchestrator.JobResponse
+	(*JobStatusRequest)(nil),         // 8: orchestrator.JobStatusRequest
+	(*JobStatusResponse)(nil),        // 9: orchestrator.JobStatusResponse
+	(*JobDetails)(nil),               // 10: orchestrator.JobDetails
+	(*RegisterWorkerRequest)(nil),    // 11: orchestrator.RegisterWorkerRequest
+	(*TaskCapability)(nil),           // 12: orchestrator.TaskCapability
+	(*RegisterWorkerResponse)(nil),   // 13: orchestrator.RegisterWorkerResponse
+	(*HeartbeatRequest)(nil),         // 14: orchestrator.HeartbeatRequest
+	(*HeartbeatResponse)(nil),        // 15: orchestrator.HeartbeatResponse
+	(*PullJobRequest)(nil),           // 16: orchestrator.PullJobRequest
+	(*PullJobResponse)(nil),          // 17: orchestrator.PullJobResponse
+	(*StartJobRequest)(nil),          // 18: orchestrator.StartJobRequest
+	(*StartJobResponse)(nil),         // 19: orchestrator.StartJobResponse
+	(*CompleteJobRequest)(nil),       // 20: orchestrator.CompleteJobRequest
+	(*CompleteJobResponse)(nil),      // 21: orchestrator.CompleteJobResponse
+	(*CancelJobRequest)(nil),         // 22: orchestrator.CancelJobRequest
+	(*CancelJobResponse)(nil),        // 23: orchestrator.CancelJobResponse
+	(*JobHistoryRequest)(nil),        // 24: orchestrator.JobHistoryRequest
+	(*JobTransition)(nil),            // 25: orchestrator.JobTransition
+	(*JobHistoryResponse)(nil),       // 26: orchestrator.JobHistoryResponse
+	(*ListJobsRequest)(nil),          // 27: orchestrator.ListJobsRequest
+	(*ListJobsResponse)(nil),         // 28: orchestrator.ListJobsResponse
+	(*JobStatus)(nil),                // 29: orchestrator.JobStatus
+	(*LogEntry)(nil),                 // 30: orchestrator.LogEntry
+	(*LogBatch)(nil),                 // 31: orchestrator.LogBatch
+	(*LogAck)(nil),                   // 32: orchestrator.LogAck
+	(*LogLine)(nil),                  // 33: orchestrator.LogLine
+	(*JobLogsRequest)(nil),           // 34: orchestrator.JobLogsRequest
+	(*JobLogsResponse)(nil),          // 35: orchestrator.JobLogsResponse
+	(*ArtifactInfo)(nil),             // 36: orchestrator.ArtifactInfo
+	(*ArtifactHeader)(nil),           // 37: orchestrator.ArtifactHeader
+	(*ArtifactChunk)(nil),            // 38: orchestrator.ArtifactChunk
+	(*UploadArtifactResponse)(nil),   // 39: orchestrator.UploadArtifactResponse
+	(*UploadInputResponse)(nil),      // 40: orchestrator.UploadInputResponse
+	(*ListArtifactsRequest)(nil),     // 41: orchestrator.ListArtifactsRequest
+	(*ListArtifactsResponse)(nil),    // 42: orchestrator.ListArtifactsResponse
+	(*DownloadArtifactRequest)(nil),  // 43: orchestrator.DownloadArtifactRequest
+	(*ArtifactData)(nil),             // 44: orchestrator.ArtifactData
+	(*ListTaskTypesRequest)(nil),     // 45: orchestrator.ListTaskTypesRequest
+	(*TaskType)(nil),                 // 46: orchestrator.TaskType
+	(*ListTaskTypesResponse)(nil),    // 47: orchestrator.ListTaskTypesResponse
+	(*CacheEntry)(nil),               // 48: orchestrator.CacheEntry
+	(*ListCacheEntriesRequest)(nil),  // 49: orchestrator.ListCacheEntriesRequest
+	(*ListCacheEntriesResponse)(nil), // 50: orchestrator.ListCacheEntriesResponse
+	(*InvalidateCacheRequest)(nil),   // 51: orchestrator.InvalidateCacheRequest
+	(*InvalidateCacheResponse)(nil),  // 52: orchestrator.InvalidateCacheResponse
+	(*ListWorkersRequest)(nil),       // 53: orchestrator.ListWorkersRequest
+	(*WorkerDetails)(nil),            // 54: orchestrator.WorkerDetails
+	(*ListWorkersResponse)(nil),      // 55: orchestrator.ListWorkersResponse
+	(*structpb.Struct)(nil),          // 56: google.protobuf.Struct
+	(*durationpb.Duration)(nil),      // 57: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil),    // 58: google.protobuf.Timestamp
 }
 var file_proto_orchestrator_proto_depIdxs = []int32{
-	14, // 0: orchestrator.ListJobsResponse.jobs:type_name -> orchestrator.JobStatus
-	0,  // 1: orchestrator.Orchestrator.SubmitJob:input_type -> orchestrator.JobRequest
-	2,  // 2: orchestrator.Orchestrator.GetJobStatus:input_type -> orchestrator.JobStatusRequest
-	4,  // 3: orchestrator.Orchestrator.RegisterWorker:input_type -> orchestrator.RegisterWorkerRequest
-	6,  // 4: orchestrator.Orchestrator.SendHeartbeat:input_type -> orchestrator.HeartbeatRequest
-	8,  // 5: orchestrator.Orchestrator.PullJob:input_type -> orchestrator.PullJobRequest
-	10, // 6: orchestrator.Orchestrator.CompleteJob:input_type -> orchestrator.CompleteJobRequest
-	12, // 7: orchestrator.Orchestrator.ListJobs:input_type -> orchestrator.ListJobsRequest
-	15, // 8: orchestrator.Orchestrator.StreamLogs:input_type -> orchestrator.LogEntry
-	1,  // 9: orchestrator.Orchestrator.SubmitJob:output_type -> orchestrator.JobResponse
-	3,  // 10: orchestrator.Orchestrator.GetJobStatus:output_type -> orchestrator.JobStatusResponse
-	5,  // 11: orchestrator.Orchestrator.RegisterWorker:output_type -> orchestrator.RegisterWorkerResponse
-	7,  // 12: orchestrator.Orchestrator.SendHeartbeat:output_type -> orchestrator.HeartbeatResponse
-	9,  // 13: orchestrator.Orchestrator.PullJob:output_type -> orchestrator.PullJobResponse
-	11, // 14: orchestrator.Orchestrator.CompleteJob:output_type -> orchestrator.CompleteJobResponse
-	13, // 15: orchestrator.Orchestrator.ListJobs:output_type -> orchestrator.ListJobsResponse
-	16, // 16: orchestrator.Orchestrator.StreamLogs:output_type -> orchestrator.LogAck
-	9,  // [9:17] is the sub-list for method output_type
-	1,  // [1:9] is the sub-list for method input_type
-	1,  // [1:1] is the sub-list for extension type_name
-	1,  // [1:1] is the sub-list for extension extendee
-	0,  // [0:1] is the sub-list for field type_name
+	4,  // 0: orchestrator.JobRequest.inputs:type_name -> orchestrator.JobInput
+	6,  // 1: orchestrator.JobRequest.outputs:type_name -> orchestrator.JobOutput
+	56, // 2: orchestrator.JobRequest.payload:type_name -> google.protobuf.Struct
+	57, // 3: orchestrator.JobRequest.timeout:type_name -> google.protobuf.Duration
+	3,  // 4: orchestrator.JobRequest.unique:type_name -> orchestrator.UniquePolicy
+	2,  // 5: orchestrator.JobRequest.resources:type_name -> orchestrator.Resources
+	0,  // 6: orchestrator.UniquePolicy.scope:type_name -> orchestrator.UniqueScope
+	57, // 7: orchestrator.UniquePolicy.window:type_name -> google.protobuf.Duration
+	5,  // 8: orchestrator.JobInput.artifact:type_name -> orchestrator.ArtifactRef
+	10, // 9: orchestrator.JobStatusResponse.details:type_name -> orchestrator.JobDetails
+	56, // 10: orchestrator.JobStatusResponse.output:type_name -> google.protobuf.Struct
+	58, // 11: orchestrator.JobDetails.created_at:type_name -> google.protobuf.Timestamp
+	58, // 12: orchestrator.JobDetails.queued_at:type_name -> google.protobuf.Timestamp
+	58, // 13: orchestrator.JobDetails.started_at:type_name -> google.protobuf.Timestamp
+	58, // 14: orchestrator.JobDetails.finished_at:type_name -> google.protobuf.Timestamp
+	57, // 15: orchestrator.JobDetails.queue_duration:type_name -> google.protobuf.Duration
+	57, // 16: orchestrator.JobDetails.run_duration:type_name -> google.protobuf.Duration
+	58, // 17: orchestrator.JobDetails.lease_expires_at:type_name -> google.protobuf.Timestamp
+	4,  // 18: orchestrator.JobDetails.inputs:type_name -> orchestrator.JobInput
+	6,  // 19: orchestrator.JobDetails.outputs:type_name -> orchestrator.JobOutput
+	56, // 20: orchestrator.JobDetails.payload:type_name -> google.protobuf.Struct
+	57, // 21: orchestrator.JobDetails.timeout:type_name -> google.protobuf.Duration
+	2,  // 22: orchestrator.JobDetails.resources:type_name -> orchestrator.Resources
+	12, // 23: orchestrator.RegisterWorkerRequest.tasks:type_name -> orchestrator.TaskCapability
+	2,  // 24: orchestrator.RegisterWorkerRequest.capacity:type_name -> orchestrator.Resources
+	57, // 25: orchestrator.PullJobRequest.wait:type_name -> google.protobuf.Duration
+	4,  // 26: orchestrator.PullJobResponse.inputs:type_name -> orchestrator.JobInput
+	6,  // 27: orchestrator.PullJobResponse.outputs:type_name -> orchestrator.JobOutput
+	56, // 28: orchestrator.PullJobResponse.payload:type_name -> google.protobuf.Struct
+	57, // 29: orchestrator.PullJobResponse.timeout:type_name -> google.protobuf.Duration
+	56, // 30: orchestrator.CompleteJobRequest.output:type_name -> google.protobuf.Struct
+	58, // 31: orchestrator.JobTransition.timestamp:type_name -> google.protobuf.Timestamp
+	25, // 32: orchestrator.JobHistoryResponse.transitions:type_name -> orchestrator.JobTransition
+	29, // 33: orchestrator.ListJobsResponse.jobs:type_name -> orchestrator.JobStatus
+	10, // 34: orchestrator.JobStatus.details:type_name -> orchestrator.JobDetails
+	56, // 35: orchestrator.JobStatus.output:type_name -> google.protobuf.Struct
+	30, // 36: orchestrator.LogBatch.entries:type_name -> orchestrator.LogEntry
+	58, // 37: orchestrator.LogLine.timestamp:type_name -> google.protobuf.Timestamp
+	33, // 38: orchestrator.JobLogsResponse.lines:type_name -> orchestrator.LogLine
+	58, // 39: orchestrator.ArtifactInfo.created_at:type_name -> google.protobuf.Timestamp
+	37, // 40: orchestrator.ArtifactChunk.header:type_name -> orchestrator.ArtifactHeader
+	36, // 41: orchestrator.UploadArtifactResponse.artifact:type_name -> orchestrator.ArtifactInfo
+	36, // 42: orchestrator.UploadInputResponse.artifact:type_name -> orchestrator.ArtifactInfo
+	36, // 43: orchestrator.ListArtifactsResponse.artifacts:type_name -> orchestrator.ArtifactInfo
+	36, // 44: orchestrator.ArtifactData.info:type_name -> orchestrator.ArtifactInfo
+	57, // 45: orchestrator.TaskType.timeout:type_name -> google.protobuf.Duration
+	57, // 46: orchestrator.TaskType.initial_backoff:type_name -> google.protobuf.Duration
+	57, // 47: orchestrator.TaskType.max_backoff:type_name -> google.protobuf.Duration
+	57, // 48: orchestrator.TaskType.cache_ttl:type_name -> google.protobuf.Duration
+	46, // 49: orchestrator.ListTaskTypesResponse.task_types:type_name -> orchestrator.TaskType
+	58, // 50: orchestrator.CacheEntry.created_at:type_name -> google.protobuf.Timestamp
+	58, // 51: orchestrator.CacheEntry.expires_at:type_name -> google.protobuf.Timestamp
+	48, // 52: orchestrator.ListCacheEntriesResponse.entries:type_name -> orchestrator.CacheEntry
+	58, // 53: orchestrator.WorkerDetails.last_seen:type_name -> google.protobuf.Timestamp
+	12, // 54: orchestrator.WorkerDetails.tasks:type_name -> orchestrator.TaskCapability
+	2,  // 55: orchestrator.WorkerDetails.capacity:type_name -> orchestrator.Resources
+	54, // 56: orchestrator.ListWorkersResponse.workers:type_name -> orchestrator.WorkerDetails
+	1,  // 57: orchestrator.Orchestrator.SubmitJob:input_type -> orchestrator.JobRequest
+	8,  // 58: orchestrator.Orchestrator.GetJobStatus:input_type -> orchestrator.JobStatusRequest
+	8,  // 59: orchestrator.Orchestrator.WatchJob:input_type -> orchestrator.JobStatusRequest
+	11, // 60: orchestrator.Orchestrator.RegisterWorker:input_type -> orchestrator.RegisterWorkerRequest
+	14, // 61: orchestrator.Orchestrator.SendHeartbeat:input_type -> orchestrator.HeartbeatRequest
+	16, // 62: orchestrator.Orchestrator.PullJob:input_type -> orchestrator.PullJobRequest
+	18, // 63: orchestrator.Orchestrator.StartJob:input_type -> orchestrator.StartJobRequest
+	20, // 64: orchestrator.Orchestrator.CompleteJob:input_type -> orchestrator.CompleteJobRequest
+	22, // 65: orchestrator.Orchestrator.CancelJob:input_type -> orchestrator.CancelJobRequest
+	24, // 66: orchestrator.Orchestrator.GetJobHistory:input_type -> orchestrator.JobHistoryRequest
+	27, // 67: orchestrator.Orchestrator.ListJobs:input_type -> orchestrator.ListJobsRequest
+	30, // 68: orchestrator.Orchestrator.StreamLogs:input_type -> orchestrator.LogEntry
+	31, // 69: orchestrator.Orchestrator.ShipLogs:input_type -> orchestrator.LogBatch
+	34, // 70: orchestrator.Orchestrator.GetJobLogs:input_type -> orchestrator.JobLogsRequest
+	34, // 71: orchestrator.Orchestrator.TailJobLogs:input_type -> orchestrator.JobLogsRequest
+	38, // 72: orchestrator.Orchestrator.UploadArtifact:input_type -> orchestrator.ArtifactChunk
+	41, // 73: orchestrator.Orchestrator.ListArtifacts:input_type -> orchestrator.ListArtifactsRequest
+	43, // 74: orchestrator.Orchestrator.DownloadArtifact:input_type -> orchestrator.DownloadArtifactRequest
+	38, // 75: orchestrator.Orchestrator.UploadInput:input_type -> orchestrator.ArtifactChunk
+	45, // 76: orchestrator.Orchestrator.ListTaskTypes:input_type -> orchestrator.ListTaskTypesRequest
+	53, // 77: orchestrator.Orchestrator.ListWorkers:input_type -> orchestrator.ListWorkersRequest
+	49, // 78: orchestrator.Orchestrator.ListCacheEntries:input_type -> orchestrator.ListCacheEntriesRequest
+	51, // 79: orchestrator.Orchestrator.InvalidateCache:input_type -> orchestrator.InvalidateCacheRequest
+	7,  // 80: orchestrator.Orchestrator.SubmitJob:output_type -> orchestrator.JobResponse
+	9,  // 81: orchestrator.Orchestrator.GetJobStatus:output_type -> orchestrator.JobStatusResponse
+	9,  // 82: orchestrator.Orchestrator.WatchJob:output_type -> orchestrator.JobStatusResponse
+	13, // 83: orchestrator.Orchestrator.RegisterWorker:output_type -> orchestrator.RegisterWorkerResponse
+	15, // 84: orchestrator.Orchestrator.SendHeartbeat:output_type -> orchestrator.HeartbeatResponse
+	17, // 85: orchestrator.Orchestrator.PullJob:output_type -> orchestrator.PullJobResponse
+	19, // 86: orchestrator.Orchestrator.StartJob:output_type -> orchestrator.StartJobResponse
+	21, // 87: orchestrator.Orchestrator.CompleteJob:output_type -> orchestrator.CompleteJobResponse
+	23, // 88: orchestrator.Orchestrator.CancelJob:output_type -> orchestrator.CancelJobResponse
+	26, // 89: orchestrator.Orchestrator.GetJobHistory:output_type -> orchestrator.JobHistoryResponse
+	28, // 90: orchestrator.Orchestrator.ListJobs:output_type -> orchestrator.ListJobsResponse
+	32, // 91: orchestrator.Orchestrator.StreamLogs:output_type -> orchestrator.LogAck
+	32, // 92: orchestrator.Orchestrator.ShipLogs:output_type -> orchestrator.LogAck
+	35, // 93: orchestrator.Orchestrator.GetJobLogs:output_type -> orchestrator.JobLogsResponse
+	33, // 94: orchestrator.Orchestrator.TailJobLogs:output_type -> orchestrator.LogLine
+	39, // 95: orchestrator.Orchestrator.UploadArtifact:output_type -> orchestrator.UploadArtifactResponse
+	42, // 96: orchestrator.Orchestrator.ListArtifacts:output_type -> orchestrator.ListArtifactsResponse
+	44, // 97: orchestrator.Orchestrator.DownloadArtifact:output_type -> orchestrator.ArtifactData
+	40, // 98: orchestrator.Orchestrator.UploadInput:output_type -> orchestrator.UploadInputResponse
+	47, // 99: orchestrator.Orchestrator.ListTaskTypes:output_type -> orchestrator.ListTaskTypesResponse
+	55, // 100: orchestrator.Orchestrator.ListWorkers:output_type -> orchestrator.ListWorkersResponse
+	50, // 101: orchestrator.Orchestrator.ListCacheEntries:output_type -> orchestrator.ListCacheEntriesResponse
+	52, // 102: orchestrator.Orchestrator.InvalidateCache:output_type -> orchestrator.InvalidateCacheResponse
+	80, // [80:103] is the sub-list for method output_type
+	57, // [57:80] is the sub-list for method input_type
+	57, // [57:57] is the sub-list for extension type_name
+	57, // [57:57] is the sub-list for extension extendee
+	0,  // [0:57] is the sub-list for field type_name
 }
 
 func init() { file_proto_orchestrator_proto_init() }
@@ -990,18 +4186,24 @@ func file_proto_orchestrator_proto_init() {
 	if File_proto_orchestrator_proto != nil {
 		return
 	}
+	file_proto_orchestrator_proto_msgTypes[0].OneofWrappers = []any{}
+	file_proto_orchestrator_proto_msgTypes[3].OneofWrappers = []any{
+		(*JobInput_UploadId)(nil),
+		(*JobInput_Artifact)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_orchestrator_proto_rawDesc), len(file_proto_orchestrator_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   17,
+			NumEnums:      1,
+			NumMessages:   55,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_proto_orchestrator_proto_goTypes,
 		DependencyIndexes: file_proto_orchestrator_proto_depIdxs,
+		EnumInfos:         file_proto_orchestrator_proto_enumTypes,
 		MessageInfos:      file_proto_orchestrator_proto_msgTypes,
 	}.Build()
 	File_proto_orchestrator_proto = out.File
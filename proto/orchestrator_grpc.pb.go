@@ -19,14 +19,29 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Orchestrator_SubmitJob_FullMethodName      = "/orchestrator.Orchestrator/SubmitJob"
-	Orchestrator_GetJobStatus_FullMethodName   = "/orchestrator.Orchestrator/GetJobStatus"
-	Orchestrator_RegisterWorker_FullMethodName = "/orchestrator.Orchestrator/RegisterWorker"
-	Orchestrator_SendHeartbeat_FullMethodName  = "/orchestrator.Orchestrator/SendHeartbeat"
-	Orchestrator_PullJob_FullMethodName        = "/orchestrator.Orchestrator/PullJob"
-	Orchestrator_CompleteJob_FullMethodName    = "/orchestrator.Orchestrator/CompleteJob"
-	Orchestrator_ListJobs_FullMethodName       = "/orchestrator.Orchestrator/ListJobs"
-	Orchestrator_StreamLogs_FullMethodName     = "/orchestrator.Orchestrator/StreamLogs"
+	Orchestrator_SubmitJob_FullMethodName        = "/orchestrator.Orchestrator/SubmitJob"
+	Orchestrator_GetJobStatus_FullMethodName     = "/orchestrator.Orchestrator/GetJobStatus"
+	Orchestrator_WatchJob_FullMethodName         = "/orchestrator.Orchestrator/WatchJob"
+	Orchestrator_RegisterWorker_FullMethodName   = "/orchestrator.Orchestrator/RegisterWorker"
+	Orchestrator_SendHeartbeat_FullMethodName    = "/orchestrator.Orchestrator/SendHeartbeat"
+	Orchestrator_PullJob_FullMethodName          = "/orchestrator.Orchestrator/PullJob"
+	Orchestrator_StartJob_FullMethodName         = "/orchestrator.Orchestrator/StartJob"
+	Orchestrator_CompleteJob_FullMethodName      = "/orchestrator.Orchestrator/CompleteJob"
+	Orchestrator_CancelJob_FullMethodName        = "/orchestrator.Orchestrator/CancelJob"
+	Orchestrator_GetJobHistory_FullMethodName    = "/orchestrator.Orchestrator/GetJobHistory"
+	Orchestrator_ListJobs_FullMethodName         = "/orchestrator.Orchestrator/ListJobs"
+	Orchestrator_StreamLogs_FullMethodName       = "/orchestrator.Orchestrator/StreamLogs"
+	Orchestrator_ShipLogs_FullMethodName         = "/orchestrator.Orchestrator/ShipLogs"
+	Orchestrator_GetJobLogs_FullMethodName       = "/orchestrator.Orchestrator/GetJobLogs"
+	Orchestrator_TailJobLogs_FullMethodName      = "/orchestrator.Orchestrator/TailJobLogs"
+	Orchestrator_UploadArtifact_FullMethodName   = "/orchestrator.Orchestrator/UploadArtifact"
+	Orchestrator_ListArtifacts_FullMethodName    = "/orchestrator.Orchestrator/ListArtifacts"
+	Orchestrator_DownloadArtifact_FullMethodName = "/orchestrator.Orchestrator/DownloadArtifact"
+	Orchestrator_UploadInput_FullMethodName      = "/orchestrator.Orchestrator/UploadInput"
+	Orchestrator_ListTaskTypes_FullMethodName    = "/orchestrator.Orchestrator/ListTaskTypes"
+	Orchestrator_ListWorkers_FullMethodName      = "/orchestrator.Orchestrator/ListWorkers"
+	Orchestrator_ListCacheEntries_FullMethodName = "/orchestrator.Orchestrator/ListCacheEntries"
+	Orchestrator_InvalidateCache_FullMethodName  = "/orchestrator.Orchestrator/InvalidateCache"
 )
 
 // OrchestratorClient is the client API for Orchestrator service.
@@ -37,15 +52,34 @@ const (
 type OrchestratorClient interface {
 	SubmitJob(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*JobResponse, error)
 	GetJobStatus(ctx context.Context, in *JobStatusRequest, opts ...grpc.CallOption) (*JobStatusResponse, error)
+	WatchJob(ctx context.Context, in *JobStatusRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[JobStatusResponse], error)
 	RegisterWorker(ctx context.Context, in *RegisterWorkerRequest, opts ...grpc.CallOption) (*RegisterWorkerResponse, error)
 	SendHeartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error)
 	// New methods for worker pull-complete flow
 	PullJob(ctx context.Context, in *PullJobRequest, opts ...grpc.CallOption) (*PullJobResponse, error)
+	StartJob(ctx context.Context, in *StartJobRequest, opts ...grpc.CallOption) (*StartJobResponse, error)
 	CompleteJob(ctx context.Context, in *CompleteJobRequest, opts ...grpc.CallOption) (*CompleteJobResponse, error)
+	CancelJob(ctx context.Context, in *CancelJobRequest, opts ...grpc.CallOption) (*CancelJobResponse, error)
+	GetJobHistory(ctx context.Context, in *JobHistoryRequest, opts ...grpc.CallOption) (*JobHistoryResponse, error)
 	// New method for TUI dashboard
 	ListJobs(ctx context.Context, in *ListJobsRequest, opts ...grpc.CallOption) (*ListJobsResponse, error)
 	// Method for streaming logs from worker to scheduler
 	StreamLogs(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[LogEntry, LogAck], error)
+	// Long-lived batched variant of StreamLogs; one ack per batch.
+	ShipLogs(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[LogBatch, LogAck], error)
+	GetJobLogs(ctx context.Context, in *JobLogsRequest, opts ...grpc.CallOption) (*JobLogsResponse, error)
+	// Sends the job's log from offset and follows new lines until the job
+	// finishes; limit is ignored.
+	TailJobLogs(ctx context.Context, in *JobLogsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LogLine], error)
+	UploadArtifact(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ArtifactChunk, UploadArtifactResponse], error)
+	ListArtifacts(ctx context.Context, in *ListArtifactsRequest, opts ...grpc.CallOption) (*ListArtifactsResponse, error)
+	DownloadArtifact(ctx context.Context, in *DownloadArtifactRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ArtifactData], error)
+	// Stores a file for later jobs to take as an input.
+	UploadInput(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ArtifactChunk, UploadInputResponse], error)
+	ListTaskTypes(ctx context.Context, in *ListTaskTypesRequest, opts ...grpc.CallOption) (*ListTaskTypesResponse, error)
+	ListWorkers(ctx context.Context, in *ListWorkersRequest, opts ...grpc.CallOption) (*ListWorkersResponse, error)
+	ListCacheEntries(ctx context.Context, in *ListCacheEntriesRequest, opts ...grpc.CallOption) (*ListCacheEntriesResponse, error)
+	InvalidateCache(ctx context.Context, in *InvalidateCacheRequest, opts ...grpc.CallOption) (*InvalidateCacheResponse, error)
 }
 
 type orchestratorClient struct {
@@ -76,6 +110,25 @@ func (c *orchestratorClient) GetJobStatus(ctx context.Context, in *JobStatusRequ
 	return out, nil
 }
 
+func (c *orchestratorClient) WatchJob(ctx context.Context, in *JobStatusRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[JobStatusResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Orchestrator_ServiceDesc.Streams[0], Orchestrator_WatchJob_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[JobStatusRequest, JobStatusResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Orchestrator_WatchJobClient = grpc.ServerStreamingClient[JobStatusResponse]
+
 func (c *orchestratorClient) RegisterWorker(ctx context.Context, in *RegisterWorkerRequest, opts ...grpc.CallOption) (*RegisterWorkerResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(RegisterWorkerResponse)
@@ -106,6 +159,16 @@ func (c *orchestratorClient) PullJob(ctx context.Context, in *PullJobRequest, op
 	return out, nil
 }
 
+func (c *orchestratorClient) StartJob(ctx context.Context, in *StartJobRequest, opts ...grpc.CallOption) (*StartJobResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StartJobResponse)
+	err := c.cc.Invoke(ctx, Orchestrator_StartJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *orchestratorClient) CompleteJob(ctx context.Context, in *CompleteJobRequest, opts ...grpc.CallOption) (*CompleteJobResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(CompleteJobResponse)
@@ -116,6 +179,26 @@ func (c *orchestratorClient) CompleteJob(ctx context.Context, in *CompleteJobReq
 	return out, nil
 }
 
+func (c *orchestratorClient) CancelJob(ctx context.Context, in *CancelJobRequest, opts ...grpc.CallOption) (*CancelJobResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelJobResponse)
+	err := c.cc.Invoke(ctx, Orchestrator_CancelJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorClient) GetJobHistory(ctx context.Context, in *JobHistoryRequest, opts ...grpc.CallOption) (*JobHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobHistoryResponse)
+	err := c.cc.Invoke(ctx, Orchestrator_GetJobHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *orchestratorClient) ListJobs(ctx context.Context, in *ListJobsRequest, opts ...grpc.CallOption) (*ListJobsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ListJobsResponse)
@@ -128,7 +211,7 @@ func (c *orchestratorClient) ListJobs(ctx context.Context, in *ListJobsRequest,
 
 func (c *orchestratorClient) StreamLogs(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[LogEntry, LogAck], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	stream, err := c.cc.NewStream(ctx, &Orchestrator_ServiceDesc.Streams[0], Orchestrator_StreamLogs_FullMethodName, cOpts...)
+	stream, err := c.cc.NewStream(ctx, &Orchestrator_ServiceDesc.Streams[1], Orchestrator_StreamLogs_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -139,6 +222,143 @@ func (c *orchestratorClient) StreamLogs(ctx context.Context, opts ...grpc.CallOp
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type Orchestrator_StreamLogsClient = grpc.BidiStreamingClient[LogEntry, LogAck]
 
+func (c *orchestratorClient) ShipLogs(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[LogBatch, LogAck], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Orchestrator_ServiceDesc.Streams[2], Orchestrator_ShipLogs_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[LogBatch, LogAck]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Orchestrator_ShipLogsClient = grpc.BidiStreamingClient[LogBatch, LogAck]
+
+func (c *orchestratorClient) GetJobLogs(ctx context.Context, in *JobLogsRequest, opts ...grpc.CallOption) (*JobLogsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobLogsResponse)
+	err := c.cc.Invoke(ctx, Orchestrator_GetJobLogs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorClient) TailJobLogs(ctx context.Context, in *JobLogsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LogLine], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Orchestrator_ServiceDesc.Streams[3], Orchestrator_TailJobLogs_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[JobLogsRequest, LogLine]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Orchestrator_TailJobLogsClient = grpc.ServerStreamingClient[LogLine]
+
+func (c *orchestratorClient) UploadArtifact(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ArtifactChunk, UploadArtifactResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Orchestrator_ServiceDesc.Streams[4], Orchestrator_UploadArtifact_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ArtifactChunk, UploadArtifactResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Orchestrator_UploadArtifactClient = grpc.ClientStreamingClient[ArtifactChunk, UploadArtifactResponse]
+
+func (c *orchestratorClient) ListArtifacts(ctx context.Context, in *ListArtifactsRequest, opts ...grpc.CallOption) (*ListArtifactsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListArtifactsResponse)
+	err := c.cc.Invoke(ctx, Orchestrator_ListArtifacts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorClient) DownloadArtifact(ctx context.Context, in *DownloadArtifactRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ArtifactData], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Orchestrator_ServiceDesc.Streams[5], Orchestrator_DownloadArtifact_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[DownloadArtifactRequest, ArtifactData]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Orchestrator_DownloadArtifactClient = grpc.ServerStreamingClient[ArtifactData]
+
+func (c *orchestratorClient) UploadInput(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ArtifactChunk, UploadInputResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Orchestrator_ServiceDesc.Streams[6], Orchestrator_UploadInput_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ArtifactChunk, UploadInputResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Orchestrator_UploadInputClient = grpc.ClientStreamingClient[ArtifactChunk, UploadInputResponse]
+
+func (c *orchestratorClient) ListTaskTypes(ctx context.Context, in *ListTaskTypesRequest, opts ...grpc.CallOption) (*ListTaskTypesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTaskTypesResponse)
+	err := c.cc.Invoke(ctx, Orchestrator_ListTaskTypes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorClient) ListWorkers(ctx context.Context, in *ListWorkersRequest, opts ...grpc.CallOption) (*ListWorkersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWorkersResponse)
+	err := c.cc.Invoke(ctx, Orchestrator_ListWorkers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorClient) ListCacheEntries(ctx context.Context, in *ListCacheEntriesRequest, opts ...grpc.CallOption) (*ListCacheEntriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCacheEntriesResponse)
+	err := c.cc.Invoke(ctx, Orchestrator_ListCacheEntries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorClient) InvalidateCache(ctx context.Context, in *InvalidateCacheRequest, opts ...grpc.CallOption) (*InvalidateCacheResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InvalidateCacheResponse)
+	err := c.cc.Invoke(ctx, Orchestrator_InvalidateCache_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // OrchestratorServer is the server API for Orchestrator service.
 // All implementations must embed UnimplementedOrchestratorServer
 // for forward compatibility.
@@ -147,15 +367,34 @@ type Orchestrator_StreamLogsClient = grpc.BidiStreamingClient[LogEntry, LogAck]
 type OrchestratorServer interface {
 	SubmitJob(context.Context, *JobRequest) (*JobResponse, error)
 	GetJobStatus(context.Context, *JobStatusRequest) (*JobStatusResponse, error)
+	WatchJob(*JobStatusRequest, grpc.ServerStreamingServer[JobStatusResponse]) error
 	RegisterWorker(context.Context, *RegisterWorkerRequest) (*RegisterWorkerResponse, error)
 	SendHeartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error)
 	// New methods for worker pull-complete flow
 	PullJob(context.Context, *PullJobRequest) (*PullJobResponse, error)
+	StartJob(context.Context, *StartJobRequest) (*StartJobResponse, error)
 	CompleteJob(context.Context, *CompleteJobRequest) (*CompleteJobResponse, error)
+	CancelJob(context.Context, *CancelJobRequest) (*CancelJobResponse, error)
+	GetJobHistory(context.Context, *JobHistoryRequest) (*JobHistoryResponse, error)
 	// New method for TUI dashboard
 	ListJobs(context.Context, *ListJobsRequest) (*ListJobsResponse, error)
 	// Method for streaming logs from worker to scheduler
 	StreamLogs(grpc.BidiStreamingServer[LogEntry, LogAck]) error
+	// Long-lived batched variant of StreamLogs; one ack per batch.
+	ShipLogs(grpc.BidiStreamingServer[LogBatch, LogAck]) error
+	GetJobLogs(context.Context, *JobLogsRequest) (*JobLogsResponse, error)
+	// Sends the job's log from offset and follows new lines until the job
+	// finishes; limit is ignored.
+	TailJobLogs(*JobLogsRequest, grpc.ServerStreamingServer[LogLine]) error
+	UploadArtifact(grpc.ClientStreamingServer[ArtifactChunk, UploadArtifactResponse]) error
+	ListArtifacts(context.Context, *ListArtifactsRequest) (*ListArtifactsResponse, error)
+	DownloadArtifact(*DownloadArtifactRequest, grpc.ServerStreamingServer[ArtifactData]) error
+	// Stores a file for later jobs to take as an input.
+	UploadInput(grpc.ClientStreamingServer[ArtifactChunk, UploadInputResponse]) error
+	ListTaskTypes(context.Context, *ListTaskTypesRequest) (*ListTaskTypesResponse, error)
+	ListWorkers(context.Context, *ListWorkersRequest) (*ListWorkersResponse, error)
+	ListCacheEntries(context.Context, *ListCacheEntriesRequest) (*ListCacheEntriesResponse, error)
+	InvalidateCache(context.Context, *InvalidateCacheRequest) (*InvalidateCacheResponse, error)
 	mustEmbedUnimplementedOrchestratorServer()
 }
 
@@ -172,6 +411,9 @@ func (UnimplementedOrchestratorServer) SubmitJob(context.Context, *JobRequest) (
 func (UnimplementedOrchestratorServer) GetJobStatus(context.Context, *JobStatusRequest) (*JobStatusResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetJobStatus not implemented")
 }
+func (UnimplementedOrchestratorServer) WatchJob(*JobStatusRequest, grpc.ServerStreamingServer[JobStatusResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchJob not implemented")
+}
 func (UnimplementedOrchestratorServer) RegisterWorker(context.Context, *RegisterWorkerRequest) (*RegisterWorkerResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RegisterWorker not implemented")
 }
@@ -181,15 +423,57 @@ func (UnimplementedOrchestratorServer) SendHeartbeat(context.Context, *Heartbeat
 func (UnimplementedOrchestratorServer) PullJob(context.Context, *PullJobRequest) (*PullJobResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PullJob not implemented")
 }
+func (UnimplementedOrchestratorServer) StartJob(context.Context, *StartJobRequest) (*StartJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartJob not implemented")
+}
 func (UnimplementedOrchestratorServer) CompleteJob(context.Context, *CompleteJobRequest) (*CompleteJobResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CompleteJob not implemented")
 }
+func (UnimplementedOrchestratorServer) CancelJob(context.Context, *CancelJobRequest) (*CancelJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelJob not implemented")
+}
+func (UnimplementedOrchestratorServer) GetJobHistory(context.Context, *JobHistoryRequest) (*JobHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJobHistory not implemented")
+}
 func (UnimplementedOrchestratorServer) ListJobs(context.Context, *ListJobsRequest) (*ListJobsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListJobs not implemented")
 }
 func (UnimplementedOrchestratorServer) StreamLogs(grpc.BidiStreamingServer[LogEntry, LogAck]) error {
 	return status.Errorf(codes.Unimplemented, "method StreamLogs not implemented")
 }
+func (UnimplementedOrchestratorServer) ShipLogs(grpc.BidiStreamingServer[LogBatch, LogAck]) error {
+	return status.Errorf(codes.Unimplemented, "method ShipLogs not implemented")
+}
+func (UnimplementedOrchestratorServer) GetJobLogs(context.Context, *JobLogsRequest) (*JobLogsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJobLogs not implemented")
+}
+func (UnimplementedOrchestratorServer) TailJobLogs(*JobLogsRequest, grpc.ServerStreamingServer[LogLine]) error {
+	return status.Errorf(codes.Unimplemented, "method TailJobLogs not implemented")
+}
+func (UnimplementedOrchestratorServer) UploadArtifact(grpc.ClientStreamingServer[ArtifactChunk, UploadArtifactResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method UploadArtifact not implemented")
+}
+func (UnimplementedOrchestratorServer) ListArtifacts(context.Context, *ListArtifactsRequest) (*ListArtifactsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListArtifacts not implemented")
+}
+func (UnimplementedOrchestratorServer) DownloadArtifact(*DownloadArtifactRequest, grpc.ServerStreamingServer[ArtifactData]) error {
+	return status.Errorf(codes.Unimplemented, "method DownloadArtifact not implemented")
+}
+func (UnimplementedOrchestratorServer) UploadInput(grpc.ClientStreamingServer[ArtifactChunk, UploadInputResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method UploadInput not implemented")
+}
+func (UnimplementedOrchestratorServer) ListTaskTypes(context.Context, *ListTaskTypesRequest) (*ListTaskTypesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTaskTypes not implemented")
+}
+func (UnimplementedOrchestratorServer) ListWorkers(context.Context, *ListWorkersRequest) (*ListWorkersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWorkers not implemented")
+}
+func (UnimplementedOrchestratorServer) ListCacheEntries(context.Context, *ListCacheEntriesRequest) (*ListCacheEntriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCacheEntries not implemented")
+}
+func (UnimplementedOrchestratorServer) InvalidateCache(context.Context, *InvalidateCacheRequest) (*InvalidateCacheResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InvalidateCache not implemented")
+}
 func (UnimplementedOrchestratorServer) mustEmbedUnimplementedOrchestratorServer() {}
 func (UnimplementedOrchestratorServer) testEmbeddedByValue()                      {}
 
@@ -247,6 +531,17 @@ func _Orchestrator_GetJobStatus_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Orchestrator_WatchJob_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(JobStatusRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OrchestratorServer).WatchJob(m, &grpc.GenericServerStream[JobStatusRequest, JobStatusResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Orchestrator_WatchJobServer = grpc.ServerStreamingServer[JobStatusResponse]
+
 func _Orchestrator_RegisterWorker_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(RegisterWorkerRequest)
 	if err := dec(in); err != nil {
@@ -301,6 +596,24 @@ func _Orchestrator_PullJob_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Orchestrator_StartJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServer).StartJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Orchestrator_StartJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServer).StartJob(ctx, req.(*StartJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Orchestrator_CompleteJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CompleteJobRequest)
 	if err := dec(in); err != nil {
@@ -319,6 +632,42 @@ func _Orchestrator_CompleteJob_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Orchestrator_CancelJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServer).CancelJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Orchestrator_CancelJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServer).CancelJob(ctx, req.(*CancelJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Orchestrator_GetJobHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServer).GetJobHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Orchestrator_GetJobHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServer).GetJobHistory(ctx, req.(*JobHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Orchestrator_ListJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListJobsRequest)
 	if err := dec(in); err != nil {
@@ -344,6 +693,157 @@ func _Orchestrator_StreamLogs_Handler(srv interface{}, stream grpc.ServerStream)
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type Orchestrator_StreamLogsServer = grpc.BidiStreamingServer[LogEntry, LogAck]
 
+func _Orchestrator_ShipLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(OrchestratorServer).ShipLogs(&grpc.GenericServerStream[LogBatch, LogAck]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Orchestrator_ShipLogsServer = grpc.BidiStreamingServer[LogBatch, LogAck]
+
+func _Orchestrator_GetJobLogs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobLogsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServer).GetJobLogs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Orchestrator_GetJobLogs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServer).GetJobLogs(ctx, req.(*JobLogsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Orchestrator_TailJobLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(JobLogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OrchestratorServer).TailJobLogs(m, &grpc.GenericServerStream[JobLogsRequest, LogLine]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Orchestrator_TailJobLogsServer = grpc.ServerStreamingServer[LogLine]
+
+func _Orchestrator_UploadArtifact_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(OrchestratorServer).UploadArtifact(&grpc.GenericServerStream[ArtifactChunk, UploadArtifactResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Orchestrator_UploadArtifactServer = grpc.ClientStreamingServer[ArtifactChunk, UploadArtifactResponse]
+
+func _Orchestrator_ListArtifacts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListArtifactsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServer).ListArtifacts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Orchestrator_ListArtifacts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServer).ListArtifacts(ctx, req.(*ListArtifactsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Orchestrator_DownloadArtifact_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloadArtifactRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OrchestratorServer).DownloadArtifact(m, &grpc.GenericServerStream[DownloadArtifactRequest, ArtifactData]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Orchestrator_DownloadArtifactServer = grpc.ServerStreamingServer[ArtifactData]
+
+func _Orchestrator_UploadInput_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(OrchestratorServer).UploadInput(&grpc.GenericServerStream[ArtifactChunk, UploadInputResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Orchestrator_UploadInputServer = grpc.ClientStreamingServer[ArtifactChunk, UploadInputResponse]
+
+func _Orchestrator_ListTaskTypes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTaskTypesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServer).ListTaskTypes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Orchestrator_ListTaskTypes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServer).ListTaskTypes(ctx, req.(*ListTaskTypesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Orchestrator_ListWorkers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWorkersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServer).ListWorkers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Orchestrator_ListWorkers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServer).ListWorkers(ctx, req.(*ListWorkersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Orchestrator_ListCacheEntries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCacheEntriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServer).ListCacheEntries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Orchestrator_ListCacheEntries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServer).ListCacheEntries(ctx, req.(*ListCacheEntriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Orchestrator_InvalidateCache_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InvalidateCacheRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServer).InvalidateCache(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Orchestrator_InvalidateCache_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServer).InvalidateCache(ctx, req.(*InvalidateCacheRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Orchestrator_ServiceDesc is the grpc.ServiceDesc for Orchestrator service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -371,22 +871,89 @@ var Orchestrator_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "PullJob",
 			Handler:    _Orchestrator_PullJob_Handler,
 		},
+		{
+			MethodName: "StartJob",
+			Handler:    _Orchestrator_StartJob_Handler,
+		},
 		{
 			MethodName: "CompleteJob",
 			Handler:    _Orchestrator_CompleteJob_Handler,
 		},
+		{
+			MethodName: "CancelJob",
+			Handler:    _Orchestrator_CancelJob_Handler,
+		},
+		{
+			MethodName: "GetJobHistory",
+			Handler:    _Orchestrator_GetJobHistory_Handler,
+		},
 		{
 			MethodName: "ListJobs",
 			Handler:    _Orchestrator_ListJobs_Handler,
 		},
+		{
+			MethodName: "GetJobLogs",
+			Handler:    _Orchestrator_GetJobLogs_Handler,
+		},
+		{
+			MethodName: "ListArtifacts",
+			Handler:    _Orchestrator_ListArtifacts_Handler,
+		},
+		{
+			MethodName: "ListTaskTypes",
+			Handler:    _Orchestrator_ListTaskTypes_Handler,
+		},
+		{
+			MethodName: "ListWorkers",
+			Handler:    _Orchestrator_ListWorkers_Handler,
+		},
+		{
+			MethodName: "ListCacheEntries",
+			Handler:    _Orchestrator_ListCacheEntries_Handler,
+		},
+		{
+			MethodName: "InvalidateCache",
+			Handler:    _Orchestrator_InvalidateCache_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchJob",
+			Handler:       _Orchestrator_WatchJob_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "StreamLogs",
 			Handler:       _Orchestrator_StreamLogs_Handler,
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "ShipLogs",
+			Handler:       _Orchestrator_ShipLogs_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "TailJobLogs",
+			Handler:       _Orchestrator_TailJobLogs_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "UploadArtifact",
+			Handler:       _Orchestrator_UploadArtifact_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "DownloadArtifact",
+			Handler:       _Orchestrator_DownloadArtifact_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "UploadInput",
+			Handler:       _Orchestrator_UploadInput_Handler,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "proto/orchestrator.proto",
 }
@@ -1,3 +1,3 @@
 #!/bin/bash
 set -e
-migrate -path ./migrations -database "$DATABASE_URL" down
\ No newline at end of file
+go run ./cmd/scheduler migrate down "${1:-1}"
\ No newline at end of file
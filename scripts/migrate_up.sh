@@ -1,3 +1,3 @@
 #!/bin/bash
 set -e
-migrate -path ./migrations -database "$DATABASE_URL" up
\ No newline at end of file
+go run ./cmd/scheduler migrate up
\ No newline at end of file